@@ -4,11 +4,13 @@ package main
 import (
 	"context"
 	"database/sql"
+	"flag"
 	"fmt"
 	"log"
 	"log/slog"
 	"net/url"
 	"os"
+	"strings"
 
 	"github.com/RyRose/uplog/internal/config"
 	"github.com/RyRose/uplog/internal/sqlc"
@@ -17,14 +19,14 @@ import (
 	"github.com/pressly/goose/v3"
 )
 
-func run(ctx context.Context, args []string) error {
+func run(ctx context.Context, src config.Source, args []string) error {
 	command := args[0]
 	arguments := []string{}
 	if len(args) > 1 {
 		arguments = append(arguments, args[1:]...)
 	}
 
-	cfg, err := config.Load(ctx, "./config/main.lua")
+	cfg, _, err := config.Resolve(ctx, src)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -46,13 +48,31 @@ func run(ctx context.Context, args []string) error {
 }
 
 func main() {
-	args := os.Args[1:]
+	// The database is configured as for uplog, e.g. with UPLOG_DATABASE_PATH.
+	src := config.Source{Overrides: make(map[string]string)}
+	flag.StringVar(&src.Path, "config", "./config/main.lua", "path to the base Lua configuration file")
+	flag.StringVar(&src.Profile, "profile", "", "name of the profile overlay in the profiles directory next to -config, e.g. dev")
+	flag.Func("set", "override a config value as key=value, e.g. database_path=data.db (repeatable)", func(s string) error {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("expected key=value, got %q", s)
+		}
+		src.Overrides[key] = value
+		return nil
+	})
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: goose [flags] COMMAND [COMMAND_ARGS]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
 	if len(args) < 1 {
-		fmt.Println("Usage: goose [OPTIONS] COMMAND [COMMAND_ARGS]")
+		flag.Usage()
 		return
 	}
 	ctx := context.Background()
-	if err := run(ctx, args); err != nil {
+	if err := run(ctx, src, args); err != nil {
 		log.Fatalf("goose: %v", err)
 	}
 }
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/RyRose/uplog/internal/config"
+)
+
+// runConfig implements the `uplog config` subcommands.
+func runConfig(ctx context.Context, src config.Source, args []string) error {
+	if len(args) != 1 {
+		return errors.New("expected exactly one of: check, print, origins, schema")
+	}
+
+	switch args[0] {
+	case "check":
+		cfg, _, err := config.Resolve(ctx, src)
+		if err != nil {
+			return err
+		}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid config %s: %w", src.Path, err)
+		}
+		fmt.Printf("%s: OK\n", src.Path)
+		return nil
+	case "print":
+		cfg, _, err := config.Resolve(ctx, src)
+		if err != nil {
+			return err
+		}
+		return printJSON(config.JSONValue(cfg))
+	case "origins":
+		_, origins, err := config.Resolve(ctx, src)
+		if err != nil {
+			return err
+		}
+		return printJSON(origins)
+	case "schema":
+		schema, err := config.GenerateJSONSchema()
+		if err != nil {
+			return fmt.Errorf("failed to generate schema: %w", err)
+		}
+		fmt.Println(schema)
+		return nil
+	default:
+		return fmt.Errorf("unknown config command %q", args[0])
+	}
+}
+
+// printJSON prints v to stdout as indented JSON.
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	return nil
+}
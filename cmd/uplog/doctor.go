@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/doctor"
+)
+
+// runDoctor implements `uplog doctor`, which reports integrity problems and
+// orphaned rows, and `uplog doctor fix`, which applies one of the suggested
+// fixes.
+func runDoctor(ctx context.Context, src config.Source, args []string) error {
+	var fix *doctor.Fix
+	switch {
+	case len(args) == 0:
+	case args[0] == "fix" && (len(args) == 5 || len(args) == 6):
+		action, err := doctor.ParseAction(args[4])
+		if err != nil {
+			return err
+		}
+		fix = &doctor.Fix{Table: args[1], Column: args[2], Value: args[3], Action: action}
+		if len(args) == 6 {
+			fix.Target = args[5]
+		}
+	default:
+		return errors.New("expected no arguments or fix <table> <column> <value> <action> [target]")
+	}
+
+	cfg, _, err := config.Resolve(ctx, src)
+	if err != nil {
+		return err
+	}
+	state, err := config.NewState(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = state.Close() }()
+
+	if fix != nil {
+		n, err := doctor.Apply(ctx, state.WDB, *fix)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: changed %d rows\n", fix.Action, n)
+		return nil
+	}
+
+	report, err := doctor.Examine(ctx, state.RDB)
+	if err != nil {
+		return err
+	}
+	printReport(os.Stdout, report)
+	if !report.OK() {
+		return errors.New("problems found")
+	}
+	return nil
+}
+
+func printReport(w io.Writer, report *doctor.Report) {
+	if report.OK() {
+		fmt.Fprintln(w, "no problems found")
+		return
+	}
+	for _, problem := range report.Integrity {
+		fmt.Fprintf(w, "integrity: %s\n", problem)
+	}
+	if report.Violations > 0 {
+		fmt.Fprintf(w, "%d rows violate foreign keys:\n", report.Violations)
+	}
+	for _, o := range report.Orphans {
+		fmt.Fprintf(w, "\n%d rows of %s.%s reference missing %s.%s %q\n",
+			o.Count, o.Table, o.Column, o.Parent, o.ParentColumn, o.Value)
+		if o.Suggestion != "" {
+			fmt.Fprintf(w, "  uplog doctor fix %q %q %q %s %q\n",
+				o.Table, o.Column, o.Value, doctor.Reassign, o.Suggestion)
+		} else {
+			fmt.Fprintf(w, "  uplog doctor fix %q %q %q %s <%s>\n",
+				o.Table, o.Column, o.Value, doctor.Reassign, o.ParentColumn)
+		}
+		if o.Nullable {
+			fmt.Fprintf(w, "  uplog doctor fix %q %q %q %s\n", o.Table, o.Column, o.Value, doctor.Clear)
+		}
+		fmt.Fprintf(w, "  uplog doctor fix %q %q %q %s\n", o.Table, o.Column, o.Value, doctor.Delete)
+	}
+}
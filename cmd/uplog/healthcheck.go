@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/RyRose/uplog/internal/config"
+)
+
+// runHealthcheck implements `uplog healthcheck`, which is used as the Docker
+// HEALTHCHECK. It requests /readyz from the running server and fails unless
+// the server is ready.
+func runHealthcheck(ctx context.Context, src config.Source, args []string) error {
+	var url string
+	switch len(args) {
+	case 0:
+		cfg, _, err := config.Resolve(ctx, src)
+		if err != nil {
+			return err
+		}
+		url = "http://" + net.JoinHostPort("localhost", cfg.Port) + "/readyz"
+	case 1:
+		url = args[0]
+	default:
+		return errors.New("expected at most one url")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if _, err := io.Copy(os.Stdout, resp.Body); err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return nil
+}
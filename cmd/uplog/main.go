@@ -2,16 +2,45 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 
+	"github.com/RyRose/uplog/internal/config"
 	"github.com/RyRose/uplog/internal/service"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
+const usage = `Usage: uplog [flags] [command]
+
+Commands:
+  (none)         run the server
+  config check   load and validate the configuration
+  config print   print the resolved configuration as JSON, as described by
+                 config schema, leaving out secrets
+  config origins print the layer each configuration value was set by
+  config schema  print the JSON Schema of the configuration
+  doctor         check integrity and foreign keys and suggest fixes
+  doctor fix <table> <column> <value> <action> [target]
+                 fix rows of table whose column references the missing
+                 value; action is delete, clear or reassign to target
+  healthcheck [url]
+                 exit non-zero unless the server's /readyz reports ready;
+                 url defaults to the configured port on localhost
+
+Configuration is resolved with the precedence env > flag > profile > base.
+Environment overrides are named UPLOG_<KEY>, e.g. UPLOG_PORT, and
+UPLOG_PROFILE selects the profile. Lists are given comma-separated, e.g.
+UPLOG_AUDIT_TRUSTED_PROXIES=10.0.0.1,10.0.0.2.
+
+Flags:
+`
+
 // @title				Uplog API
 // @version			1.0
 // @description		A workout tracking and management system with progress logging, routine management, and workout scheduling.
@@ -32,13 +61,56 @@ import (
 //
 // @tag.name			rawdata
 // @tag.description	CRUD operations for raw data entities (lifts, workouts, progress, etc.)
+//
+// @tag.name			admin
+// @tag.description	Database maintenance pages
+//
+// @tag.name			health
+// @tag.description	Liveness and readiness checks
 func main() {
+	src := config.Source{Overrides: make(map[string]string)}
+	flag.StringVar(&src.Path, "config", "./config/main.lua", "path to the base Lua configuration file")
+	flag.StringVar(&src.Profile, "profile", "", "name of the profile overlay in the profiles directory next to -config, e.g. dev")
+	flag.Func("set", "override a config value as key=value, e.g. port=9090 (repeatable)", func(s string) error {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("expected key=value, got %q", s)
+		}
+		src.Overrides[key] = value
+		return nil
+	})
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
 	ctx := context.Background()
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
 	defer cancel()
 
-	if err := service.Run(ctx, "./config/main.lua"); err != nil {
-		log.Fatalf("server failed to run: %v", err)
+	args := flag.Args()
+	if len(args) == 0 {
+		if err := service.Run(ctx, src); err != nil {
+			log.Fatalf("server failed to run: %v", err)
+		}
+		slog.InfoContext(ctx, "server exited gracefully")
+		return
+	}
+
+	var err error
+	switch args[0] {
+	case "config":
+		err = runConfig(ctx, src, args[1:])
+	case "doctor":
+		err = runDoctor(ctx, src, args[1:])
+	case "healthcheck":
+		err = runHealthcheck(ctx, src, args[1:])
+	default:
+		flag.Usage()
+		err = fmt.Errorf("unknown command %q", args[0])
+	}
+	if err != nil {
+		log.Fatalf("%s: %v", args[0], err)
 	}
-	slog.InfoContext(ctx, "server exited gracefully")
 }
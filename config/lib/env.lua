@@ -1,6 +1,6 @@
 -- Utility functions for configuration.
 
-local types = require("config.lib.types")
+local types = require("lib.types")
 
 local M = {}
 
@@ -3,10 +3,10 @@ describe("env", function()
 	local types
 
 	before_each(function()
-		package.loaded["config.env"] = nil
-		package.loaded["config.types"] = nil
-		types = require("config.lib.types")
-		env = require("config.lib.env")
+		package.loaded["lib.env"] = nil
+		package.loaded["lib.types"] = nil
+		types = require("lib.types")
+		env = require("lib.env")
 	end)
 
 	describe("Or", function()
@@ -11,13 +11,18 @@
 -- fields are expunged. Sensitive fields should be marked with `json:"-"` struct
 -- tags.
 ---@class Data
+-- mode specifies the deployment mode: "dev", "test" or "prod". Dev-only
+-- features such as the API docs and debug logging are disabled in "prod".
+---@field mode string
 -- debug enables debug mode. When enabled, the application will log additional
--- debug information.
+-- debug information. Ignored when mode is "prod".
 ---@field debug boolean
 -- version specifies the application version.
 ---@field version? string
 -- database_path specifies the path to the database file.
 ---@field database_path string
+-- database configures the SQLite connections to the database file.
+---@field database Database
 -- port specifies the port on which the application will run.
 ---@field port string
 -- swagger_url specifies the URL for the Swagger documentation.
@@ -25,3 +30,85 @@
 -- first_day_of_week specifies the first day of the week (0 = Sunday, 1 =
 -- Monday, ...).
 ---@field first_day_of_week number
+-- tracing configures OpenTelemetry tracing.
+---@field tracing Tracing
+-- training_metrics configures the lifting progress metrics exported on
+-- /metrics.
+---@field training_metrics TrainingMetrics
+-- server configures HTTP server timeouts and graceful shutdown.
+---@field server Server
+-- trash configures how long deleted rows can be restored.
+---@field trash Trash
+-- undo configures how many changes can be undone.
+---@field undo Undo
+-- audit configures the audit log of data table changes.
+---@field audit Audit
+
+-- Database configures the SQLite connection pools and the pragmas applied to
+-- every connection. Zero values keep the SQLite defaults.
+---@class Database
+-- read_pool_size bounds the number of read-only connections. Zero uses the
+-- number of CPUs. Writes always use a single connection.
+---@field read_pool_size number
+-- busy_timeout_millis specifies how long a connection waits on a lock held by
+-- another connection before failing with SQLITE_BUSY.
+---@field busy_timeout_millis number
+-- synchronous sets how often SQLite syncs to disk: "OFF", "NORMAL", "FULL" or
+-- "EXTRA". "NORMAL" is durable across application crashes in WAL mode.
+---@field synchronous string
+-- cache_size_kb sets the page cache size of each connection in KiB.
+---@field cache_size_kb number
+-- mmap_size_bytes sets how many bytes of the database file each connection may
+-- memory-map.
+---@field mmap_size_bytes number
+
+-- Tracing configures how OpenTelemetry spans are exported.
+---@class Tracing
+-- exporter selects where spans are exported: "none", "stdout" or "otlpfile".
+-- "otlpfile" appends OTLP JSON lines to Path so traces can be collected
+-- offline. Empty is the same as "none".
+---@field exporter string
+-- path specifies the file spans are appended to by the "otlpfile" exporter.
+---@field path string
+
+-- TrainingMetrics configures the per-lift e1RM, top weight and weekly volume
+-- gauges computed from logged progress.
+---@class TrainingMetrics
+-- enabled exports the training metrics on /metrics.
+---@field enabled boolean
+-- cache_seconds specifies how long computed metrics are reused between scrapes
+-- before progress is read again.
+---@field cache_seconds number
+
+-- Server configures the HTTP server. Zero values disable the corresponding
+-- timeout.
+---@class Server
+-- read_header_timeout_seconds specifies how long reading request headers may
+-- take.
+---@field read_header_timeout_seconds number
+-- idle_timeout_seconds specifies how long idle keep-alive connections are kept
+-- open.
+---@field idle_timeout_seconds number
+-- drain_timeout_seconds specifies how long in-flight requests may take to
+-- finish on shutdown before their connections are closed.
+---@field drain_timeout_seconds number
+
+-- Trash configures the trash that rows removed by deletes are moved to.
+---@class Trash
+-- retention_days specifies how many days deleted rows are kept before they are
+-- purged. Zero keeps them forever.
+---@field retention_days number
+
+-- Undo configures the change history used to undo the changes of a session.
+---@class Undo
+-- limit specifies how many of the most recent changes of each session can be
+-- undone. Zero uses 10.
+---@field limit number
+
+-- Audit configures the audit log of the changes made through the data tables.
+---@class Audit
+-- trusted_proxies lists the IP addresses or CIDR prefixes of the authenticating
+-- proxies whose Remote-User or X-Forwarded-User header names the user a change
+-- is recorded for. The headers of other clients are ignored since any client
+-- can set them. Empty records no users.
+---@field trusted_proxies string[]
@@ -1,18 +1,52 @@
-local env = require("config.lib.env")
-local types = require("config.lib.types")
+local env = require("lib.env")
+local types = require("lib.types")
 
-local port = env.Or("PORT", "8080")
 local curtime = os.time()
-local version = env.Or("VERSION", "auto-" .. tostring(curtime))
+-- Read here as well as by the UPLOG_ overrides so that the swagger URL is
+-- versioned with it.
+local version = env.Or("UPLOG_VERSION", "auto-" .. tostring(curtime))
 
 ---@type Data
 local M = {
-	debug = env.Or("DEBUG", false),
+	-- Dev-only features such as the API docs stay disabled unless a profile
+	-- enables them, e.g. with -profile dev.
+	mode = "prod",
+	debug = false,
 	version = version,
-	database_path = env.Or("DATABASE_PATH", "./tmp/db/data.db"),
-	port = port,
-	swagger_url = env.Or("SWAGGER_URL", "http://localhost:" .. port .. "/docs/swagger.json") .. "?v=" .. version,
+	database_path = "./tmp/db/data.db",
+	database = {
+		read_pool_size = 4,
+		busy_timeout_millis = 5000,
+		synchronous = "NORMAL",
+		cache_size_kb = 16384,
+		mmap_size_bytes = 268435456,
+	},
+	port = "8080",
+	-- Relative so that it follows the port however it is overridden.
+	swagger_url = "/docs/swagger.json?v=" .. version,
 	first_day_of_week = 0,
+	tracing = {
+		exporter = "none",
+		path = "./tmp/traces/traces.jsonl",
+	},
+	training_metrics = {
+		enabled = false,
+		cache_seconds = 60,
+	},
+	server = {
+		read_header_timeout_seconds = 10,
+		idle_timeout_seconds = 120,
+		drain_timeout_seconds = 15,
+	},
+	trash = {
+		retention_days = 30,
+	},
+	undo = {
+		limit = 10,
+	},
+	audit = {
+		trusted_proxies = {},
+	},
 }
 
 return M
@@ -2,7 +2,7 @@ describe("main", function()
 	local main
 
 	before_each(function()
-		package.loaded["config.main"] = nil
+		package.loaded["main"] = nil
 	end)
 
 	describe("structural snapshot", function()
@@ -11,7 +11,7 @@ describe("main", function()
 			os.getenv = function()
 				return nil
 			end
-			main = require("config.main")
+			main = require("main")
 
 			-- Version will be auto-generated with timestamp, so we need to check it exists
 			assert.is_not_nil(main.version)
@@ -19,38 +19,71 @@ describe("main", function()
 			assert.is_truthy(main.version:match("^auto%-"))
 
 			-- Swagger URL should include version query param
-			local expected_swagger_prefix = "http://localhost:8080/docs/swagger.json?v=auto-"
+			local expected_swagger_prefix = "/docs/swagger.json?v=auto-"
 			assert.is_truthy(main.swagger_url:sub(1, #expected_swagger_prefix) == expected_swagger_prefix)
 
 			-- Check other fields with exact values
+			assert.equal("prod", main.mode)
 			assert.equal(false, main.debug)
 			assert.equal("./tmp/db/data.db", main.database_path)
 			assert.equal("8080", main.port)
 		end)
 
-		it("should match expected structure with all env vars set", function()
+		it("should only read the version from the env vars it is given", function()
 			--- @diagnostic disable-next-line: duplicate-set-field
 			os.getenv = function(key)
 				if key == "DEBUG" then
 					return "true"
-				elseif key == "VERSION" then
+				elseif key == "UPLOG_VERSION" then
 					return "1.0.0"
+				elseif key == "VERSION" or key == "SWAGGER_URL" then
+					return "unprefixed"
 				elseif key == "DATABASE_PATH" then
 					return "/var/db/data.db"
 				elseif key == "PORT" then
 					return "3000"
 				end
 			end
-			main = require("config.main")
+			main = require("main")
 
 			---@type Data
 			local expected = {
-				debug = true,
+				mode = "prod",
+				debug = false,
 				version = "1.0.0",
-				database_path = "/var/db/data.db",
-				port = "3000",
-				swagger_url = "http://localhost:3000/docs/swagger.json?v=1.0.0",
+				database_path = "./tmp/db/data.db",
+				database = {
+					read_pool_size = 4,
+					busy_timeout_millis = 5000,
+					synchronous = "NORMAL",
+					cache_size_kb = 16384,
+					mmap_size_bytes = 268435456,
+				},
+				port = "8080",
+				swagger_url = "/docs/swagger.json?v=1.0.0",
 				first_day_of_week = 0,
+				tracing = {
+					exporter = "none",
+					path = "./tmp/traces/traces.jsonl",
+				},
+				training_metrics = {
+					enabled = false,
+					cache_seconds = 60,
+				},
+				server = {
+					read_header_timeout_seconds = 10,
+					idle_timeout_seconds = 120,
+					drain_timeout_seconds = 15,
+				},
+				trash = {
+					retention_days = 30,
+				},
+				undo = {
+					limit = 10,
+				},
+				audit = {
+					trusted_proxies = {},
+				},
 			}
 
 			assert.same(expected, main)
@@ -0,0 +1,10 @@
+-- Overlay for local development. Enables the API docs, debug logging and
+-- writes traces to the file configured in main.lua.
+
+return {
+	mode = "dev",
+	debug = true,
+	tracing = {
+		exporter = "otlpfile",
+	},
+}
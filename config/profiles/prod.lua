@@ -0,0 +1,6 @@
+-- Overlay for production deployments. Dev-only features are disabled.
+
+return {
+	mode = "prod",
+	debug = false,
+}
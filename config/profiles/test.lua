@@ -0,0 +1,6 @@
+-- Overlay for automated tests. Enables the API docs without debug logging.
+
+return {
+	mode = "test",
+	debug = false,
+}
@@ -48,1603 +48,72 @@ const docTemplate = `{
                 }
             }
         },
-        "/data": {
-            "get": {
-                "description": "Renders the main index page with specified tab and CSS query parameters",
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "index"
-                ],
-                "summary": "Get index page",
-                "responses": {
-                    "200": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/data/{tabX}/{tabY}": {
-            "get": {
-                "description": "Renders the main index page with specified tab and CSS query parameters",
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "index"
-                ],
-                "summary": "Get index page",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Tab X parameter",
-                        "name": "tabX",
-                        "in": "path"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Tab Y parameter",
-                        "name": "tabY",
-                        "in": "path"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/lift": {
-            "get": {
-                "description": "Renders a paginated table view of lifts with their details",
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Get lift data table view",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Creates a new lift entry in the database",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Create new lift",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Lift ID",
-                        "name": "id",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Lift link",
-                        "name": "link",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Default side weight",
-                        "name": "default_side_weight",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Notes",
-                        "name": "notes",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Lift group",
-                        "name": "lift_group",
-                        "in": "formData"
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/lift/{id}": {
-            "delete": {
-                "description": "Deletes a lift entry by ID",
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Delete lift",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Lift ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "patch": {
-                "description": "Updates specific fields of a lift entry by ID",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Update lift data",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Lift ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "New lift ID",
-                        "name": "id",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Lift link",
-                        "name": "link",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Default side weight",
-                        "name": "default_side_weight",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Notes",
-                        "name": "notes",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Lift group",
-                        "name": "lift_group",
-                        "in": "formData"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/lift_group": {
-            "get": {
-                "description": "Renders a paginated table view of lift groups",
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Get lift group data table view",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Creates a new lift group entry in the database",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Create new lift group",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Lift group ID",
-                        "name": "id",
-                        "in": "formData",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/lift_group/{id}": {
-            "delete": {
-                "description": "Deletes a lift group entry by ID",
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Delete lift group",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Lift group ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "patch": {
-                "description": "Updates the ID of a lift group entry",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Update lift group data",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Lift group ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "New lift group ID",
-                        "name": "id",
-                        "in": "formData"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/lift_muscle_mapping": {
-            "get": {
-                "description": "Renders a paginated table view of lift-muscle-movement mappings",
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Get lift muscle mapping data table view",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Creates a new lift-muscle-movement mapping in the database",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Create new lift muscle mapping",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Lift ID",
-                        "name": "lift",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Muscle ID",
-                        "name": "muscle",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Movement ID",
-                        "name": "movement",
-                        "in": "formData",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/lift_muscle_mapping/{lift}/{muscle}/{movement}": {
-            "delete": {
-                "description": "Deletes a lift-muscle-movement mapping",
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Delete lift muscle mapping",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Lift ID",
-                        "name": "lift",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Muscle ID",
-                        "name": "muscle",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Movement ID",
-                        "name": "movement",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "patch": {
-                "description": "Updates specific fields of a lift-muscle-movement mapping",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Update lift muscle mapping data",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Lift ID",
-                        "name": "lift",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Muscle ID",
-                        "name": "muscle",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Movement ID",
-                        "name": "movement",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "New lift ID",
-                        "name": "lift",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "New muscle ID",
-                        "name": "muscle",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "New movement ID",
-                        "name": "movement",
-                        "in": "formData"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/lift_workout_mapping": {
-            "get": {
-                "description": "Renders a paginated table view of lift-workout mappings",
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Get lift workout mapping data table view",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Creates a new lift-workout mapping in the database",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Create new lift workout mapping",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Lift ID",
-                        "name": "lift",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workout ID",
-                        "name": "workout",
-                        "in": "formData",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/lift_workout_mapping/{lift}/{workout}": {
-            "delete": {
-                "description": "Deletes a lift-workout mapping",
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Delete lift workout mapping",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Lift ID",
-                        "name": "lift",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workout ID",
-                        "name": "workout",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "patch": {
-                "description": "Updates specific fields of a lift-workout mapping",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Update lift workout mapping data",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Lift ID",
-                        "name": "lift",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workout ID",
-                        "name": "workout",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "New lift ID",
-                        "name": "lift",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "New workout ID",
-                        "name": "workout",
-                        "in": "formData"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/movement": {
-            "get": {
-                "description": "Renders a paginated table view of movements",
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Get movement data table view",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Creates a new movement entry in the database",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Create new movement",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Movement ID",
-                        "name": "id",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Movement alias",
-                        "name": "alias",
-                        "in": "formData",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/movement/{id}": {
-            "delete": {
-                "description": "Deletes a movement entry by ID",
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Delete movement",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Movement ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "patch": {
-                "description": "Updates specific fields of a movement entry by ID",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Update movement data",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Movement ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "New movement ID",
-                        "name": "id",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Movement alias",
-                        "name": "alias",
-                        "in": "formData"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/muscle": {
-            "get": {
-                "description": "Renders a paginated table view of muscles",
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Get muscle data table view",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Creates a new muscle entry in the database",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Create new muscle",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Muscle ID",
-                        "name": "id",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Muscle link",
-                        "name": "link",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Message",
-                        "name": "message",
-                        "in": "formData"
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/muscle/{id}": {
-            "delete": {
-                "description": "Deletes a muscle entry by ID",
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Delete muscle",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Muscle ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "patch": {
-                "description": "Updates specific fields of a muscle entry by ID",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Update muscle data",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Muscle ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "New muscle ID",
-                        "name": "id",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Muscle link",
-                        "name": "link",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Message",
-                        "name": "message",
-                        "in": "formData"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/progress": {
-            "get": {
-                "description": "Renders a paginated table view of progress entries",
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Get progress data table view",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Creates a new progress entry in the database",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Create new progress entry",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Lift ID",
-                        "name": "lift",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Date",
-                        "name": "date",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "Weight",
-                        "name": "weight",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of sets",
-                        "name": "sets",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of reps",
-                        "name": "reps",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Side weight",
-                        "name": "side_weight",
-                        "in": "formData"
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/progress/{id}": {
-            "delete": {
-                "description": "Deletes a progress entry by ID",
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Delete progress entry",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Progress ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "patch": {
-                "description": "Updates specific fields of a progress entry by ID",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Update progress data",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Progress ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Lift ID",
-                        "name": "lift",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Date",
-                        "name": "date",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Weight",
-                        "name": "weight",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of sets",
-                        "name": "sets",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of reps",
-                        "name": "reps",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Side weight",
-                        "name": "side_weight",
-                        "in": "formData"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/routine": {
+        "/api/data/{table}": {
             "get": {
-                "description": "Renders a paginated table view of routines",
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Get routine data table view",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Creates a new routine entry in the database",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
+                "description": "Lists the rows of a data table as objects keyed by column",
                 "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Create new routine",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Routine ID",
-                        "name": "id",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Routine steps",
-                        "name": "steps",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Lift ID",
-                        "name": "lift",
-                        "in": "formData",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "HTML content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/data/routine/{id}": {
-            "delete": {
-                "description": "Deletes a routine entry by ID",
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Delete routine",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Routine ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "patch": {
-                "description": "Updates specific fields of a routine entry by ID",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
+                    "application/json"
                 ],
                 "tags": [
-                    "rawdata"
+                    "api"
                 ],
-                "summary": "Update routine data",
+                "summary": "List data table rows",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Routine ID",
-                        "name": "id",
+                        "enum": [
+                            "lift",
+                            "movement",
+                            "muscle",
+                            "routine",
+                            "workout",
+                            "template_variable",
+                            "lift_group",
+                            "side_weight",
+                            "progress",
+                            "subworkout",
+                            "routine_workout_mapping",
+                            "lift_muscle_mapping",
+                            "lift_workout_mapping"
+                        ],
+                        "type": "string",
+                        "description": "Data table",
+                        "name": "table",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "New routine ID",
-                        "name": "id",
-                        "in": "formData"
+                        "description": "Free text search",
+                        "name": "q",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Routine steps",
-                        "name": "steps",
-                        "in": "formData"
+                        "description": "Column to sort by, prefixed with - to reverse",
+                        "name": "sort",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Lift ID",
-                        "name": "lift",
-                        "in": "formData"
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Rows",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "type": "object"
+                            }
                         }
                     },
                     "400": {
@@ -1660,31 +129,57 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/view/data/routine_workout_mapping": {
-            "get": {
-                "description": "Renders a paginated table view of routine-workout mappings",
+            },
+            "post": {
+                "description": "Creates a row of a data table from an object keyed by column. Generated IDs are left out",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
-                    "text/html"
+                    "application/json"
                 ],
                 "tags": [
-                    "rawdata"
+                    "api"
                 ],
-                "summary": "Get routine workout mapping data table view",
+                "summary": "Create data table row",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
+                        "enum": [
+                            "lift",
+                            "movement",
+                            "muscle",
+                            "routine",
+                            "workout",
+                            "template_variable",
+                            "lift_group",
+                            "side_weight",
+                            "progress",
+                            "subworkout",
+                            "routine_workout_mapping",
+                            "lift_muscle_mapping",
+                            "lift_workout_mapping"
+                        ],
+                        "type": "string",
+                        "description": "Data table",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Row",
+                        "name": "row",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "HTML content",
+                    "201": {
+                        "description": "Row",
                         "schema": {
-                            "type": "string"
+                            "type": "object"
                         }
                     },
                     "400": {
@@ -1693,6 +188,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "422": {
+                        "description": "Problems with the fields",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1700,44 +201,58 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Creates a new routine-workout mapping in the database",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
+            }
+        },
+        "/api/data/{table}/{key}": {
+            "get": {
+                "description": "Gets a row of a data table with its version as the ETag. The row is identified by one path segment per primary key column",
                 "produces": [
-                    "text/html"
+                    "application/json"
                 ],
                 "tags": [
-                    "rawdata"
+                    "api"
                 ],
-                "summary": "Create new routine workout mapping",
+                "summary": "Get data table row",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Routine ID",
-                        "name": "routine",
-                        "in": "formData",
+                        "enum": [
+                            "lift",
+                            "movement",
+                            "muscle",
+                            "routine",
+                            "workout",
+                            "template_variable",
+                            "lift_group",
+                            "side_weight",
+                            "progress",
+                            "subworkout",
+                            "routine_workout_mapping",
+                            "lift_muscle_mapping",
+                            "lift_workout_mapping"
+                        ],
+                        "type": "string",
+                        "description": "Data table",
+                        "name": "table",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Workout ID",
-                        "name": "workout",
-                        "in": "formData",
+                        "description": "Primary key of the row",
+                        "name": "key",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "HTML content",
+                    "200": {
+                        "description": "Row",
                         "schema": {
-                            "type": "string"
+                            "type": "object"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "404": {
+                        "description": "Row not found",
                         "schema": {
                             "type": "string"
                         }
@@ -1749,44 +264,75 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/view/data/routine_workout_mapping/{routine}/{workout}": {
+            },
             "delete": {
-                "description": "Deletes a routine-workout mapping",
+                "description": "Deletes a row of a data table. Rows with a text ID are moved to the trash unless other rows depend on them and cascade is not set",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "rawdata"
+                    "api"
                 ],
-                "summary": "Delete routine workout mapping",
+                "summary": "Delete data table row",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Routine ID",
-                        "name": "routine",
+                        "enum": [
+                            "lift",
+                            "movement",
+                            "muscle",
+                            "routine",
+                            "workout",
+                            "template_variable",
+                            "lift_group",
+                            "side_weight",
+                            "progress",
+                            "subworkout",
+                            "routine_workout_mapping",
+                            "lift_muscle_mapping",
+                            "lift_workout_mapping"
+                        ],
+                        "type": "string",
+                        "description": "Data table",
+                        "name": "table",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Workout ID",
-                        "name": "workout",
+                        "description": "Primary key of the row",
+                        "name": "key",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also remove the rows depending on it",
+                        "name": "cascade",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "Deleted",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "404": {
+                        "description": "Not found",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "409": {
+                        "description": "Rows a cascading delete would remove",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1796,47 +342,68 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Updates specific fields of a routine-workout mapping",
+                "description": "Updates the given fields of a row of a data table in one transaction",
                 "consumes": [
-                    "application/x-www-form-urlencoded"
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "rawdata"
+                    "api"
                 ],
-                "summary": "Update routine workout mapping data",
+                "summary": "Update data table row",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Routine ID",
-                        "name": "routine",
+                        "enum": [
+                            "lift",
+                            "movement",
+                            "muscle",
+                            "routine",
+                            "workout",
+                            "template_variable",
+                            "lift_group",
+                            "side_weight",
+                            "progress",
+                            "subworkout",
+                            "routine_workout_mapping",
+                            "lift_muscle_mapping",
+                            "lift_workout_mapping"
+                        ],
+                        "type": "string",
+                        "description": "Data table",
+                        "name": "table",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Workout ID",
-                        "name": "workout",
+                        "description": "Primary key of the row",
+                        "name": "key",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "New routine ID",
-                        "name": "routine",
-                        "in": "formData"
+                        "description": "Fields to update",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "New workout ID",
-                        "name": "workout",
-                        "in": "formData"
+                        "description": "Version of the row the patch is based on",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Updated row",
                         "schema": {
-                            "type": "string"
+                            "type": "object"
                         }
                     },
                     "400": {
@@ -1845,6 +412,24 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Row not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Stored row if it changed since that version",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "422": {
+                        "description": "Problems with the fields",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1854,24 +439,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/view/data/side_weight": {
+        "/data": {
             "get": {
-                "description": "Renders a paginated table view of side weights",
+                "description": "Renders the main index page with specified tab and CSS query parameters",
                 "produces": [
                     "text/html"
                 ],
                 "tags": [
-                    "rawdata"
-                ],
-                "summary": "Get side weight data table view",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "index"
                 ],
+                "summary": "Get index page",
                 "responses": {
                     "200": {
                         "description": "HTML content",
@@ -1879,12 +456,6 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1892,62 +463,39 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Creates a new side weight entry in the database",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
+            }
+        },
+        "/data/{tabX}/{tabY}": {
+            "get": {
+                "description": "Renders the main index page with specified tab and CSS query parameters",
                 "produces": [
                     "text/html"
                 ],
                 "tags": [
-                    "rawdata"
+                    "index"
                 ],
-                "summary": "Create new side weight",
+                "summary": "Get index page",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Side weight ID",
-                        "name": "id",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "Multiplier value",
-                        "name": "multiplier",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "Addend value",
-                        "name": "addend",
-                        "in": "formData",
-                        "required": true
+                        "description": "Tab X parameter",
+                        "name": "tabX",
+                        "in": "path"
                     },
                     {
                         "type": "string",
-                        "description": "Format string",
-                        "name": "format",
-                        "in": "formData",
-                        "required": true
+                        "description": "Tab Y parameter",
+                        "name": "tabY",
+                        "in": "path"
                     }
                 ],
                 "responses": {
-                    "201": {
+                    "200": {
                         "description": "HTML content",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1957,17 +505,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/view/data/side_weight/{id}": {
-            "delete": {
-                "description": "Deletes a side weight entry by ID",
+        "/healthz": {
+            "get": {
+                "description": "Reports that the process is running",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "rawdata"
+                    "health"
                 ],
-                "summary": "Delete side weight",
+                "summary": "Liveness check",
+                "responses": {
+                    "200": {
+                        "description": "Alive",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/lift/{id}": {
+            "get": {
+                "description": "Renders the index page showing everything about a lift",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "index"
+                ],
+                "summary": "Get lift page",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Side weight ID",
+                        "description": "Lift ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1975,7 +546,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "HTML content",
                         "schema": {
                             "type": "string"
                         }
@@ -1987,52 +558,153 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Updates specific fields of a side weight entry by ID",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Checks the databases, migrations, free disk space, write-ahead log size and whether the server is shutting down",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness check",
+                "responses": {
+                    "200": {
+                        "description": "Ready",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Not ready",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/view/audit": {
+            "get": {
+                "description": "Renders a page of the changes made through the data tables, most recent first",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get audit log view",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only show changes of this table",
+                        "name": "table",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only show changes on or after this date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only show changes on or before this date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number starting at 0",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HTML content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/view/data/{table}": {
+            "get": {
+                "description": "Renders a paginated table view of the rows of a data table, selected by the query of the page URL in the HX-Current-URL header",
+                "produces": [
+                    "text/html"
                 ],
                 "tags": [
                     "rawdata"
                 ],
-                "summary": "Update side weight data",
+                "summary": "Get data table view",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Side weight ID",
-                        "name": "id",
+                        "enum": [
+                            "lift",
+                            "movement",
+                            "muscle",
+                            "routine",
+                            "workout",
+                            "template_variable",
+                            "lift_group",
+                            "side_weight",
+                            "progress",
+                            "subworkout",
+                            "routine_workout_mapping",
+                            "lift_muscle_mapping",
+                            "lift_workout_mapping"
+                        ],
+                        "type": "string",
+                        "description": "Data table",
+                        "name": "table",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "New side weight ID",
-                        "name": "id",
-                        "in": "formData"
+                        "description": "Free text search",
+                        "name": "q",
+                        "in": "query"
                     },
                     {
-                        "type": "number",
-                        "description": "Multiplier value",
-                        "name": "multiplier",
-                        "in": "formData"
+                        "type": "string",
+                        "description": "Column to sort by, prefixed with - to reverse",
+                        "name": "sort",
+                        "in": "query"
                     },
                     {
-                        "type": "number",
-                        "description": "Addend value",
-                        "name": "addend",
-                        "in": "formData"
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Format string",
-                        "name": "format",
-                        "in": "formData"
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "HTML content",
                         "schema": {
                             "type": "string"
                         }
@@ -2050,28 +722,45 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/view/data/subworkout": {
-            "get": {
-                "description": "Renders a paginated table view of subworkout-superworkout relationships",
+            },
+            "post": {
+                "description": "Creates a row of a data table from form fields named after its columns and renders it. Generated IDs are left out. Mapping table columns given several values create a row for each in one transaction",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
                 "produces": [
                     "text/html"
                 ],
                 "tags": [
                     "rawdata"
                 ],
-                "summary": "Get subworkout data table view",
+                "summary": "Create data table row",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
+                        "enum": [
+                            "lift",
+                            "movement",
+                            "muscle",
+                            "routine",
+                            "workout",
+                            "template_variable",
+                            "lift_group",
+                            "side_weight",
+                            "progress",
+                            "subworkout",
+                            "routine_workout_mapping",
+                            "lift_muscle_mapping",
+                            "lift_workout_mapping"
+                        ],
+                        "type": "string",
+                        "description": "Data table",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
+                    "201": {
                         "description": "HTML content",
                         "schema": {
                             "type": "string"
@@ -2083,6 +772,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "422": {
+                        "description": "HTML content of the new row with the invalid fields",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2090,42 +785,69 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Creates a new subworkout-superworkout relationship in the database",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
+            }
+        },
+        "/view/data/{table}/{id}/rename": {
+            "get": {
+                "description": "Renders a dialog confirming the rename of a row with a text ID with the number of rows referencing it",
                 "produces": [
                     "text/html"
                 ],
                 "tags": [
                     "rawdata"
                 ],
-                "summary": "Create new subworkout relationship",
+                "summary": "Confirm data table row rename",
                 "parameters": [
+                    {
+                        "enum": [
+                            "lift",
+                            "movement",
+                            "muscle",
+                            "routine",
+                            "workout",
+                            "template_variable",
+                            "lift_group",
+                            "side_weight",
+                            "progress",
+                            "subworkout",
+                            "routine_workout_mapping",
+                            "lift_muscle_mapping",
+                            "lift_workout_mapping"
+                        ],
+                        "type": "string",
+                        "description": "Data table",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
-                        "description": "Subworkout ID",
-                        "name": "subworkout",
-                        "in": "formData",
+                        "description": "Row ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Superworkout ID",
-                        "name": "superworkout",
-                        "in": "formData",
+                        "description": "New row ID",
+                        "name": "id",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
+                    "200": {
                         "description": "HTML content",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "204": {
+                        "description": "ID unchanged",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "400": {
                         "description": "Bad request",
                         "schema": {
@@ -2141,38 +863,59 @@ const docTemplate = `{
                 }
             }
         },
-        "/view/data/subworkout/{subworkout}/{superworkout}": {
+        "/view/data/{table}/{key}": {
             "delete": {
-                "description": "Deletes a subworkout-superworkout relationship",
+                "description": "Deletes a row of a data table. Rows with a text ID are moved to the trash, and if other rows depend on them, a dialog listing them is rendered instead unless cascade is set",
                 "tags": [
                     "rawdata"
                 ],
-                "summary": "Delete subworkout relationship",
+                "summary": "Delete data table row",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Subworkout ID",
-                        "name": "subworkout",
+                        "enum": [
+                            "lift",
+                            "movement",
+                            "muscle",
+                            "routine",
+                            "workout",
+                            "template_variable",
+                            "lift_group",
+                            "side_weight",
+                            "progress",
+                            "subworkout",
+                            "routine_workout_mapping",
+                            "lift_muscle_mapping",
+                            "lift_workout_mapping"
+                        ],
+                        "type": "string",
+                        "description": "Data table",
+                        "name": "table",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Superworkout ID",
-                        "name": "superworkout",
+                        "description": "Primary key of the row",
+                        "name": "key",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also remove the rows depending on it",
+                        "name": "cascade",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "OK, or the HTML dialog listing dependent rows",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "404": {
+                        "description": "Not found",
                         "schema": {
                             "type": "string"
                         }
@@ -2186,45 +929,57 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Updates specific fields of a subworkout-superworkout relationship",
+                "description": "Updates the given fields of a row of a data table in one transaction and renders the updated row, followed by a toast offering to undo the edit for progress. The row is identified by one path segment per primary key column",
                 "consumes": [
                     "application/x-www-form-urlencoded"
                 ],
+                "produces": [
+                    "text/html"
+                ],
                 "tags": [
                     "rawdata"
                 ],
-                "summary": "Update subworkout data",
+                "summary": "Update data table row",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Subworkout ID",
-                        "name": "subworkout",
+                        "enum": [
+                            "lift",
+                            "movement",
+                            "muscle",
+                            "routine",
+                            "workout",
+                            "template_variable",
+                            "lift_group",
+                            "side_weight",
+                            "progress",
+                            "subworkout",
+                            "routine_workout_mapping",
+                            "lift_muscle_mapping",
+                            "lift_workout_mapping"
+                        ],
+                        "type": "string",
+                        "description": "Data table",
+                        "name": "table",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Superworkout ID",
-                        "name": "superworkout",
+                        "description": "Primary key of the row",
+                        "name": "key",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "New subworkout ID",
-                        "name": "subworkout",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "New superworkout ID",
-                        "name": "superworkout",
-                        "in": "formData"
+                        "description": "Version of the row the patch is based on",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "HTML content",
                         "schema": {
                             "type": "string"
                         }
@@ -2235,6 +990,24 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Row not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "HTML content of the stored row if it changed since that version",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "HTML content of the stored row with the invalid fields",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2244,24 +1017,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/view/data/template_variable": {
+        "/view/doctor": {
             "get": {
-                "description": "Renders a paginated table view of template variables",
+                "description": "Renders the results of the integrity and foreign key checks with fixes for orphaned rows",
                 "produces": [
                     "text/html"
                 ],
                 "tags": [
-                    "rawdata"
-                ],
-                "summary": "Get template variable data table view",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "admin"
                 ],
+                "summary": "Get doctor view",
                 "responses": {
                     "200": {
                         "description": "HTML content",
@@ -2269,12 +1034,6 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2282,9 +1041,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/view/doctor/fix": {
             "post": {
-                "description": "Creates a new template variable entry in the database",
+                "description": "Deletes, clears or reassigns the rows whose column references a missing value, then renders the doctor view",
                 "consumes": [
                     "application/x-www-form-urlencoded"
                 ],
@@ -2292,34 +1053,59 @@ const docTemplate = `{
                     "text/html"
                 ],
                 "tags": [
-                    "rawdata"
+                    "admin"
                 ],
-                "summary": "Create new template variable",
+                "summary": "Fix orphaned rows",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Template variable ID",
-                        "name": "id",
+                        "description": "Referencing table",
+                        "name": "table",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Referencing column",
+                        "name": "column",
                         "in": "formData",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Template variable value",
+                        "description": "Missing value",
                         "name": "value",
                         "in": "formData",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "delete",
+                            "clear",
+                            "reassign"
+                        ],
+                        "type": "string",
+                        "description": "Fix to apply",
+                        "name": "action",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Existing value to reassign the rows to",
+                        "name": "target",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "201": {
+                    "200": {
                         "description": "HTML content",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid form data",
                         "schema": {
                             "type": "string"
                         }
@@ -2333,76 +1119,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/view/data/template_variable/{id}": {
-            "delete": {
-                "description": "Deletes a template variable entry by ID",
-                "tags": [
-                    "rawdata"
-                ],
-                "summary": "Delete template variable",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Template variable ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "patch": {
-                "description": "Updates specific fields of a template variable entry by ID",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
+        "/view/lift/{id}": {
+            "get": {
+                "description": "Renders a lift as a row of the lifts data table to edit inline, along with the muscles it moves by movement, the routines and workouts using it, including workouts containing those as subworkouts, its records by reps and its most recent progress",
+                "produces": [
+                    "text/html"
                 ],
                 "tags": [
-                    "rawdata"
+                    "index"
                 ],
-                "summary": "Update template variable data",
+                "summary": "Get lift view",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Template variable ID",
+                        "description": "Lift ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "New template variable ID",
-                        "name": "id",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template variable value",
-                        "name": "value",
-                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "HTML content",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "404": {
+                        "description": "Lift not found",
                         "schema": {
                             "type": "string"
                         }
@@ -2416,24 +1160,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/view/data/workout": {
+        "/view/liftgroups": {
             "get": {
-                "description": "Renders a paginated table view of workouts",
+                "description": "Renders the list of lift groups for today",
                 "produces": [
                     "text/html"
                 ],
                 "tags": [
-                    "rawdata"
-                ],
-                "summary": "Get workout data table view",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "index"
                 ],
+                "summary": "Get lift group list view",
                 "responses": {
                     "200": {
                         "description": "HTML content",
@@ -2441,12 +1177,6 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2454,48 +1184,39 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Creates a new workout entry in the database",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
+            }
+        },
+        "/view/liftselect": {
+            "get": {
+                "description": "Renders a select dropdown of lifts grouped by lift group",
                 "produces": [
                     "text/html"
                 ],
                 "tags": [
-                    "rawdata"
+                    "index"
                 ],
-                "summary": "Create new workout",
+                "summary": "Get lift select dropdown",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workout ID",
-                        "name": "id",
-                        "in": "formData",
-                        "required": true
+                        "description": "Input name attribute",
+                        "name": "name",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Workout template",
-                        "name": "template",
-                        "in": "formData",
-                        "required": true
+                        "description": "Selected lift ID",
+                        "name": "lift",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
+                    "200": {
                         "description": "HTML content",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2505,25 +1226,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/view/data/workout/{id}": {
-            "delete": {
-                "description": "Deletes a workout entry by ID",
+        "/view/matrix/{table}": {
+            "get": {
+                "description": "Renders the mappings of lifts as a matrix of lifts by the muscles or workouts they map to, with the role of each muscle",
+                "produces": [
+                    "text/html"
+                ],
                 "tags": [
                     "rawdata"
                 ],
-                "summary": "Delete workout",
+                "summary": "Get mapping matrix view",
                 "parameters": [
                     {
+                        "enum": [
+                            "lift_muscle_mapping",
+                            "lift_workout_mapping"
+                        ],
                         "type": "string",
-                        "description": "Workout ID",
-                        "name": "id",
+                        "description": "Mapping table",
+                        "name": "table",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lift group of the lifts shown",
+                        "name": "group",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "HTML content",
                         "schema": {
                             "type": "string"
                         }
@@ -2536,39 +1270,47 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
-                "description": "Updates specific fields of a workout entry by ID",
+            "post": {
+                "description": "Sets the cells of a mapping matrix in one transaction and renders it. Cells are named cell.{lift}.{column} after the indexes of the lift and column fields listing the lifts and columns shown, and hold the roles of the cell joined by newlines, or 1 if checked for mappings without roles. Nothing is saved if the mappings changed since the version they were shown at",
                 "consumes": [
                     "application/x-www-form-urlencoded"
                 ],
+                "produces": [
+                    "text/html"
+                ],
                 "tags": [
                     "rawdata"
                 ],
-                "summary": "Update workout data",
+                "summary": "Save mapping matrix",
                 "parameters": [
                     {
+                        "enum": [
+                            "lift_muscle_mapping",
+                            "lift_workout_mapping"
+                        ],
                         "type": "string",
-                        "description": "Workout ID",
-                        "name": "id",
+                        "description": "Mapping table",
+                        "name": "table",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "New workout ID",
-                        "name": "id",
+                        "description": "Lift group of the lifts shown",
+                        "name": "group",
                         "in": "formData"
                     },
                     {
                         "type": "string",
-                        "description": "Workout template",
-                        "name": "template",
-                        "in": "formData"
+                        "description": "Version of the mappings shown",
+                        "name": "version",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "HTML content",
                         "schema": {
                             "type": "string"
                         }
@@ -2579,28 +1321,8 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/view/liftgroups": {
-            "get": {
-                "description": "Renders the list of lift groups for today",
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "index"
-                ],
-                "summary": "Get lift group list view",
-                "responses": {
-                    "200": {
-                        "description": "HTML content",
+                    "409": {
+                        "description": "HTML content of the stored matrix if it changed since that version",
                         "schema": {
                             "type": "string"
                         }
@@ -2614,27 +1336,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/view/liftselect": {
+        "/view/options/{table}/{column}": {
             "get": {
-                "description": "Renders a select dropdown of lifts grouped by lift group",
+                "description": "Renders the options of the datalist of a column referencing another table, which are the keys of that table containing the typed text, those starting with it first",
                 "produces": [
                     "text/html"
                 ],
                 "tags": [
-                    "index"
+                    "rawdata"
                 ],
-                "summary": "Get lift select dropdown",
+                "summary": "Suggest data table references",
                 "parameters": [
+                    {
+                        "enum": [
+                            "lift",
+                            "movement",
+                            "muscle",
+                            "routine",
+                            "workout",
+                            "template_variable",
+                            "lift_group",
+                            "side_weight",
+                            "progress",
+                            "subworkout",
+                            "routine_workout_mapping",
+                            "lift_muscle_mapping",
+                            "lift_workout_mapping"
+                        ],
+                        "type": "string",
+                        "description": "Data table",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
-                        "description": "Input name attribute",
-                        "name": "name",
-                        "in": "query"
+                        "description": "Column referencing another table",
+                        "name": "column",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Selected lift ID",
-                        "name": "lift",
+                        "description": "Typed text, sent as the query parameter named after the column",
+                        "name": "text",
                         "in": "query"
                     }
                 ],
@@ -2644,6 +1389,18 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "No such column",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
@@ -2709,6 +1466,12 @@ const docTemplate = `{
                         "description": "Reps",
                         "name": "reps",
                         "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Whether the form is filled in again once logged, as one of the forms of a workout",
+                        "name": "keep",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
@@ -2809,6 +1572,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "422": {
+                        "description": "Progress form with the invalid fields",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2820,7 +1589,10 @@ const docTemplate = `{
         },
         "/view/progresstablerow/{id}": {
             "delete": {
-                "description": "Deletes a progress entry by ID",
+                "description": "Deletes a progress entry by ID and renders a toast to undo it",
+                "produces": [
+                    "text/html"
+                ],
                 "tags": [
                     "index"
                 ],
@@ -2836,7 +1608,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "HTML content",
                         "schema": {
                             "type": "string"
                         }
@@ -2847,6 +1619,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2895,6 +1673,12 @@ const docTemplate = `{
                         "description": "Lift ID to get default side weight",
                         "name": "lift",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Selected side weight, instead of the default of the lift",
+                        "name": "side",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2903,6 +1687,12 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
@@ -2949,7 +1739,7 @@ const docTemplate = `{
         },
         "/view/tabs/main": {
             "get": {
-                "description": "Renders the main tab view with progress for today and lift groups",
+                "description": "Renders the main tab view with progress for today and lift groups, and a progress form for each lift of the workout given by the workout query parameter of the page URL",
                 "produces": [
                     "text/html"
                 ],
@@ -2957,6 +1747,190 @@ const docTemplate = `{
                     "index"
                 ],
                 "summary": "Get main tab view",
+                "responses": {
+                    "200": {
+                        "description": "HTML content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/view/trash": {
+            "get": {
+                "description": "Renders the deleted rows that can still be restored, most recent first",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get trash view",
+                "responses": {
+                    "200": {
+                        "description": "HTML content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/view/trash/{batch}/restore": {
+            "post": {
+                "description": "Puts the rows removed by a delete back, then renders the trash view",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Restore deleted rows",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Trash batch ID",
+                        "name": "batch",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HTML content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid batch ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/view/undo": {
+            "post": {
+                "description": "Reverts the most recent change made by the session and triggers undoChange so views reload",
+                "tags": [
+                    "index"
+                ],
+                "summary": "Undo the last change",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Nothing to undo",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/view/workout/{id}": {
+            "get": {
+                "description": "Renders a workout with its template variables expanded, the lifts and routines mapped to it and a graph of the superworkouts containing it and the subworkouts it contains, with a link to log each of its lifts",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "index"
+                ],
+                "summary": "Get workout view",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HTML content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout/{id}": {
+            "get": {
+                "description": "Renders the index page showing everything about a workout",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "index"
+                ],
+                "summary": "Get workout page",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "HTML content",
@@ -2974,6 +1948,37 @@ const docTemplate = `{
             }
         }
     },
+    "definitions": {
+        "health.Check": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.Check"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        }
+    },
     "tags": [
         {
             "description": "Main application pages and views",
@@ -2982,6 +1987,14 @@ const docTemplate = `{
         {
             "description": "CRUD operations for raw data entities (lifts, workouts, progress, etc.)",
             "name": "rawdata"
+        },
+        {
+            "description": "Database maintenance pages",
+            "name": "admin"
+        },
+        {
+            "description": "Liveness and readiness checks",
+            "name": "health"
         }
     ]
 }`
@@ -0,0 +1,287 @@
+// Package audit records who changed which fields of the data tables and when.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/RyRose/uplog/internal/schema"
+	"github.com/RyRose/uplog/internal/telemetry"
+)
+
+// Action is how a row was changed.
+type Action string
+
+const (
+	Inserted Action = "insert"
+	Updated  Action = "update"
+	Deleted  Action = "delete"
+)
+
+// Request identifies the request making a change.
+type Request struct {
+	ID string
+	// User is the user the request was made on behalf of.
+	User string
+}
+
+// FromRequest returns the request ID and user of r. The ID is the
+// X-Request-Id header or else the trace ID. The user is set by an
+// authenticating proxy in the Remote-User or X-Forwarded-User header. Any
+// client can send those headers, so they are ignored unless r comes directly
+// from one of the trusted proxies of l.
+func (l *Log) FromRequest(r *http.Request) Request {
+	req := Request{ID: r.Header.Get("X-Request-Id")}
+	if req.ID == "" {
+		req.ID = telemetry.TraceID(r.Context())
+	}
+	if l.trusts(r.RemoteAddr) {
+		req.User = r.Header.Get("Remote-User")
+		if req.User == "" {
+			req.User = r.Header.Get("X-Forwarded-User")
+		}
+	}
+	return req
+}
+
+// trusts reports whether remoteAddr, the host:port of a client, is a trusted
+// proxy.
+func (l *Log) trusts(remoteAddr string) bool {
+	addrPort, err := netip.ParseAddrPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+	addr := addrPort.Addr().Unmap()
+	for _, prefix := range l.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTrustedProxies parses proxies, each an IP address or a CIDR prefix
+// such as 10.0.0.0/8.
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if addr, err := netip.ParseAddr(proxy); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q is neither an IP address nor a CIDR prefix", proxy)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// Entry is the change of a single field.
+type Entry struct {
+	ID        int64
+	ChangedAt time.Time
+	RequestID string
+	User      string
+	// Table and Key identify the changed row.
+	Table, Key string
+	Action     Action
+	Field      string
+	// Old and New are the values of the field before and after the change, or
+	// nil if absent.
+	Old, New *string
+}
+
+// Row is a row as a JSON object, or empty if there is no row.
+type Row string
+
+// Version identifies the values of r, so it changes whenever any value of the
+// row does however it is changed. It is empty if there is no row.
+func (r Row) Version() string {
+	if r == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(r))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Values returns the values of the fields of r as shown in the audit log, or
+// nil for nulls.
+func (r Row) Values() (map[string]*string, error) {
+	fields, err := decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode row: %w", err)
+	}
+	values := make(map[string]*string, len(fields))
+	for field, v := range fields {
+		if v != nil {
+			t := text(v)
+			values[field] = &t
+		}
+	}
+	return values, nil
+}
+
+// Snapshot returns the row of table whose primary key columns have the values
+// of key, or an empty Row if there is none.
+func Snapshot(ctx context.Context, db schema.Querier, table string, key map[string]string) (Row, error) {
+	columns, _, err := schema.Columns(ctx, db, table)
+	if err != nil {
+		return "", err
+	}
+	var (
+		where []string
+		args  []any
+	)
+	for c, v := range key {
+		where = append(where, schema.Quote(c)+" = ?")
+		args = append(args, v)
+	}
+	if len(where) == 0 {
+		return "", fmt.Errorf("no key given for %s", table)
+	}
+	return snapshot(ctx, db, fmt.Sprintf("SELECT %s FROM %s WHERE %s",
+		schema.Object(columns), schema.Quote(table), strings.Join(where, " AND ")), args...)
+}
+
+// SnapshotInserted returns the row of table inserted last on the connection of
+// db, which must be the transaction that inserted it.
+func SnapshotInserted(ctx context.Context, db schema.Querier, table string) (Row, error) {
+	columns, _, err := schema.Columns(ctx, db, table)
+	if err != nil {
+		return "", err
+	}
+	return snapshot(ctx, db, fmt.Sprintf("SELECT %s FROM %s WHERE rowid = last_insert_rowid()",
+		schema.Object(columns), schema.Quote(table)))
+}
+
+func snapshot(ctx context.Context, db schema.Querier, query string, args ...any) (Row, error) {
+	var row string
+	err := db.QueryRowContext(ctx, query, args...).Scan(&row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to snapshot row: %w", err)
+	}
+	return Row(row), nil
+}
+
+// Log records audit entries and lists them.
+type Log struct {
+	db             schema.Querier
+	trustedProxies []netip.Prefix
+	now            func() time.Time
+}
+
+// New returns a Log listing entries from db. Entries are written by the
+// transactions passed to Record, so db may be read-only. Users are only
+// recorded for requests coming from trustedProxies.
+func New(db schema.Querier, trustedProxies []netip.Prefix) *Log {
+	return &Log{db: db, trustedProxies: trustedProxies, now: time.Now}
+}
+
+// Record stores an entry for each field of the row of table that differs
+// between before and after. An empty before is an insert and an empty after a
+// delete. It must run in the transaction making the change so the entries are
+// only kept if the change is.
+func (l *Log) Record(ctx context.Context, tx schema.Execer, req Request, table string, before, after Row) error {
+	action := Updated
+	switch {
+	case before == "" && after == "":
+		return nil
+	case before == "":
+		action = Inserted
+	case after == "":
+		action = Deleted
+	}
+	old, err := decode(before)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s row before change: %w", table, err)
+	}
+	changed, err := decode(after)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s row after change: %w", table, err)
+	}
+	columns, primaryKey, err := schema.Columns(ctx, tx, table)
+	if err != nil {
+		return err
+	}
+	keyRow := changed
+	if action == Deleted {
+		keyRow = old
+	}
+	var key []string
+	for _, c := range primaryKey {
+		key = append(key, text(keyRow[c]))
+	}
+
+	changedAt := l.now().UTC().Format(time.RFC3339)
+	for _, c := range columns {
+		o, n := old[c], changed[c]
+		if o == nil && n == nil {
+			continue
+		}
+		if o != nil && n != nil && text(o) == text(n) {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO audit_log
+			(changed_at, request_id, user, source, key, action, field, old_value, new_value)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			changedAt, req.ID, req.User, table, strings.Join(key, "/"), string(action), c,
+			nullable(o), nullable(n),
+		); err != nil {
+			return fmt.Errorf("failed to record change of %s.%s: %w", table, c, err)
+		}
+	}
+	return nil
+}
+
+// Observer returns a function recording the change of a row of table given as
+// JSON objects before and after it as made by req. It is run by the trash and
+// the undo history for each row they change, in the transaction changing it.
+func (l *Log) Observer(req Request) func(ctx context.Context, tx *sql.Tx, table, before, after string) error {
+	return func(ctx context.Context, tx *sql.Tx, table, before, after string) error {
+		return l.Record(ctx, tx, req, table, Row(before), Row(after))
+	}
+}
+
+func decode(row Row) (map[string]any, error) {
+	values := make(map[string]any)
+	if row == "" {
+		return values, nil
+	}
+	d := json.NewDecoder(bytes.NewReader([]byte(row)))
+	d.UseNumber()
+	if err := d.Decode(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// text returns a JSON value as it is shown in the audit log.
+func text(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+func nullable(v any) any {
+	if v == nil {
+		return nil
+	}
+	return text(v)
+}
@@ -0,0 +1,291 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func setup(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`
+CREATE TABLE audit_log (
+    id INTEGER PRIMARY KEY NOT NULL,
+    changed_at TEXT NOT NULL,
+    request_id TEXT NOT NULL,
+    user TEXT NOT NULL,
+    source TEXT NOT NULL,
+    key TEXT NOT NULL,
+    action TEXT NOT NULL,
+    field TEXT NOT NULL,
+    old_value TEXT NULL,
+    new_value TEXT NULL
+);
+CREATE TABLE lift (id TEXT PRIMARY KEY NOT NULL, link TEXT NOT NULL, notes TEXT);
+CREATE TABLE lift_workout_mapping (
+    lift TEXT NOT NULL,
+    workout TEXT NOT NULL,
+    PRIMARY KEY (lift, workout)
+);
+CREATE TABLE progress (id INTEGER PRIMARY KEY NOT NULL, weight REAL NOT NULL);
+`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+// change runs stmt in a transaction and records it like the data tables do.
+func change(t *testing.T, log *Log, table string, key map[string]string, stmt string) {
+	t.Helper()
+	ctx := context.Background()
+	tx, err := log.db.(*sql.DB).BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	var before Row
+	if key != nil {
+		if before, err = Snapshot(ctx, tx, table, key); err != nil {
+			t.Fatalf("Snapshot() error = %v", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, stmt); err != nil {
+		t.Fatalf("%s: %v", stmt, err)
+	}
+	var after Row
+	if key == nil {
+		after, err = SnapshotInserted(ctx, tx, table)
+	} else {
+		after, err = Snapshot(ctx, tx, table, key)
+	}
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if err := log.Record(ctx, tx, Request{ID: "req", User: "ryan"}, table, before, after); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+}
+
+type field struct {
+	Key      string
+	Action   Action
+	Field    string
+	Old, New any
+}
+
+func fields(entries []Entry) []field {
+	var got []field
+	for _, e := range entries {
+		f := field{Key: e.Key, Action: e.Action, Field: e.Field}
+		if e.Old != nil {
+			f.Old = *e.Old
+		}
+		if e.New != nil {
+			f.New = *e.New
+		}
+		got = append(got, f)
+	}
+	return got
+}
+
+func TestRecord(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	log := New(db, nil)
+
+	change(t, log, "lift", nil, "INSERT INTO lift VALUES ('squat', 'a', NULL)")
+	change(t, log, "lift", map[string]string{"id": "squat"}, "UPDATE lift SET link = 'b', notes = 'deep' WHERE id = 'squat'")
+	change(t, log, "lift", map[string]string{"id": "squat"}, "UPDATE lift SET link = 'b' WHERE id = 'squat'")
+	change(t, log, "progress", nil, "INSERT INTO progress VALUES (7, 100.5)")
+	change(t, log, "lift_workout_mapping", nil, "INSERT INTO lift_workout_mapping VALUES ('squat', 'legs')")
+	change(t, log, "lift_workout_mapping", map[string]string{"lift": "squat", "workout": "legs"},
+		"DELETE FROM lift_workout_mapping")
+
+	entries, err := log.List(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	want := []field{
+		{Key: "squat/legs", Action: Deleted, Field: "workout", Old: "legs"},
+		{Key: "squat/legs", Action: Deleted, Field: "lift", Old: "squat"},
+		{Key: "squat/legs", Action: Inserted, Field: "workout", New: "legs"},
+		{Key: "squat/legs", Action: Inserted, Field: "lift", New: "squat"},
+		{Key: "7", Action: Inserted, Field: "weight", New: "100.5"},
+		{Key: "7", Action: Inserted, Field: "id", New: "7"},
+		{Key: "squat", Action: Updated, Field: "notes", New: "deep"},
+		{Key: "squat", Action: Updated, Field: "link", Old: "a", New: "b"},
+		{Key: "squat", Action: Inserted, Field: "link", New: "a"},
+		{Key: "squat", Action: Inserted, Field: "id", New: "squat"},
+	}
+	if got := fields(entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("List() =\n%+v\nwant\n%+v", got, want)
+	}
+	for _, e := range entries {
+		if e.RequestID != "req" || e.User != "ryan" {
+			t.Errorf("entry %d made by %q for %q, want req for ryan", e.ID, e.RequestID, e.User)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	log := New(db, nil)
+
+	day := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, stmt := range []string{
+		"INSERT INTO progress VALUES (1, 100)",
+		"INSERT INTO progress VALUES (2, 100)",
+		"INSERT INTO lift VALUES ('squat', 'a', NULL)",
+	} {
+		log.now = func() time.Time { return day.AddDate(0, 0, i) }
+		table := "progress"
+		if i == 2 {
+			table = "lift"
+		}
+		change(t, log, table, nil, stmt)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"all", Filter{}, []string{"squat", "squat", "2", "2", "1", "1"}},
+		{"table", Filter{Table: "progress"}, []string{"2", "2", "1", "1"}},
+		{"from", Filter{From: day.AddDate(0, 0, 1)}, []string{"squat", "squat", "2", "2"}},
+		{"to", Filter{To: day.AddDate(0, 0, 1)}, []string{"1", "1"}},
+		{"page", Filter{Limit: 2, Offset: 1}, []string{"squat", "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := log.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Key)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List() keys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	tables, err := log.Tables(ctx)
+	if err != nil {
+		t.Fatalf("Tables() error = %v", err)
+	}
+	if !reflect.DeepEqual(tables, []string{"lift", "progress"}) {
+		t.Errorf("Tables() = %v, want [lift progress]", tables)
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	// httptest requests come from 192.0.2.1.
+	trusted, err := ParseTrustedProxies([]string{"192.0.2.0/24"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error = %v", err)
+	}
+	log := New(nil, trusted)
+	r := httptest.NewRequest(http.MethodPatch, "/", nil)
+	r.Header.Set("X-Request-Id", "abc")
+	r.Header.Set("X-Forwarded-User", "ryan")
+	if got, want := log.FromRequest(r), (Request{ID: "abc", User: "ryan"}); got != want {
+		t.Errorf("FromRequest() = %+v, want %+v", got, want)
+	}
+	r.Header.Set("Remote-User", "rose")
+	if got := log.FromRequest(r).User; got != "rose" {
+		t.Errorf("FromRequest() user = %q, want rose", got)
+	}
+
+	// Requests from other clients may forge the headers.
+	r.RemoteAddr = "198.51.100.7:1234"
+	if got, want := log.FromRequest(r), (Request{ID: "abc"}); got != want {
+		t.Errorf("FromRequest() from an untrusted client = %+v, want %+v", got, want)
+	}
+	if got := New(nil, nil).FromRequest(httptest.NewRequest(http.MethodPatch, "/", nil)).User; got != "" {
+		t.Errorf("FromRequest() without trusted proxies user = %q, want none", got)
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	got, err := ParseTrustedProxies([]string{"10.1.2.3", "::ffff:10.0.0.1", "172.16.5.0/12", "fd00::/8"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error = %v", err)
+	}
+	want := []string{"10.1.2.3/32", "10.0.0.1/32", "172.16.0.0/12", "fd00::/8"}
+	for i, prefix := range got {
+		if prefix.String() != want[i] {
+			t.Errorf("ParseTrustedProxies()[%d] = %s, want %s", i, prefix, want[i])
+		}
+	}
+	if _, err := ParseTrustedProxies([]string{"proxy.local"}); err == nil {
+		t.Error("ParseTrustedProxies() expected error for a host name")
+	}
+}
+
+func TestRowVersion(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	if _, err := db.Exec("INSERT INTO lift VALUES ('squat', 'a', NULL)"); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	snapshot := func() Row {
+		t.Helper()
+		row, err := Snapshot(ctx, db, "lift", map[string]string{"id": "squat"})
+		if err != nil {
+			t.Fatalf("Snapshot() error = %v", err)
+		}
+		return row
+	}
+
+	v := snapshot().Version()
+	if v == "" {
+		t.Fatal("Version() is empty")
+	}
+	if got := snapshot().Version(); got != v {
+		t.Errorf("Version() of the same row = %q, want %q", got, v)
+	}
+	if _, err := db.Exec("UPDATE lift SET notes = 'deep'"); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if got := snapshot().Version(); got == v {
+		t.Errorf("Version() of the changed row = %q, want it to change", got)
+	}
+	if got := Row("").Version(); got != "" {
+		t.Errorf("Version() of no row = %q, want empty", got)
+	}
+}
+
+func TestRowValues(t *testing.T) {
+	values, err := Row(`{"id":7,"lift":"squat","notes":null,"weight":100.5}`).Values()
+	if err != nil {
+		t.Fatalf("Values() error = %v", err)
+	}
+	got := make(map[string]any)
+	for field, v := range values {
+		got[field] = *v
+	}
+	want := map[string]any{"id": "7", "lift": "squat", "weight": "100.5"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Values() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Filter selects audit entries.
+type Filter struct {
+	// Table selects the entries of a single table if not empty.
+	Table string
+	// From and To select the entries changed at or after From and before To if
+	// they are not zero.
+	From, To time.Time
+	// Limit and Offset select a page of entries, most recent first.
+	Limit, Offset int
+}
+
+// List returns the entries selected by f, most recent first.
+func (l *Log) List(ctx context.Context, f Filter) ([]Entry, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if f.Table != "" {
+		where = append(where, "source = ?")
+		args = append(args, f.Table)
+	}
+	if !f.From.IsZero() {
+		where = append(where, "changed_at >= ?")
+		args = append(args, f.From.UTC().Format(time.RFC3339))
+	}
+	if !f.To.IsZero() {
+		where = append(where, "changed_at < ?")
+		args = append(args, f.To.UTC().Format(time.RFC3339))
+	}
+	query := `SELECT id, changed_at, request_id, user, source, key, action, field, old_value, new_value
+		FROM audit_log`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT ? OFFSET ?"
+	limit := f.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	args = append(args, limit, f.Offset)
+
+	rows, err := l.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	var entries []Entry
+	for rows.Next() {
+		var (
+			e         Entry
+			changedAt string
+			action    string
+		)
+		if err := rows.Scan(&e.ID, &changedAt, &e.RequestID, &e.User, &e.Table, &e.Key, &action,
+			&e.Field, &e.Old, &e.New); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to scan audit entry: %w", err), rows.Close())
+		}
+		e.Action = Action(action)
+		if e.ChangedAt, err = time.Parse(time.RFC3339, changedAt); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to parse time of audit entry %d: %w", e.ID, err), rows.Close())
+		}
+		entries = append(entries, e)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// Tables returns the tables with audit entries in order.
+func (l *Log) Tables(ctx context.Context) ([]string, error) {
+	rows, err := l.db.QueryContext(ctx, "SELECT DISTINCT source FROM audit_log ORDER BY source")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audited tables: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to scan audited table: %w", err), rows.Close())
+		}
+		tables = append(tables, table)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("failed to list audited tables: %w", err)
+	}
+	return tables, nil
+}
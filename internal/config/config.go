@@ -4,32 +4,19 @@ import (
 	"context"
 	"fmt"
 	"strings"
-
-	"github.com/RyRose/gluamapper"
-	lua "github.com/yuin/gopher-lua"
 )
 
+// Load evaluates the Lua config file at configPath in the DefaultSandbox.
 func Load(ctx context.Context, configPath string) (*Data, error) {
-	L := lua.NewState()
-	L.SetContext(ctx)
-	L.OpenLibs()
-	defer L.Close()
-
-	if err := L.DoFile(configPath); err != nil {
-		return nil, fmt.Errorf("failed to load config file %s: %w", configPath, err)
-	}
-
-	lv := L.Get(-1)
-	if lv.Type() != lua.LTTable {
-		return nil, fmt.Errorf("config file must return a table, got %s", lv.Type())
-	}
+	return LoadWithSandbox(ctx, DefaultSandbox(), configPath)
+}
 
+// LoadWithSandbox evaluates the Lua config file at configPath in sandbox.
+func LoadWithSandbox(ctx context.Context, sandbox *Sandbox, configPath string) (*Data, error) {
 	var data Data
-	mapper := gluamapper.NewMapper(gluamapper.Option{NameFunc: ToUpperCamelCase})
-	if err := mapper.Map(lv.(*lua.LTable), &data); err != nil {
-		return nil, fmt.Errorf("failed to map lua table to config: %w", err)
+	if err := sandbox.Eval(ctx, configPath, &data); err != nil {
+		return nil, err
 	}
-
 	return &data, nil
 }
 
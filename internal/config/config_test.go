@@ -5,17 +5,18 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
 func TestLoad(t *testing.T) {
-	// Set some environment variables for testing
-	_ = os.Setenv("DATABASE_PATH", "/test/db.db")
-	_ = os.Setenv("PORT", "9090")
-	_ = os.Setenv("DEBUG", "true")
-	defer func() { _ = os.Unsetenv("DATABASE_PATH") }()
-	defer func() { _ = os.Unsetenv("PORT") }()
-	defer func() { _ = os.Unsetenv("DEBUG") }()
+	// The unprefixed variables are no longer read; values are overridden with
+	// UPLOG_<KEY> by Resolve instead.
+	t.Setenv("DATABASE_PATH", "/test/db.db")
+	t.Setenv("PORT", "9090")
+	t.Setenv("DEBUG", "true")
+	t.Setenv("VERSION", "unprefixed")
+	t.Setenv("SWAGGER_URL", "unprefixed")
 
 	origDir, err := os.Getwd()
 	if err != nil {
@@ -33,15 +34,39 @@ func TestLoad(t *testing.T) {
 		t.Fatalf("Load() error = %v", err)
 	}
 
-	if cfg.DatabasePath != "/test/db.db" {
-		t.Errorf("DatabasePath = %v, want /test/db.db", cfg.DatabasePath)
+	if cfg.DatabasePath != "./tmp/db/data.db" {
+		t.Errorf("DatabasePath = %v, want ./tmp/db/data.db", cfg.DatabasePath)
 	}
 
-	if cfg.Port != "9090" {
-		t.Errorf("Port = %v, want 9090", cfg.Port)
+	if cfg.Port != "8080" {
+		t.Errorf("Port = %v, want 8080", cfg.Port)
 	}
 
-	if !cfg.Debug {
-		t.Errorf("Debug = %v, want true", cfg.Debug)
+	if cfg.Debug {
+		t.Errorf("Debug = %v, want false", cfg.Debug)
+	}
+
+	if cfg.Mode != ModeProd {
+		t.Errorf("Mode = %v, want %v", cfg.Mode, ModeProd)
+	}
+
+	if cfg.AppVersion() == "unprefixed" {
+		t.Errorf("Version = %v, want it not read from VERSION", cfg.AppVersion())
+	}
+
+	if strings.HasPrefix(cfg.SwaggerURL, "unprefixed") {
+		t.Errorf("SwaggerURL = %v, want it not read from SWAGGER_URL", cfg.SwaggerURL)
+	}
+}
+
+func TestData_AppVersion(t *testing.T) {
+	var cfg Data
+	if got := cfg.AppVersion(); got != "" {
+		t.Errorf("AppVersion() without a version = %q, want empty", got)
+	}
+	version := "1.2.3"
+	cfg.Version = &version
+	if got := cfg.AppVersion(); got != version {
+		t.Errorf("AppVersion() = %q, want %q", got, version)
 	}
 }
@@ -4,17 +4,128 @@ package config
 // To log this struct, to use slog's JSON logging to ensure certain fields are expunged.
 // Sensitive fields should be marked with `json:"-"` struct tags.
 type Data struct {
+	// Mode specifies the deployment mode: "dev", "test" or "prod". Dev-only
+	// features such as the API docs and debug logging are disabled in "prod".
+	Mode string
 	// Debug enables debug mode. When enabled, the application will log additional
-	// debug information.
+	// debug information. Ignored when mode is "prod".
 	Debug bool
 	// Version specifies the application version.
 	Version *string
 	// DatabasePath specifies the path to the database file.
 	DatabasePath string
+	// Database configures the SQLite connections to the database file.
+	Database Database
 	// Port specifies the port on which the application will run.
 	Port string
 	// SwaggerURL specifies the URL for the Swagger documentation.
 	SwaggerURL string
 	// FirstDayOfWeek specifies the first day of the week (0 = Sunday, 1 = Monday, ...).
 	FirstDayOfWeek int
+	// Tracing configures OpenTelemetry tracing.
+	Tracing Tracing
+	// TrainingMetrics configures the lifting progress metrics exported on /metrics.
+	TrainingMetrics TrainingMetrics
+	// Server configures HTTP server timeouts and graceful shutdown.
+	Server Server
+	// Trash configures how long deleted rows can be restored.
+	Trash Trash
+	// Undo configures how many changes can be undone.
+	Undo Undo
+	// Audit configures the audit log of data table changes.
+	Audit Audit
+}
+
+// Tracing configures how OpenTelemetry spans are exported.
+type Tracing struct {
+	// Exporter selects where spans are exported: "none", "stdout" or "otlpfile".
+	// "otlpfile" appends OTLP JSON lines to Path so traces can be collected
+	// offline. Empty is the same as "none".
+	Exporter string
+	// Path specifies the file spans are appended to by the "otlpfile" exporter.
+	Path string
+}
+
+// Database configures the SQLite connection pools and the pragmas applied to
+// every connection. Zero values keep the SQLite defaults.
+type Database struct {
+	// ReadPoolSize bounds the number of read-only connections. Zero uses the
+	// number of CPUs. Writes always use a single connection.
+	ReadPoolSize int
+	// BusyTimeoutMillis specifies how long a connection waits on a lock held by
+	// another connection before failing with SQLITE_BUSY.
+	BusyTimeoutMillis int
+	// Synchronous sets how often SQLite syncs to disk: "OFF", "NORMAL", "FULL"
+	// or "EXTRA". "NORMAL" is durable across application crashes in WAL mode.
+	Synchronous string
+	// CacheSizeKB sets the page cache size of each connection in KiB.
+	CacheSizeKB int
+	// MmapSizeBytes sets how many bytes of the database file each connection
+	// may memory-map.
+	MmapSizeBytes int
+}
+
+// Server configures the HTTP server. Zero values disable the corresponding
+// timeout.
+type Server struct {
+	// ReadHeaderTimeoutSeconds specifies how long reading request headers may take.
+	ReadHeaderTimeoutSeconds int
+	// IdleTimeoutSeconds specifies how long idle keep-alive connections are kept open.
+	IdleTimeoutSeconds int
+	// DrainTimeoutSeconds specifies how long in-flight requests may take to finish
+	// on shutdown before their connections are closed.
+	DrainTimeoutSeconds int
+}
+
+// Trash configures the trash that rows removed by deletes are moved to.
+type Trash struct {
+	// RetentionDays specifies how many days deleted rows are kept before they
+	// are purged. Zero keeps them forever.
+	RetentionDays int
+}
+
+// Undo configures the change history used to undo the changes of a session.
+type Undo struct {
+	// Limit specifies how many of the most recent changes of each session can
+	// be undone. Zero uses 10.
+	Limit int
+}
+
+// Audit configures the audit log of the changes made through the data tables.
+type Audit struct {
+	// TrustedProxies lists the IP addresses or CIDR prefixes of the
+	// authenticating proxies whose Remote-User or X-Forwarded-User header names
+	// the user a change is recorded for. The headers of other clients are
+	// ignored since any client can set them. Empty records no users.
+	TrustedProxies []string
+}
+
+// TrainingMetrics configures the per-lift e1RM, top weight and weekly volume
+// gauges computed from logged progress.
+type TrainingMetrics struct {
+	// Enabled exports the training metrics on /metrics.
+	Enabled bool
+	// CacheSeconds specifies how long computed metrics are reused between
+	// scrapes before progress is read again.
+	CacheSeconds int
+}
+
+const (
+	ModeDev  = "dev"
+	ModeTest = "test"
+	ModeProd = "prod"
+)
+
+// DevFeatures reports whether dev-only features such as the API docs and debug
+// logging are enabled. They are enabled in every mode except prod.
+func (d *Data) DevFeatures() bool {
+	return d.Mode != ModeProd
+}
+
+// AppVersion returns Version, or "" if it is not set.
+func (d *Data) AppVersion() string {
+	if d.Version == nil {
+		return ""
+	}
+	return *d.Version
 }
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// decode maps a Lua value into the Go value pointed to by v. Table keys are
+// matched against the lower_snake_case names of struct fields, while map keys
+// are preserved as-is. Unknown struct fields are an error so typos in the
+// config file are caught early.
+func decode(lv lua.LValue, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, got %T", v)
+	}
+	return decodeValue("", lv, rv.Elem())
+}
+
+func decodePath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func decodeValue(path string, lv lua.LValue, rv reflect.Value) error {
+	if lv == lua.LNil {
+		rv.SetZero()
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(rv.Type().Elem())
+		if err := decodeValue(path, lv, elem.Elem()); err != nil {
+			return err
+		}
+		rv.Set(elem)
+		return nil
+	case reflect.String:
+		switch v := lv.(type) {
+		case lua.LString:
+			rv.SetString(string(v))
+		case lua.LNumber:
+			rv.SetString(v.String())
+		default:
+			return fmt.Errorf("%s: expected string, got %s", path, lv.Type())
+		}
+		return nil
+	case reflect.Bool:
+		switch v := lv.(type) {
+		case lua.LBool:
+			rv.SetBool(bool(v))
+		case lua.LString:
+			b, err := strconv.ParseBool(string(v))
+			if err != nil {
+				return fmt.Errorf("%s: expected boolean: %w", path, err)
+			}
+			rv.SetBool(b)
+		default:
+			return fmt.Errorf("%s: expected boolean, got %s", path, lv.Type())
+		}
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		f, err := decodeNumber(path, lv)
+		if err != nil {
+			return err
+		}
+		return setNumber(path, f, rv)
+	case reflect.Slice:
+		tbl, ok := lv.(*lua.LTable)
+		if !ok {
+			return fmt.Errorf("%s: expected array table, got %s", path, lv.Type())
+		}
+		n := tbl.MaxN()
+		if count := tableLen(tbl); count != n {
+			return fmt.Errorf("%s: expected array table, got table with %d non-sequential keys", path, count-n)
+		}
+		s := reflect.MakeSlice(rv.Type(), n, n)
+		for i := range n {
+			if err := decodeValue(fmt.Sprintf("%s[%d]", path, i+1), tbl.RawGetInt(i+1), s.Index(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(s)
+		return nil
+	case reflect.Map:
+		tbl, ok := lv.(*lua.LTable)
+		if !ok {
+			return fmt.Errorf("%s: expected table, got %s", path, lv.Type())
+		}
+		m := reflect.MakeMap(rv.Type())
+		var err error
+		tbl.ForEach(func(lk, lval lua.LValue) {
+			if err != nil {
+				return
+			}
+			key := reflect.New(rv.Type().Key()).Elem()
+			keyPath := decodePath(path, lk.String())
+			if err = decodeValue(keyPath, lk, key); err != nil {
+				return
+			}
+			val := reflect.New(rv.Type().Elem()).Elem()
+			if err = decodeValue(keyPath, lval, val); err != nil {
+				return
+			}
+			m.SetMapIndex(key, val)
+		})
+		if err != nil {
+			return err
+		}
+		rv.Set(m)
+		return nil
+	case reflect.Struct:
+		tbl, ok := lv.(*lua.LTable)
+		if !ok {
+			return fmt.Errorf("%s: expected table, got %s", path, lv.Type())
+		}
+		// Fields are keyed by the names used in the generated Lua types.
+		fields := make(map[string]reflect.StructField)
+		for _, f := range exportedFields(rv.Type()) {
+			fields[toSnakeCase(f.Name)] = f
+		}
+		var err error
+		tbl.ForEach(func(lk, lval lua.LValue) {
+			if err != nil {
+				return
+			}
+			key, ok := lk.(lua.LString)
+			if !ok {
+				err = fmt.Errorf("%s: expected string key, got %s", path, lk.Type())
+				return
+			}
+			f, ok := fields[string(key)]
+			if !ok {
+				// Fall back to a case-insensitive match, e.g. swagger_Url.
+				f, ok = fields[toSnakeCase(ToUpperCamelCase(string(key)))]
+			}
+			if !ok {
+				err = fmt.Errorf("%s: unknown field", decodePath(path, string(key)))
+				return
+			}
+			err = decodeValue(decodePath(path, string(key)), lval, rv.FieldByIndex(f.Index))
+		})
+		return err
+	default:
+		return fmt.Errorf("%s: unsupported type: %v", path, rv.Type())
+	}
+}
+
+func decodeNumber(path string, lv lua.LValue) (float64, error) {
+	switch v := lv.(type) {
+	case lua.LNumber:
+		return float64(v), nil
+	case lua.LString:
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%s: expected number: %w", path, err)
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("%s: expected number, got %s", path, lv.Type())
+	}
+}
+
+func setNumber(path string, f float64, rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		rv.SetFloat(f)
+		return nil
+	}
+	if f != math.Trunc(f) {
+		return fmt.Errorf("%s: expected integer, got %v", path, f)
+	}
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if f < 0 || rv.OverflowUint(uint64(f)) {
+			return fmt.Errorf("%s: %v overflows %v", path, f, rv.Type())
+		}
+		rv.SetUint(uint64(f))
+	default:
+		if rv.OverflowInt(int64(f)) {
+			return fmt.Errorf("%s: %v overflows %v", path, f, rv.Type())
+		}
+		rv.SetInt(int64(f))
+	}
+	return nil
+}
+
+func tableLen(tbl *lua.LTable) int {
+	n := 0
+	tbl.ForEach(func(lua.LValue, lua.LValue) { n++ })
+	return n
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+type Inner struct {
+	Name  string
+	Count int
+}
+
+type NestedConfig struct {
+	Title    string
+	Inner    Inner
+	Optional *Inner
+	List     []Inner
+	ByName   map[string]Inner
+	ByIndex  map[int]string
+	Matrix   [][]int
+	Ratio    float64
+	Enabled  *bool
+	Metadata map[string][]string
+}
+
+func evalLua(t *testing.T, src string) lua.LValue {
+	t.Helper()
+	L := lua.NewState()
+	t.Cleanup(L.Close)
+	if err := L.DoString(src); err != nil {
+		t.Fatalf("DoString() error = %v", err)
+	}
+	return L.Get(-1)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestDecode_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		target   func() any
+		expected any
+	}{
+		{
+			name:   "primitives",
+			src:    `return { name = "a", age = 3, active = true, score = 1.5 }`,
+			target: func() any { return &SimplePrimitives{} },
+			expected: &SimplePrimitives{
+				Name: "a", Age: 3, Active: true, Score: 1.5,
+			},
+		},
+		{
+			name:   "camel case and acronyms",
+			src:    `return { first_name = "a", last_name = "b", user_id = 7 }`,
+			target: func() any { return &CamelCaseFields{} },
+			expected: &CamelCaseFields{
+				FirstName: "a", LastName: "b", UserID: 7,
+			},
+		},
+		{
+			name:   "pointers",
+			src:    `return { name = "a" }`,
+			target: func() any { return &WithPointer{} },
+			expected: &WithPointer{
+				Name: ptr("a"),
+			},
+		},
+		{
+			name:     "string map keys are preserved",
+			src:      `return { metadata = { snake_key = "x", CamelKey = "y" } }`,
+			target:   func() any { return &WithStringMap{} },
+			expected: &WithStringMap{Metadata: map[string]string{"snake_key": "x", "CamelKey": "y"}},
+		},
+		{
+			name:     "numeric map keys",
+			src:      `return { counts = { [1] = "one", [5] = "five" } }`,
+			target:   func() any { return &WithIntMap{} },
+			expected: &WithIntMap{Counts: map[int]string{1: "one", 5: "five"}},
+		},
+		{
+			name: "nested structs, slices and maps",
+			src: `return {
+				title = "t",
+				inner = { name = "i", count = 1 },
+				optional = { name = "o", count = 2 },
+				list = { { name = "l1", count = 3 }, { name = "l2", count = 4 } },
+				by_name = { first = { name = "m", count = 5 } },
+				by_index = { [2] = "two" },
+				matrix = { { 1, 2 }, { 3 } },
+				ratio = 0.5,
+				enabled = false,
+				metadata = { tags = { "a", "b" }, empty = {} },
+			}`,
+			target: func() any { return &NestedConfig{} },
+			expected: &NestedConfig{
+				Title:    "t",
+				Inner:    Inner{Name: "i", Count: 1},
+				Optional: &Inner{Name: "o", Count: 2},
+				List:     []Inner{{Name: "l1", Count: 3}, {Name: "l2", Count: 4}},
+				ByName:   map[string]Inner{"first": {Name: "m", Count: 5}},
+				ByIndex:  map[int]string{2: "two"},
+				Matrix:   [][]int{{1, 2}, {3}},
+				Ratio:    0.5,
+				Enabled:  ptr(false),
+				Metadata: map[string][]string{"tags": {"a", "b"}, "empty": {}},
+			},
+		},
+		{
+			name:     "weakly typed values",
+			src:      `return { name = 8080, age = "3", active = "true", score = "2.5" }`,
+			target:   func() any { return &SimplePrimitives{} },
+			expected: &SimplePrimitives{Name: "8080", Age: 3, Active: true, Score: 2.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.target()
+			if err := decode(evalLua(t, tt.src), got); err != nil {
+				t.Fatalf("decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("decode() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		target  any
+		wantErr string
+	}{
+		{"unknown field", `return { nmae = "a" }`, &SimplePrimitives{}, "nmae: unknown field"},
+		{"wrong type", `return { active = {} }`, &SimplePrimitives{}, "active: expected boolean"},
+		{"non-integer", `return { age = 1.5 }`, &SimplePrimitives{}, "age: expected integer"},
+		{"overflow", `return { int8_field = 300 }`, &AllNumericTypes{}, "int8_field: 300 overflows int8"},
+		{"negative unsigned", `return { uint8_field = -1 }`, &AllNumericTypes{}, "overflows uint8"},
+		{"array with holes", `return { tags = { "a", x = "b" } }`, &WithSlice{}, "tags: expected array table"},
+		{
+			"nested unknown field",
+			`return { list = { { name = "a" }, { nmae = "b" } } }`,
+			&NestedConfig{},
+			"list[2].nmae: unknown field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := decode(evalLua(t, tt.src), tt.target)
+			if err == nil {
+				t.Fatalf("decode() expected error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("decode() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateLuaType_Nested(t *testing.T) {
+	got, err := GenerateLuaType(NestedConfig{})
+	if err != nil {
+		t.Fatalf("GenerateLuaType() error = %v", err)
+	}
+	for _, want := range []string{
+		"---@field inner Inner",
+		"---@field optional? Inner",
+		"---@field list Inner[]",
+		"---@field by_name { [string]:Inner }",
+		"---@field by_index table<number, string>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("GenerateLuaType() missing %q in:\n%s", want, got)
+		}
+	}
+
+	types := getZeroValues(NestedConfig{})
+	if len(types) != 2 {
+		t.Fatalf("getZeroValues() returned %d types, want 2", len(types))
+	}
+	if _, ok := types[1].(Inner); !ok {
+		t.Errorf("getZeroValues()[1] = %T, want Inner", types[1])
+	}
+}
+
+func TestExtractComments_Embedded(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	comment, fields, err := extractComments("Data")
+	if err != nil {
+		t.Fatalf("extractComments() error = %v", err)
+	}
+	if comment == "" {
+		t.Error("extractComments() returned empty struct comment outside the repo")
+	}
+	if _, ok := fields["DatabasePath"]; !ok {
+		t.Error("extractComments() missing DatabasePath comment outside the repo")
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	_ "embed"
 	"fmt"
 	"go/ast"
 	"go/parser"
@@ -145,7 +146,6 @@ func luaType(t reflect.Type) (*luaTypeData, error) {
 			return nil, err
 		}
 		return &luaTypeData{value: s.String() + "[]"}, nil
-	// TODO: Verify maps are handled as expected in gluamapper.
 	case reflect.Map:
 		keyS, err := luaType(t.Key())
 		if err != nil {
@@ -172,12 +172,19 @@ func luaType(t reflect.Type) (*luaTypeData, error) {
 	}
 }
 
+// dataSource is the source of data.go, embedded so that doc comments are
+// available to the generators regardless of the working directory. All config
+// types, including nested ones, must be declared in data.go.
+//
+//go:embed data.go
+var dataSource []byte
+
 // extractComments parses the source file to extract struct and field comments for a struct type
 func extractComments(typeName string) (string, map[string]string, error) {
 	fset := token.NewFileSet()
 
-	// Parse the data.go file which contains the Data struct
-	node, err := parser.ParseFile(fset, "internal/config/data.go", nil, parser.ParseComments)
+	// Parse the embedded data.go file which contains the Data struct
+	node, err := parser.ParseFile(fset, "data.go", dataSource, parser.ParseComments)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to parse data.go: %w", err)
 	}
@@ -248,6 +255,20 @@ func extractComments(typeName string) (string, map[string]string, error) {
 	return structComment, fieldComments, nil
 }
 
+// exportedFields returns the fields of the struct type t that are visible to
+// Lua. Unexported (lowercase) fields are skipped.
+func exportedFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if len(f.Name) > 0 && !unicode.IsUpper(rune(f.Name[0])) {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
 func GenerateLuaType(v any) (string, error) {
 	t := reflect.TypeOf(v)
 	typeName := t.Name()
@@ -270,14 +291,7 @@ func GenerateLuaType(v any) (string, error) {
 
 	lines = append(lines, fmt.Sprintf("---@class %s", typeName))
 
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-
-		// Skip unexported (lowercase) fields as they are hidden from Lua
-		if len(f.Name) > 0 && !unicode.IsUpper(rune(f.Name[0])) {
-			continue
-		}
-
+	for _, f := range exportedFields(t) {
 		luaTypeData, err := luaType(f.Type)
 		if err != nil {
 			return "", fmt.Errorf("lines so far:\n%s\nfield %s: %w", strings.Join(lines, "\n"), f.Name, err)
@@ -303,23 +317,27 @@ func GenerateLuaType(v any) (string, error) {
 
 func getZeroValues(v any) []any {
 	seen := make(map[reflect.Type]bool)
-	return getZeroValuesWithSeen(v, seen)
+	return getZeroValuesWithSeen(reflect.TypeOf(v), seen)
 }
 
-func getZeroValuesWithSeen(v any, seen map[reflect.Type]bool) []any {
+// getZeroValuesWithSeen returns the zero value of t, if t is a struct, followed
+// by the zero values of all struct types reachable from its fields through
+// pointers, slices and maps.
+func getZeroValuesWithSeen(t reflect.Type, seen map[reflect.Type]bool) []any {
 	var zeroValues []any
-	val := reflect.ValueOf(v)
 
-	// Ensure we are working with the value of the struct
-	if val.Kind() == reflect.Pointer {
-		val = val.Elem()
-	}
-	if val.Kind() != reflect.Struct {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice:
+		return getZeroValuesWithSeen(t.Elem(), seen)
+	case reflect.Map:
+		zeroValues = append(zeroValues, getZeroValuesWithSeen(t.Key(), seen)...)
+		return append(zeroValues, getZeroValuesWithSeen(t.Elem(), seen)...)
+	case reflect.Struct:
+	default:
 		return zeroValues
 	}
 
 	// Skip if we've already seen this type
-	t := val.Type()
 	if seen[t] {
 		return zeroValues
 	}
@@ -329,16 +347,8 @@ func getZeroValuesWithSeen(v any, seen map[reflect.Type]bool) []any {
 	zeroValues = append(zeroValues, reflect.New(t).Elem().Interface())
 
 	// Iterate through fields to find nested structs
-	for i := 0; i < val.NumField(); i++ {
-		field := val.Field(i)
-		fieldType := val.Type().Field(i)
-
-		// Check if the field is a struct or a pointer to a struct
-		if field.Kind() == reflect.Struct {
-			zeroValues = append(zeroValues, getZeroValuesWithSeen(field.Interface(), seen)...)
-		} else if field.Kind() == reflect.Pointer && fieldType.Type.Elem().Kind() == reflect.Struct {
-			zeroValues = append(zeroValues, getZeroValuesWithSeen(reflect.New(fieldType.Type.Elem()).Elem().Interface(), seen)...)
-		}
+	for _, f := range exportedFields(t) {
+		zeroValues = append(zeroValues, getZeroValuesWithSeen(f.Type, seen)...)
 	}
 
 	return zeroValues
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/RyRose/uplog/internal/sqlc/dbtx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Metrics holds domain metrics registered on State.PrometheusRegistry.
+type Metrics struct {
+	// ProgressLogged counts progress entries logged, labeled by the lift group
+	// of the lift. Lifts without a group are labeled "none".
+	ProgressLogged *prometheus.CounterVec
+}
+
+// fileSize returns the size of the file at path, or 0 if it does not exist.
+func fileSize(path string) float64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return float64(info.Size())
+}
+
+// registerMetrics registers database and domain metrics on reg.
+func registerMetrics(reg prometheus.Registerer, dbPath string, rDB, wDB *sql.DB) (*Metrics, error) {
+	m := &Metrics{
+		ProgressLogged: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "uplog",
+			Name:      "progress_logged_total",
+			Help:      "Progress entries logged by lift group.",
+		}, []string{"lift_group"}),
+	}
+
+	cs := []prometheus.Collector{
+		m.ProgressLogged,
+		collectors.NewDBStatsCollector(rDB, "read"),
+		collectors.NewDBStatsCollector(wDB, "write"),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "uplog",
+			Subsystem: "db",
+			Name:      "file_size_bytes",
+			Help:      "Size of the SQLite database file.",
+		}, func() float64 { return fileSize(dbPath) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "uplog",
+			Subsystem: "db",
+			Name:      "wal_size_bytes",
+			Help:      "Size of the SQLite write-ahead log.",
+		}, func() float64 { return fileSize(dbPath + "-wal") }),
+	}
+	cs = append(cs, dbtx.Collectors()...)
+	for _, c := range cs {
+		if err := reg.Register(c); err != nil {
+			return nil, fmt.Errorf("failed to register metrics: %w", err)
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,272 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// EnvPrefix prefixes environment variables that override config values. The
+// rest of the name is the upper-cased path of the value with dots replaced by
+// underscores, e.g. UPLOG_DATABASE_PATH. UPLOG_PROFILE selects the profile.
+const EnvPrefix = "UPLOG_"
+
+// Layers that a config value may come from, from lowest to highest precedence.
+const (
+	LayerBase    = "base"
+	LayerProfile = "profile"
+	LayerFlag    = "flag"
+	LayerEnv     = "env"
+)
+
+// Source describes the layers the configuration is resolved from. Values from
+// later layers take precedence: env > flag > profile > base.
+type Source struct {
+	// Path is the base Lua config file.
+	Path string
+	// Profile names an overlay in the profiles directory next to Path, e.g.
+	// "dev" loads config/profiles/dev.lua. Tables in the overlay are merged into
+	// the base recursively. Empty means no overlay.
+	Profile string
+	// Overrides maps dotted lower_snake_case value paths, e.g. "port", to
+	// values typically set by command-line flags.
+	Overrides map[string]string
+	// Environ is consulted for overrides prefixed with EnvPrefix, in the form
+	// returned by os.Environ. If nil, os.Environ is used.
+	Environ []string
+}
+
+// Origins maps the dotted lower_snake_case path of each value set while
+// resolving the configuration to the layer that set it.
+type Origins map[string]string
+
+func (s *Source) environ() map[string]string {
+	environ := s.Environ
+	if environ == nil {
+		environ = os.Environ()
+	}
+	env := make(map[string]string)
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(k, EnvPrefix) {
+			env[k] = v
+		}
+	}
+	return env
+}
+
+// profilePath returns the overlay file for the named profile.
+func (s *Source) profilePath(profile string) (string, error) {
+	if !filepath.IsLocal(profile) || strings.ContainsAny(profile, `/\`) {
+		return "", fmt.Errorf("invalid profile name %q", profile)
+	}
+	return filepath.Join(filepath.Dir(s.Path), "profiles", profile+".lua"), nil
+}
+
+// Resolve loads the configuration described by src in the DefaultSandbox and
+// reports which layer each value came from.
+func Resolve(ctx context.Context, src Source) (*Data, Origins, error) {
+	return ResolveWithSandbox(ctx, DefaultSandbox(), src)
+}
+
+// ResolveWithSandbox loads the configuration described by src in sandbox and
+// reports which layer each value came from.
+func ResolveWithSandbox(ctx context.Context, sandbox *Sandbox, src Source) (*Data, Origins, error) {
+	env := src.environ()
+	profile, profileLayer := src.Profile, LayerFlag
+	if p, ok := env[EnvPrefix+"PROFILE"]; ok {
+		profile, profileLayer = p, LayerEnv
+	}
+
+	paths := []string{src.Path}
+	if profile != "" {
+		path, err := src.profilePath(profile)
+		if err != nil {
+			return nil, nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	var data Data
+	origins := make(Origins)
+	err := sandbox.run(ctx, paths, func(L *lua.LState, values []lua.LValue) error {
+		root := values[0].(*lua.LTable)
+		recordOrigins(root, "", LayerBase, origins)
+		if len(values) > 1 {
+			mergeTables(root, values[1].(*lua.LTable), "", LayerProfile+":"+profile, origins)
+		}
+
+		for key, value := range src.Overrides {
+			lv, err := overrideValue(L, reflect.TypeOf(data), key, value)
+			if err != nil {
+				return fmt.Errorf("override %s: %w", key, err)
+			}
+			if err := setPath(L, root, key, lv); err != nil {
+				return fmt.Errorf("override %s: %w", key, err)
+			}
+			origins[key] = LayerFlag
+		}
+		for _, key := range valuePaths(reflect.TypeOf(data), "") {
+			name := EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+			if value, ok := env[name]; ok {
+				lv, err := overrideValue(L, reflect.TypeOf(data), key, value)
+				if err != nil {
+					return fmt.Errorf("override %s: %w", name, err)
+				}
+				if err := setPath(L, root, key, lv); err != nil {
+					return fmt.Errorf("override %s: %w", name, err)
+				}
+				origins[key] = LayerEnv
+			}
+		}
+
+		if err := decode(root, &data); err != nil {
+			return fmt.Errorf("%s: %w", strings.Join(paths, " + "), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if profile != "" {
+		origins["profile"] = profileLayer
+	}
+	return &data, origins, nil
+}
+
+// isRecord reports whether lv should be merged key by key rather than
+// replaced as a whole, i.e. whether it is a non-empty table without array
+// entries.
+func isRecord(lv lua.LValue) bool {
+	tbl, ok := lv.(*lua.LTable)
+	if !ok || tbl.MaxN() != 0 {
+		return false
+	}
+	k, _ := tbl.Next(lua.LNil)
+	return k != lua.LNil
+}
+
+func recordOrigins(lv lua.LValue, path, layer string, origins Origins) {
+	if !isRecord(lv) {
+		if path != "" {
+			origins[path] = layer
+		}
+		return
+	}
+	lv.(*lua.LTable).ForEach(func(k, v lua.LValue) {
+		recordOrigins(v, decodePath(path, k.String()), layer, origins)
+	})
+}
+
+// mergeTables recursively merges src into dst. Records are merged key by key
+// while any other value in src replaces the one in dst.
+func mergeTables(dst, src *lua.LTable, path, layer string, origins Origins) {
+	src.ForEach(func(k, v lua.LValue) {
+		keyPath := decodePath(path, k.String())
+		if existing := dst.RawGet(k); isRecord(existing) && isRecord(v) {
+			mergeTables(existing.(*lua.LTable), v.(*lua.LTable), keyPath, layer, origins)
+			return
+		}
+		for p := range origins {
+			if p == keyPath || strings.HasPrefix(p, keyPath+".") {
+				delete(origins, p)
+			}
+		}
+		dst.RawSet(k, v)
+		recordOrigins(v, keyPath, layer, origins)
+	})
+}
+
+// overrideValue converts value overriding the dotted path within struct type
+// t to Lua. Slices are comma-separated, e.g. "10.0.0.1, 10.0.0.2", and an
+// empty value is an empty slice. Maps cannot be overridden from a string.
+// Paths that are not in t are left as strings for decoding to reject.
+func overrideValue(L *lua.LState, t reflect.Type, path, value string) (lua.LValue, error) {
+	ft, ok := pathType(t, path)
+	if !ok {
+		return lua.LString(value), nil
+	}
+	switch ft.Kind() {
+	case reflect.Slice:
+		tbl := L.NewTable()
+		for item := range strings.SplitSeq(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				tbl.Append(lua.LString(item))
+			}
+		}
+		return tbl, nil
+	case reflect.Map, reflect.Struct:
+		return nil, fmt.Errorf("%s is a %s and cannot be overridden by a string", path, ft.Kind())
+	}
+	return lua.LString(value), nil
+}
+
+// pathType returns the type of the value at the dotted lower_snake_case path
+// within struct type t, dereferencing pointers.
+func pathType(t reflect.Type, path string) (reflect.Type, bool) {
+	for key := range strings.SplitSeq(path, ".") {
+		if t.Kind() != reflect.Struct {
+			return nil, false
+		}
+		var found bool
+		for _, f := range exportedFields(t) {
+			if toSnakeCase(f.Name) == key {
+				t, found = f.Type, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+	}
+	return t, true
+}
+
+// setPath sets the value at the dotted path within root, creating intermediate
+// tables as needed.
+func setPath(L *lua.LState, root *lua.LTable, path string, value lua.LValue) error {
+	keys := strings.Split(path, ".")
+	tbl := root
+	for _, key := range keys[:len(keys)-1] {
+		switch next := tbl.RawGetString(key).(type) {
+		case *lua.LTable:
+			tbl = next
+		case *lua.LNilType:
+			created := L.NewTable()
+			tbl.RawSetString(key, created)
+			tbl = created
+		default:
+			return fmt.Errorf("%s is a %s, not a table", key, next.Type())
+		}
+	}
+	tbl.RawSetString(keys[len(keys)-1], value)
+	return nil
+}
+
+// valuePaths lists the dotted lower_snake_case paths of the values in struct
+// type t that can be overridden from the environment. Nested structs are
+// expanded; all other fields are a single value, with slices given as
+// comma-separated lists.
+func valuePaths(t reflect.Type, prefix string) []string {
+	var paths []string
+	for _, f := range exportedFields(t) {
+		path := decodePath(prefix, toSnakeCase(f.Name))
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			paths = append(paths, valuePaths(ft, path)...)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
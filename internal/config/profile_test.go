@@ -0,0 +1,241 @@
+package config
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+const testBaseConfig = `
+return {
+	mode = "prod",
+	debug = false,
+	database_path = "base.db",
+	port = "8080",
+	swagger_url = "http://localhost",
+	first_day_of_week = 0,
+}
+`
+
+func TestResolve_Precedence(t *testing.T) {
+	writeFiles(t, map[string]string{
+		"config/main.lua":          testBaseConfig,
+		"config/profiles/dev.lua":  `return { mode = "dev", debug = true, port = "1000" }`,
+		"config/profiles/prod.lua": `return { mode = "prod" }`,
+	})
+
+	tests := []struct {
+		name        string
+		src         Source
+		wantMode    string
+		wantPort    string
+		wantDebug   bool
+		wantOrigins map[string]string
+	}{
+		{
+			name:     "base only",
+			src:      Source{Path: "config/main.lua", Environ: []string{}},
+			wantMode: "prod",
+			wantPort: "8080",
+			wantOrigins: map[string]string{
+				"mode": LayerBase,
+				"port": LayerBase,
+			},
+		},
+		{
+			name:      "profile over base",
+			src:       Source{Path: "config/main.lua", Profile: "dev", Environ: []string{}},
+			wantMode:  "dev",
+			wantPort:  "1000",
+			wantDebug: true,
+			wantOrigins: map[string]string{
+				"mode":          "profile:dev",
+				"port":          "profile:dev",
+				"database_path": LayerBase,
+				"profile":       LayerFlag,
+			},
+		},
+		{
+			name: "flag over profile",
+			src: Source{
+				Path:      "config/main.lua",
+				Profile:   "dev",
+				Overrides: map[string]string{"port": "2000", "debug": "false"},
+				Environ:   []string{},
+			},
+			wantMode: "dev",
+			wantPort: "2000",
+			wantOrigins: map[string]string{
+				"port":  LayerFlag,
+				"debug": LayerFlag,
+			},
+		},
+		{
+			name: "env over flag",
+			src: Source{
+				Path:      "config/main.lua",
+				Profile:   "dev",
+				Overrides: map[string]string{"port": "2000"},
+				Environ:   []string{"UPLOG_PORT=3000", "PORT=4000", "UPLOG_PROFILE=prod"},
+			},
+			wantMode: "prod",
+			wantPort: "3000",
+			wantOrigins: map[string]string{
+				"port":    LayerEnv,
+				"mode":    "profile:prod",
+				"profile": LayerEnv,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, origins, err := Resolve(context.Background(), tt.src)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if cfg.Mode != tt.wantMode {
+				t.Errorf("Mode = %q, want %q", cfg.Mode, tt.wantMode)
+			}
+			if cfg.Port != tt.wantPort {
+				t.Errorf("Port = %q, want %q", cfg.Port, tt.wantPort)
+			}
+			if cfg.Debug != tt.wantDebug {
+				t.Errorf("Debug = %v, want %v", cfg.Debug, tt.wantDebug)
+			}
+			for key, want := range tt.wantOrigins {
+				if origins[key] != want {
+					t.Errorf("origins[%q] = %q, want %q", key, origins[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestResolve_Lists(t *testing.T) {
+	writeFiles(t, map[string]string{"config/main.lua": testBaseConfig})
+
+	tests := []struct {
+		name string
+		src  Source
+		want []string
+	}{
+		{
+			name: "flag",
+			src: Source{
+				Path:      "config/main.lua",
+				Overrides: map[string]string{"audit.trusted_proxies": "10.0.0.1"},
+				Environ:   []string{},
+			},
+			want: []string{"10.0.0.1"},
+		},
+		{
+			name: "env",
+			src: Source{
+				Path:    "config/main.lua",
+				Environ: []string{"UPLOG_AUDIT_TRUSTED_PROXIES=10.0.0.1, 192.168.0.0/16,"},
+			},
+			want: []string{"10.0.0.1", "192.168.0.0/16"},
+		},
+		{
+			name: "empty",
+			src: Source{
+				Path:    "config/main.lua",
+				Environ: []string{"UPLOG_AUDIT_TRUSTED_PROXIES="},
+			},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, _, err := Resolve(context.Background(), tt.src)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if !slices.Equal(cfg.Audit.TrustedProxies, tt.want) {
+				t.Errorf("TrustedProxies = %q, want %q", cfg.Audit.TrustedProxies, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve_Errors(t *testing.T) {
+	writeFiles(t, map[string]string{"config/main.lua": testBaseConfig})
+
+	tests := []struct {
+		name string
+		src  Source
+	}{
+		{"missing profile", Source{Path: "config/main.lua", Profile: "staging", Environ: []string{}}},
+		{"profile traversal", Source{Path: "config/main.lua", Profile: "../main", Environ: []string{}}},
+		{"unknown override", Source{Path: "config/main.lua", Overrides: map[string]string{"nope": "1"}, Environ: []string{}}},
+		{"override through scalar", Source{Path: "config/main.lua", Overrides: map[string]string{"port.x": "1"}, Environ: []string{}}},
+		{"bad env value", Source{Path: "config/main.lua", Environ: []string{"UPLOG_FIRST_DAY_OF_WEEK=monday"}}},
+		{"override table", Source{Path: "config/main.lua", Overrides: map[string]string{"database": "x"}, Environ: []string{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Resolve(context.Background(), tt.src); err == nil {
+				t.Error("Resolve() expected error")
+			}
+		})
+	}
+}
+
+func TestMergeTables_Nested(t *testing.T) {
+	base := evalLua(t, `return { inner = { name = "a", count = 1 }, list = { { name = "x" } } }`).(*lua.LTable)
+	overlay := evalLua(t, `return { inner = { count = 2 }, list = { { name = "y" }, { name = "z" } } }`).(*lua.LTable)
+
+	origins := make(Origins)
+	recordOrigins(base, "", LayerBase, origins)
+	mergeTables(base, overlay, "", LayerProfile, origins)
+
+	var got NestedConfig
+	if err := decode(base, &got); err != nil {
+		t.Fatalf("decode() error = %v", err)
+	}
+	if got.Inner != (Inner{Name: "a", Count: 2}) {
+		t.Errorf("Inner = %+v, want records merged key by key", got.Inner)
+	}
+	if len(got.List) != 2 || got.List[0].Name != "y" {
+		t.Errorf("List = %+v, want arrays replaced", got.List)
+	}
+	want := Origins{"inner.name": LayerBase, "inner.count": LayerProfile, "list": LayerProfile}
+	if !maps.Equal(origins, want) {
+		t.Errorf("origins = %v, want %v", origins, want)
+	}
+}
+
+func TestResolve_RepoProfiles(t *testing.T) {
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("os.Getwd() error = %v", err)
+	}
+	t.Chdir(filepath.Join(origDir, "..", ".."))
+
+	for _, profile := range []string{"", ModeDev, ModeTest, ModeProd} {
+		t.Run(profile, func(t *testing.T) {
+			cfg, _, err := Resolve(context.Background(), Source{
+				Path:    "./config/main.lua",
+				Profile: profile,
+				Environ: []string{},
+			})
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+			if profile != "" && cfg.Mode != profile {
+				t.Errorf("Mode = %q, want %q", cfg.Mode, profile)
+			}
+		})
+	}
+}
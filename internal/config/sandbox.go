@@ -0,0 +1,276 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/metrics"
+	"slices"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ErrMemoryLimit is the cause of a Lua evaluation that was stopped for
+// exceeding Sandbox.MaxHeapGrowth.
+var ErrMemoryLimit = errors.New("lua memory limit exceeded")
+
+// Sandbox restricts the environment Lua scripts are evaluated in. The zero
+// value opens no optional libraries and applies no limits; use DefaultSandbox
+// for settings suitable for configuration files.
+type Sandbox struct {
+	// Libraries lists the optional standard libraries available to scripts,
+	// e.g. lua.StringLibName. The base and package libraries are always opened,
+	// without dofile and loadfile. The os library only exposes functions that
+	// read the environment or the clock.
+	Libraries []string
+	// RequireRoot is the only directory require() may load modules from.
+	// Module names are resolved relative to it, so "lib.env" maps to
+	// lib/env.lua within RequireRoot. If empty, it is the directory of the
+	// first file evaluated, so modules next to the configuration file load
+	// wherever the process runs from and whatever its directory is named.
+	RequireRoot string
+	// Timeout bounds how long evaluation may run in addition to any deadline
+	// already on the context. Zero means no additional bound.
+	Timeout time.Duration
+	// CallStackSize bounds the depth of the Lua call stack.
+	CallStackSize int
+	// RegistryMaxSize bounds the size of the Lua data stack.
+	RegistryMaxSize int
+	// MaxHeapGrowth bounds how many bytes the Go heap may grow by while a
+	// script runs. The heap is shared with the rest of the process, so this is
+	// an approximation. Zero disables the check.
+	MaxHeapGrowth uint64
+}
+
+// DefaultSandbox returns the sandbox used to evaluate configuration files.
+func DefaultSandbox() *Sandbox {
+	return &Sandbox{
+		Libraries: []string{
+			lua.TabLibName,
+			lua.StringLibName,
+			lua.MathLibName,
+			lua.OsLibName,
+		},
+		Timeout:         5 * time.Second,
+		CallStackSize:   lua.CallStackSize,
+		RegistryMaxSize: lua.RegistrySize * 4,
+		MaxHeapGrowth:   256 << 20,
+	}
+}
+
+// osAllowed lists the functions of the os library available in the sandbox.
+var osAllowed = []string{"clock", "date", "difftime", "getenv", "time"}
+
+func (s *Sandbox) newState(requireRoot string) (*lua.LState, error) {
+	L := lua.NewState(lua.Options{
+		SkipOpenLibs:    true,
+		CallStackSize:   s.CallStackSize,
+		RegistryMaxSize: s.RegistryMaxSize,
+	})
+
+	open := map[string]lua.LGFunction{
+		lua.TabLibName:       lua.OpenTable,
+		lua.IoLibName:        lua.OpenIo,
+		lua.OsLibName:        lua.OpenOs,
+		lua.StringLibName:    lua.OpenString,
+		lua.MathLibName:      lua.OpenMath,
+		lua.DebugLibName:     lua.OpenDebug,
+		lua.ChannelLibName:   lua.OpenChannel,
+		lua.CoroutineLibName: lua.OpenCoroutine,
+	}
+	libs := []string{lua.LoadLibName, lua.BaseLibName}
+	open[lua.LoadLibName] = lua.OpenPackage
+	open[lua.BaseLibName] = lua.OpenBase
+	for _, name := range s.Libraries {
+		if _, ok := open[name]; !ok {
+			L.Close()
+			return nil, fmt.Errorf("unknown lua library %q", name)
+		}
+		libs = append(libs, name)
+	}
+	for _, name := range libs {
+		L.Push(L.NewFunction(open[name]))
+		L.Push(lua.LString(name))
+		L.Call(1, 0)
+	}
+
+	// Only allow loading files through require() so RequireRoot is enforced.
+	L.SetGlobal("dofile", lua.LNil)
+	L.SetGlobal("loadfile", lua.LNil)
+
+	if os, ok := L.GetGlobal(lua.OsLibName).(*lua.LTable); ok {
+		os.ForEach(func(k, _ lua.LValue) {
+			if !slices.Contains(osAllowed, k.String()) {
+				L.SetField(os, k.String(), lua.LNil)
+			}
+		})
+	}
+
+	pkg := L.GetGlobal(lua.LoadLibName).(*lua.LTable)
+	L.SetField(pkg, "path", lua.LString(""))
+	L.SetField(pkg, "cpath", lua.LString(""))
+	L.SetField(pkg, "loadlib", lua.LNil)
+	// The loaders table is shared with the registry, so it is modified in
+	// place rather than replaced.
+	loaders := L.GetField(pkg, "loaders").(*lua.LTable)
+	for loaders.Len() > 0 {
+		loaders.Remove(loaders.Len())
+	}
+	loaders.Append(L.NewFunction(requireLoader(requireRoot)))
+	return L, nil
+}
+
+// requireLoader returns a package.loaders entry that only finds modules within
+// root.
+func requireLoader(root string) lua.LGFunction {
+	return func(L *lua.LState) int {
+		name := L.CheckString(1)
+		file := filepath.Join(root, filepath.FromSlash(strings.ReplaceAll(name, ".", "/"))+".lua")
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			L.RaiseError("failed to resolve require root %s: %v", root, err)
+		}
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			L.RaiseError("failed to resolve module %s: %v", name, err)
+		}
+		if rel, err := filepath.Rel(absRoot, abs); err != nil || !filepath.IsLocal(rel) {
+			L.Push(lua.LString(fmt.Sprintf("\n\tmodule '%s' is outside of %s", name, root)))
+			return 1
+		}
+		if _, err := os.Stat(file); err != nil {
+			L.Push(lua.LString(fmt.Sprintf("\n\tno file '%s'", file)))
+			return 1
+		}
+
+		fn, err := L.LoadFile(file)
+		if err != nil {
+			L.RaiseError("%s", err.Error())
+		}
+		L.Push(fn)
+		return 1
+	}
+}
+
+// watchHeap cancels ctx with ErrMemoryLimit if the Go heap grows by more than
+// limit bytes before ctx is done.
+func watchHeap(ctx context.Context, cancel context.CancelCauseFunc, limit uint64) {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return
+	}
+	start := sample[0].Value.Uint64()
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			metrics.Read(sample)
+			if heap := sample[0].Value.Uint64(); heap > start && heap-start > limit {
+				cancel(ErrMemoryLimit)
+				return
+			}
+		}
+	}
+}
+
+// Eval evaluates the Lua file at path within the sandbox and decodes the
+// value it returns into v, which must be a pointer.
+func (s *Sandbox) Eval(ctx context.Context, path string, v any) error {
+	return s.run(ctx, []string{path}, func(_ *lua.LState, values []lua.LValue) error {
+		if err := decode(values[0], v); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// run evaluates the Lua files at paths in order within a single sandboxed
+// state. Each file must return a table. The returned tables are passed to fn
+// before the state is closed.
+func (s *Sandbox) run(ctx context.Context, paths []string, fn func(*lua.LState, []lua.LValue) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	if s.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, s.Timeout)
+		defer cancelTimeout()
+	}
+	if s.MaxHeapGrowth > 0 {
+		go watchHeap(ctx, cancel, s.MaxHeapGrowth)
+	}
+
+	requireRoot := s.RequireRoot
+	if requireRoot == "" {
+		requireRoot = filepath.Dir(paths[0])
+	}
+	L, err := s.newState(requireRoot)
+	if err != nil {
+		return err
+	}
+	defer L.Close()
+	L.SetContext(ctx)
+
+	var values []lua.LValue
+	for _, path := range paths {
+		if err := L.DoFile(path); err != nil {
+			return evalError(ctx, path, err)
+		}
+		lv := L.Get(-1)
+		L.SetTop(0)
+		if lv.Type() != lua.LTTable {
+			return fmt.Errorf("%s must return a table, got %s", path, lv.Type())
+		}
+		values = append(values, lv)
+	}
+	return fn(L, values)
+}
+
+// evalError describes a failed evaluation of the Lua file at path. Lua
+// messages already carry the file and line of the failure; the first frame of
+// the traceback is added for errors raised from Go functions.
+func evalError(ctx context.Context, path string, err error) error {
+	var apiErr *lua.ApiError
+	if !errors.As(err, &apiErr) {
+		return fmt.Errorf("failed to evaluate %s: %w", path, err)
+	}
+
+	msg := strings.TrimSpace(apiErr.Object.String())
+	switch apiErr.Type {
+	case lua.ApiErrorSyntax:
+		return fmt.Errorf("syntax error in %s: %s", path, msg)
+	case lua.ApiErrorFile:
+		return fmt.Errorf("failed to read %s: %w", path, apiErr.Cause)
+	}
+
+	if frame := luaFrame(apiErr.StackTrace); frame != "" && !strings.HasPrefix(msg, frame) {
+		msg = frame + ": " + msg
+	}
+	if cause := context.Cause(ctx); cause != nil {
+		return fmt.Errorf("failed to evaluate %s: %w: %s", path, cause, msg)
+	}
+	return fmt.Errorf("failed to evaluate %s: %s", path, msg)
+}
+
+// luaFrame returns the "file:line" location of the innermost Lua frame in a
+// gopher-lua traceback.
+func luaFrame(trace string) string {
+	for _, line := range strings.Split(trace, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "stack traceback") || strings.HasPrefix(line, "[G]") {
+			continue
+		}
+		if loc, _, ok := strings.Cut(line, ": in "); ok {
+			return loc
+		}
+	}
+	return ""
+}
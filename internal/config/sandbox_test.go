@@ -0,0 +1,194 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// writeFiles writes files relative to a temporary directory and changes into
+// it for the duration of the test.
+func writeFiles(t *testing.T, files map[string]string) {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("os.MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("os.WriteFile() error = %v", err)
+		}
+	}
+	t.Chdir(dir)
+}
+
+func TestSandbox_Eval(t *testing.T) {
+	writeFiles(t, map[string]string{
+		"config/main.lua": `
+			local lib = require("lib.name")
+			return { name = lib.name, age = math.floor(os.time() / os.time()) }
+		`,
+		"config/lib/name.lua": `return { name = string.upper("uplog") }`,
+	})
+
+	var got SimplePrimitives
+	if err := DefaultSandbox().Eval(context.Background(), "config/main.lua", &got); err != nil {
+		t.Fatalf("Eval() error = %v", err)
+	}
+	if got.Name != "UPLOG" || got.Age != 1 {
+		t.Errorf("Eval() = %+v, want name UPLOG and age 1", got)
+	}
+}
+
+// TestSandbox_EvalElsewhere checks that modules next to the configuration file
+// are found when it is evaluated from another directory.
+func TestSandbox_EvalElsewhere(t *testing.T) {
+	writeFiles(t, map[string]string{
+		"config/main.lua":        `return require("lib.name")`,
+		"config/lib/name.lua":    `return { name = "uplog" }`,
+		"elsewhere/lib/name.lua": `return { name = "wrong" }`,
+	})
+	t.Chdir("elsewhere")
+
+	var got SimplePrimitives
+	if err := DefaultSandbox().Eval(context.Background(), "../config/main.lua", &got); err != nil {
+		t.Fatalf("Eval() error = %v", err)
+	}
+	if got.Name != "uplog" {
+		t.Errorf("Eval() = %+v, want name uplog", got)
+	}
+}
+
+// TestSandbox_EvalRenamedDirectory checks that modules are found relative to
+// the directory of the configuration file whatever it is named.
+func TestSandbox_EvalRenamedDirectory(t *testing.T) {
+	writeFiles(t, map[string]string{
+		"etc/uplog/main.lua":     `return require("lib.name")`,
+		"etc/uplog/lib/name.lua": `return { name = "uplog" }`,
+	})
+
+	var got SimplePrimitives
+	if err := DefaultSandbox().Eval(context.Background(), "etc/uplog/main.lua", &got); err != nil {
+		t.Fatalf("Eval() error = %v", err)
+	}
+	if got.Name != "uplog" {
+		t.Errorf("Eval() = %+v, want name uplog", got)
+	}
+}
+
+func TestSandbox_Restrictions(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{"os.execute removed", `os.execute("true")`, "config/main.lua:1:"},
+		{"os.remove removed", `os.remove("config/main.lua")`, "config/main.lua:1:"},
+		{"io not opened", `io.open("config/main.lua")`, "config/main.lua:1:"},
+		{"dofile removed", `dofile("secret.lua")`, "config/main.lua:1:"},
+		{"loadfile removed", `loadfile("secret.lua")`, "config/main.lua:1:"},
+		{"require outside root", `require("../secret")`, "no file"},
+		{"require absolute path", `require("/etc/passwd")`, "no file"},
+		{"require missing", `require("missing")`, "no file"},
+		{"runtime error line", "local x = 1\nlocal y = nil + x", "config/main.lua:2:"},
+		{"syntax error", "return {", "syntax error in config/main.lua"},
+		{"not a table", "return 1", "must return a table"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeFiles(t, map[string]string{
+				"config/main.lua": tt.src,
+				"secret.lua":      `return {}`,
+			})
+			var got SimplePrimitives
+			err := DefaultSandbox().Eval(context.Background(), "config/main.lua", &got)
+			if err == nil {
+				t.Fatalf("Eval() expected error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Eval() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSandbox_Libraries(t *testing.T) {
+	writeFiles(t, map[string]string{"config/main.lua": `return { name = type(io) }`})
+
+	sandbox := DefaultSandbox()
+	sandbox.Libraries = append(sandbox.Libraries, lua.IoLibName)
+	var got SimplePrimitives
+	if err := sandbox.Eval(context.Background(), "config/main.lua", &got); err != nil {
+		t.Fatalf("Eval() error = %v", err)
+	}
+	if got.Name != "table" {
+		t.Errorf("type(io) = %q, want table", got.Name)
+	}
+
+	sandbox.Libraries = []string{"nope"}
+	if err := sandbox.Eval(context.Background(), "config/main.lua", &got); err == nil {
+		t.Error("Eval() expected error for unknown library")
+	}
+}
+
+func TestSandbox_Timeout(t *testing.T) {
+	writeFiles(t, map[string]string{"config/main.lua": "local x = 0\nwhile true do x = x + 1 end"})
+
+	sandbox := DefaultSandbox()
+	sandbox.Timeout = 50 * time.Millisecond
+	var got SimplePrimitives
+	err := sandbox.Eval(context.Background(), "config/main.lua", &got)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Eval() error = %v, want context.DeadlineExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "config/main.lua:2:") {
+		t.Errorf("Eval() error = %v, want it to point at config/main.lua:2", err)
+	}
+}
+
+func TestSandbox_ContextDeadline(t *testing.T) {
+	writeFiles(t, map[string]string{"config/main.lua": "while true do end"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var got SimplePrimitives
+	if err := DefaultSandbox().Eval(ctx, "config/main.lua", &got); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Eval() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestSandbox_MemoryLimit(t *testing.T) {
+	writeFiles(t, map[string]string{"config/main.lua": `
+		local t = {}
+		for i = 1, 1e9 do t[i] = tostring(i) end
+	`})
+
+	sandbox := DefaultSandbox()
+	sandbox.MaxHeapGrowth = 16 << 20
+	sandbox.Timeout = 30 * time.Second
+	var got SimplePrimitives
+	if err := sandbox.Eval(context.Background(), "config/main.lua", &got); !errors.Is(err, ErrMemoryLimit) {
+		t.Fatalf("Eval() error = %v, want ErrMemoryLimit", err)
+	}
+}
+
+func TestSandbox_CallStackLimit(t *testing.T) {
+	writeFiles(t, map[string]string{"config/main.lua": `
+		local function f(n) return 1 + f(n + 1) end
+		return { age = f(1) }
+	`})
+
+	var got SimplePrimitives
+	err := DefaultSandbox().Eval(context.Background(), "config/main.lua", &got)
+	if err == nil || !strings.Contains(err.Error(), "stack overflow") {
+		t.Fatalf("Eval() error = %v, want stack overflow", err)
+	}
+}
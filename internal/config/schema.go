@@ -0,0 +1,175 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// jsonSchema is the subset of JSON Schema (draft 2020-12) needed to describe
+// the configuration types.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+}
+
+func jsonSchemaType(t reflect.Type) (*jsonSchema, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return &jsonSchema{Type: "string"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}, nil
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}, nil
+	case reflect.Slice:
+		items, err := jsonSchemaType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &jsonSchema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if _, err := jsonSchemaType(t.Key()); err != nil {
+			return nil, fmt.Errorf("map key type: %w", err)
+		}
+		elem, err := jsonSchemaType(t.Elem())
+		if err != nil {
+			return nil, fmt.Errorf("map value type: %w", err)
+		}
+		return &jsonSchema{Type: "object", AdditionalProperties: elem}, nil
+	case reflect.Struct:
+		return &jsonSchema{Ref: "#/$defs/" + t.Name()}, nil
+	case reflect.Pointer:
+		s, err := jsonSchemaType(t.Elem())
+		if err != nil {
+			return nil, fmt.Errorf("pointer to %v: %w", t.Elem(), err)
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unsupported type: %v", t)
+	}
+}
+
+// generateJSONSchemaObject describes the struct v as a JSON Schema object using
+// the same field names and comments as GenerateLuaType.
+func generateJSONSchemaObject(v any) (*jsonSchema, error) {
+	t := reflect.TypeOf(v)
+
+	structComment, fieldComments, err := extractComments(t.Name())
+	if err != nil {
+		structComment = ""
+		fieldComments = make(map[string]string)
+	}
+
+	obj := &jsonSchema{
+		Title:       t.Name(),
+		Description: structComment,
+		Type:        "object",
+		Properties:  make(map[string]*jsonSchema),
+	}
+	for _, f := range exportedFields(t) {
+		prop, err := jsonSchemaType(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		fieldName := toSnakeCase(f.Name)
+		if comment, ok := fieldComments[f.Name]; ok {
+			prop.Description = strings.Replace(comment, f.Name, fieldName, 1)
+		}
+		obj.Properties[fieldName] = prop
+		if f.Type.Kind() != reflect.Pointer {
+			obj.Required = append(obj.Required, fieldName)
+		}
+	}
+	return obj, nil
+}
+
+// GenerateJSONSchema returns a JSON Schema document describing the Lua
+// configuration table. Nested config types are placed under "$defs".
+func GenerateJSONSchema() (string, error) {
+	types := LuaTypes()
+	root, err := generateJSONSchemaObject(types[0])
+	if err != nil {
+		return "", fmt.Errorf("generating json schema for %T: %w", types[0], err)
+	}
+	root.Schema = "https://json-schema.org/draft/2020-12/schema"
+	for _, t := range types[1:] {
+		def, err := generateJSONSchemaObject(t)
+		if err != nil {
+			return "", fmt.Errorf("generating json schema for %T: %w", t, err)
+		}
+		if root.Defs == nil {
+			root.Defs = make(map[string]*jsonSchema)
+		}
+		root.Defs[reflect.TypeOf(t).Name()] = def
+	}
+	b, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal json schema: %w", err)
+	}
+	return string(b), nil
+}
+
+// JSONValue returns v with each struct as an object keyed by the same
+// lower_snake_case field names as GenerateJSONSchema, so that encoding it as
+// JSON gives a document the schema describes. Fields tagged `json:"-"` hold
+// secrets and are left out, as are nil pointers.
+func JSONValue(v any) any {
+	return jsonValue(reflect.ValueOf(v))
+}
+
+func jsonValue(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return jsonValue(v.Elem())
+	case reflect.Struct:
+		obj := make(map[string]any)
+		for _, f := range exportedFields(v.Type()) {
+			if f.Tag.Get("json") == "-" {
+				continue
+			}
+			field := v.FieldByIndex(f.Index)
+			if field.Kind() == reflect.Pointer && field.IsNil() {
+				continue
+			}
+			obj[toSnakeCase(f.Name)] = jsonValue(field)
+		}
+		return obj
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = jsonValue(v.Index(i))
+		}
+		return items
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		obj := make(map[string]any, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			obj[fmt.Sprint(iter.Key().Interface())] = jsonValue(iter.Value())
+		}
+		return obj
+	default:
+		return v.Interface()
+	}
+}
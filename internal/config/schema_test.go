@@ -0,0 +1,162 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestJSONSchemaType(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{"string type", "", `{"type":"string"}`},
+		{"int type", 0, `{"type":"integer"}`},
+		{"float type", 0.0, `{"type":"number"}`},
+		{"bool type", false, `{"type":"boolean"}`},
+		{"slice of strings", []string{}, `{"type":"array","items":{"type":"string"}}`},
+		{"map with string keys", map[string]int{}, `{"type":"object","additionalProperties":{"type":"integer"}}`},
+		{"pointer to string", new(string), `{"type":"string"}`},
+		{"struct", User{}, `{"$ref":"#/$defs/User"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := jsonSchemaType(reflect.TypeOf(tt.input))
+			if err != nil {
+				t.Fatalf("jsonSchemaType() error = %v", err)
+			}
+			got, err := json.Marshal(s)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("jsonSchemaType() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaType_Unsupported(t *testing.T) {
+	if _, err := jsonSchemaType(reflect.TypeOf(make(chan int))); err == nil {
+		t.Error("jsonSchemaType() expected error for channel type")
+	}
+}
+
+func TestGenerateJSONSchemaObject(t *testing.T) {
+	obj, err := generateJSONSchemaObject(WithPointer{})
+	if err != nil {
+		t.Fatalf("generateJSONSchemaObject() error = %v", err)
+	}
+	if obj.Type != "object" {
+		t.Errorf("Type = %q, want object", obj.Type)
+	}
+	if _, ok := obj.Properties["name"]; !ok {
+		t.Error("missing property name")
+	}
+	if len(obj.Required) != 0 {
+		t.Errorf("Required = %v, want none for pointer fields", obj.Required)
+	}
+
+	obj, err = generateJSONSchemaObject(CamelCaseFields{})
+	if err != nil {
+		t.Fatalf("generateJSONSchemaObject() error = %v", err)
+	}
+	want := []string{"first_name", "last_name", "user_id"}
+	if !slices.Equal(obj.Required, want) {
+		t.Errorf("Required = %v, want %v", obj.Required, want)
+	}
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	out, err := GenerateJSONSchema()
+	if err != nil {
+		t.Fatalf("GenerateJSONSchema() error = %v", err)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(out), &schema); err != nil {
+		t.Fatalf("GenerateJSONSchema() produced invalid JSON: %v", err)
+	}
+	if schema["title"] != "Data" {
+		t.Errorf("title = %v, want Data", schema["title"])
+	}
+	props, ok := schema["properties"].(map[string]any)
+	if !ok {
+		t.Fatalf("properties missing or wrong type: %T", schema["properties"])
+	}
+	for _, name := range []string{"debug", "database_path", "port", "first_day_of_week"} {
+		if _, ok := props[name]; !ok {
+			t.Errorf("missing property %q", name)
+		}
+	}
+}
+
+func TestJSONValue(t *testing.T) {
+	type secrets struct {
+		APIKey   string `json:"-"`
+		UserName string
+		Nickname *string
+	}
+	type withSecrets struct {
+		FirstName string
+		Secrets   secrets
+		Tags      []string
+	}
+	got, err := json.Marshal(JSONValue(&withSecrets{
+		FirstName: "Ada",
+		Secrets:   secrets{APIKey: "hunter2", UserName: "ada"},
+		Tags:      []string{"a"},
+	}))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"first_name":"Ada","secrets":{"user_name":"ada"},"tags":["a"]}`
+	if string(got) != want {
+		t.Errorf("JSONValue() = %s, want %s", got, want)
+	}
+}
+
+// TestJSONValue_MatchesSchema checks that the printed configuration only has
+// the properties the schema describes.
+func TestJSONValue_MatchesSchema(t *testing.T) {
+	out, err := GenerateJSONSchema()
+	if err != nil {
+		t.Fatalf("GenerateJSONSchema() error = %v", err)
+	}
+	var schema jsonSchema
+	if err := json.Unmarshal([]byte(out), &schema); err != nil {
+		t.Fatalf("GenerateJSONSchema() produced invalid JSON: %v", err)
+	}
+	version := "v1"
+	value, ok := JSONValue(&Data{Version: &version}).(map[string]any)
+	if !ok {
+		t.Fatalf("JSONValue() = %T, want an object", value)
+	}
+	var check func(path string, value map[string]any, s *jsonSchema)
+	check = func(path string, value map[string]any, s *jsonSchema) {
+		if s.Ref != "" {
+			s = schema.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		}
+		for name, v := range value {
+			prop, ok := s.Properties[name]
+			if !ok {
+				t.Errorf("JSONValue() has %s%s, which the schema does not describe", path, name)
+				continue
+			}
+			if obj, ok := v.(map[string]any); ok && prop.AdditionalProperties == nil {
+				check(path+name+".", obj, prop)
+			}
+		}
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				t.Errorf("JSONValue() is missing required %s%s", path, name)
+			}
+		}
+	}
+	check("", value, &schema)
+}
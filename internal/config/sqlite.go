@@ -0,0 +1,76 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"runtime"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// pragmas returns the statements run on every new connection. Foreign keys
+// are always enforced since SQLite disables them by default on each
+// connection.
+func (d *Database) pragmas() []string {
+	ps := []string{"PRAGMA foreign_keys = ON"}
+	if d.BusyTimeoutMillis > 0 {
+		ps = append(ps, fmt.Sprintf("PRAGMA busy_timeout = %d", d.BusyTimeoutMillis))
+	}
+	if d.Synchronous != "" {
+		ps = append(ps, "PRAGMA synchronous = "+d.Synchronous)
+	}
+	if d.CacheSizeKB > 0 {
+		// Negative sizes are in KiB rather than pages.
+		ps = append(ps, fmt.Sprintf("PRAGMA cache_size = -%d", d.CacheSizeKB))
+	}
+	if d.MmapSizeBytes > 0 {
+		ps = append(ps, fmt.Sprintf("PRAGMA mmap_size = %d", d.MmapSizeBytes))
+	}
+	return ps
+}
+
+// readPoolSize returns the maximum number of read-only connections.
+func (d *Database) readPoolSize() int {
+	if d.ReadPoolSize > 0 {
+		return d.ReadPoolSize
+	}
+	return runtime.NumCPU()
+}
+
+// connector opens SQLite connections to dsn and applies pragmas to each one.
+// Unlike DSN parameters, this supports every pragma.
+type connector struct {
+	dsn    string
+	driver *sqlite3.SQLiteDriver
+}
+
+func newConnector(dsn string, pragmas []string) *connector {
+	return &connector{
+		dsn: dsn,
+		driver: &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				for _, pragma := range pragmas {
+					if _, err := conn.Exec(pragma, nil); err != nil {
+						return fmt.Errorf("failed to run %q: %w", pragma, err)
+					}
+				}
+				return nil
+			},
+		},
+	}
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// openDB opens a database handle whose connections are configured by d.
+func (d *Database) openDB(dsn string) *sql.DB {
+	return sql.OpenDB(newConnector(dsn, d.pragmas()))
+}
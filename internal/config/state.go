@@ -9,10 +9,19 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sync/atomic"
+	"time"
 
+	"github.com/RyRose/uplog/internal/audit"
+	"github.com/RyRose/uplog/internal/history"
+	"github.com/RyRose/uplog/internal/search"
 	"github.com/RyRose/uplog/internal/sqlc"
-	"github.com/pressly/goose/v3"
+	"github.com/RyRose/uplog/internal/sqlc/dbtx"
+	"github.com/RyRose/uplog/internal/sqlc/workoutdb"
+	"github.com/RyRose/uplog/internal/telemetry"
+	"github.com/RyRose/uplog/internal/trash"
 	"github.com/prometheus/client_golang/prometheus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 type State struct {
@@ -22,69 +31,169 @@ type State struct {
 	// WDB is a write database connection.
 	WDB *sql.DB
 
+	// RPrepared runs queries against RDB with spans, metrics and prepared
+	// statements.
+	RPrepared *dbtx.DB
+
+	// WPrepared runs queries and begins transactions against WDB with spans,
+	// metrics and prepared statements.
+	WPrepared *dbtx.DB
+
+	// RQ runs queries against RPrepared.
+	RQ *workoutdb.Queries
+
+	// WQ runs queries against WPrepared.
+	WQ *workoutdb.Queries
+
+	// Trash moves deleted rows out of WDB so they can be restored.
+	Trash *trash.Bin
+
+	// History records changes to WDB so they can be undone.
+	History *history.Log
+
+	// Audit records the changes made through the data tables.
+	Audit *audit.Log
+
+	// Search finds the rows of the data tables matching free text.
+	Search *search.Index
+
 	// PrometheusRegistry is a Prometheus metrics registry.
 	PrometheusRegistry *prometheus.Registry
 
+	// Metrics holds domain metrics registered on PrometheusRegistry.
+	Metrics *Metrics
+
 	// JLog is a JSON slog logger.
 	JLog *slog.Logger
 
 	// TLog is a text slog logger.
 	TLog *slog.Logger
+
+	// TracerProvider creates the spans exported as configured by Data.Tracing.
+	TracerProvider *sdktrace.TracerProvider
+
+	// Draining is set once the server starts shutting down. New writes are
+	// rejected while in-flight requests drain.
+	Draining atomic.Bool
 }
 
+// Close closes the databases and flushes pending spans. The write-ahead log
+// is checkpointed into the database file before the write database is closed.
 func (s *State) Close() error {
 	var errs []error
+	for _, db := range []*dbtx.DB{s.RPrepared, s.WPrepared} {
+		if err := db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close prepared statements: %w", err))
+		}
+	}
 	if err := s.RDB.Close(); err != nil {
 		errs = append(errs, fmt.Errorf("failed to close readonly database: %w", err))
 	}
+	// Readers are closed first so the checkpoint is not blocked by them.
+	if err := checkpoint(s.WDB); err != nil {
+		errs = append(errs, err)
+	}
 	if err := s.WDB.Close(); err != nil {
 		errs = append(errs, fmt.Errorf("failed to close write database: %w", err))
 	}
+	if err := s.TracerProvider.Shutdown(context.Background()); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shutdown tracer provider: %w", err))
+	}
 	return errors.Join(errs...)
 }
 
-func setupDatabases(ctx context.Context, dbPath string) (*sql.DB, *sql.DB, error) {
-	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
-		return nil, nil, fmt.Errorf("failed to create directories: %w", err)
+// checkpoint updates the query planner statistics, then moves the contents of
+// the write-ahead log into the database file and truncates the log. Optimizing
+// first ensures its writes are checkpointed too.
+func checkpoint(db *sql.DB) error {
+	if _, err := db.Exec("PRAGMA optimize"); err != nil {
+		return fmt.Errorf("failed to optimize database: %w", err)
 	}
-
-	dsn := fmt.Sprintf("file:%s?mode=rwc&_journal_mode=WAL&_txlock=immediate", url.QueryEscape(dbPath))
-	db, err := sql.Open("sqlite3", dsn)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to open %v: %w", dsn, err)
+	if _, err := db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("failed to checkpoint write-ahead log: %w", err)
 	}
+	return nil
+}
+
+// migrate applies the embedded migrations to the database at dsn. Foreign keys
+// are not enforced while migrating since migrations may insert rows before the
+// rows they reference, as SQLite recommends for schema changes.
+func migrate(ctx context.Context, dsn string, cfg Database) error {
+	db := sql.OpenDB(newConnector(dsn, append(cfg.pragmas(), "PRAGMA foreign_keys = OFF")))
+	defer func() {
+		if err := db.Close(); err != nil {
+			slog.WarnContext(ctx, "failed to close migration database", "error", err)
+		}
+	}()
 	db.SetMaxOpenConns(1)
 
 	slog.InfoContext(ctx, "applying migrations")
-	goose.SetBaseFS(sqlc.EmbedMigrations)
-	if err := goose.SetDialect("sqlite"); err != nil {
-		return nil, nil, fmt.Errorf("failed to set dialect: %w", err)
+	return sqlc.Migrate(ctx, db)
+}
+
+func setupDatabases(ctx context.Context, dbPath string, cfg *Database) (*sql.DB, *sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create directories: %w", err)
 	}
-	if err := goose.UpContext(ctx, db, "migrations"); err != nil {
-		return nil, nil, fmt.Errorf("failed to apply migrations: %w", err)
+
+	dsn := fmt.Sprintf("file:%s?mode=rwc&_journal_mode=WAL&_txlock=immediate", url.QueryEscape(dbPath))
+	if err := migrate(ctx, dsn, *cfg); err != nil {
+		return nil, nil, err
 	}
+	db := cfg.openDB(dsn)
+	db.SetMaxOpenConns(1)
 
 	rdsn := fmt.Sprintf("file:%s?mode=ro&_journal_mode=WAL&_txlock=immediate", url.QueryEscape(dbPath))
-	rdb, err := sql.Open("sqlite3", rdsn)
-	if err != nil {
-		if closeErr := db.Close(); closeErr != nil {
-			slog.Warn("failed to close database during error cleanup", "error", closeErr)
-		}
-		return nil, nil, fmt.Errorf("failed to open %v: %w", rdsn, err)
-	}
+	rdb := cfg.openDB(rdsn)
+	rdb.SetMaxOpenConns(cfg.readPoolSize())
+	rdb.SetMaxIdleConns(cfg.readPoolSize())
 	return db, rdb, nil
 }
 
 func NewState(ctx context.Context, cfg *Data) (*State, error) {
-	wDB, rDB, err := setupDatabases(ctx, cfg.DatabasePath)
+	wDB, rDB, err := setupDatabases(ctx, cfg.DatabasePath, &cfg.Database)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup databases: %w", err)
 	}
+	registry := prometheus.NewRegistry()
+	metrics, err := registerMetrics(registry, cfg.DatabasePath, rDB, wDB)
+	if err != nil {
+		return nil, errors.Join(err, wDB.Close(), rDB.Close())
+	}
+	tp, err := telemetry.NewTracerProvider(ctx, telemetry.TracerOptions{
+		Exporter: cfg.Tracing.Exporter,
+		Path:     cfg.Tracing.Path,
+		Version:  cfg.AppVersion(),
+	})
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("failed to setup tracing: %w", err), wDB.Close(), rDB.Close())
+	}
+	trustedProxies, err := audit.ParseTrustedProxies(cfg.Audit.TrustedProxies)
+	if err != nil {
+		return nil, errors.Join(err, tp.Shutdown(ctx), wDB.Close(), rDB.Close())
+	}
+	rPrepared, wPrepared := dbtx.WrapPrepared(rDB), dbtx.WrapPrepared(wDB)
+	index, err := search.New(ctx, wPrepared)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("failed to setup search: %w", err), tp.Shutdown(ctx), wDB.Close(), rDB.Close())
+	}
 	return &State{
 		RDB:                rDB,
 		WDB:                wDB,
-		PrometheusRegistry: prometheus.NewRegistry(),
-		JLog:               slog.New(slog.NewJSONHandler(os.Stderr, nil)),
-		TLog:               slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		RPrepared:          rPrepared,
+		WPrepared:          wPrepared,
+		RQ:                 workoutdb.New(rPrepared),
+		WQ:                 workoutdb.New(wPrepared),
+		Trash:              trash.New(wPrepared, time.Duration(cfg.Trash.RetentionDays)*24*time.Hour),
+		History:            history.New(wPrepared, cfg.Undo.Limit),
+		Audit:              audit.New(rPrepared, trustedProxies),
+		Search:             index,
+		PrometheusRegistry: registry,
+		Metrics:            metrics,
+		JLog:               slog.New(telemetry.NewLogHandler(slog.NewJSONHandler(os.Stderr, nil))),
+		TLog:               slog.New(telemetry.NewLogHandler(slog.NewTextHandler(os.Stderr, nil))),
+		TracerProvider:     tp,
 	}, nil
 }
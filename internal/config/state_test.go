@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"database/sql"
 	"os"
 	"path/filepath"
 	"testing"
@@ -144,7 +145,7 @@ func TestSetupDatabases(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
 
-	wDB, rDB, err := setupDatabases(ctx, dbPath)
+	wDB, rDB, err := setupDatabases(ctx, dbPath, &Database{})
 	if err != nil {
 		t.Fatalf("setupDatabases() error = %v", err)
 	}
@@ -174,7 +175,7 @@ func TestSetupDatabases_MigrationsApplied(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
 
-	wDB, rDB, err := setupDatabases(ctx, dbPath)
+	wDB, rDB, err := setupDatabases(ctx, dbPath, &Database{})
 	if err != nil {
 		t.Fatalf("setupDatabases() error = %v", err)
 	}
@@ -194,7 +195,7 @@ func TestSetupDatabases_WriteDBMaxConnections(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
 
-	wDB, rDB, err := setupDatabases(ctx, dbPath)
+	wDB, rDB, err := setupDatabases(ctx, dbPath, &Database{})
 	if err != nil {
 		t.Fatalf("setupDatabases() error = %v", err)
 	}
@@ -207,3 +208,179 @@ func TestSetupDatabases_WriteDBMaxConnections(t *testing.T) {
 		t.Errorf("MaxOpenConnections = %d, want 1", stats.MaxOpenConnections)
 	}
 }
+
+func TestNewState_Metrics(t *testing.T) {
+	ctx := context.Background()
+	cfg := &Data{
+		DatabasePath: filepath.Join(t.TempDir(), "test.db"),
+	}
+
+	state, err := NewState(ctx, cfg)
+	if err != nil {
+		t.Fatalf("NewState() error = %v", err)
+	}
+	defer func() { _ = state.Close() }()
+
+	state.Metrics.ProgressLogged.WithLabelValues("upper").Inc()
+
+	families, err := state.PrometheusRegistry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	got := make(map[string]float64)
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			switch {
+			case m.GetGauge() != nil:
+				got[mf.GetName()] = m.GetGauge().GetValue()
+			case m.GetCounter() != nil:
+				got[mf.GetName()] = m.GetCounter().GetValue()
+			default:
+				got[mf.GetName()] = 0
+			}
+		}
+	}
+	for _, name := range []string{
+		"go_sql_max_open_connections",
+		"uplog_db_wal_size_bytes",
+	} {
+		if _, ok := got[name]; !ok {
+			t.Errorf("missing metric %s", name)
+		}
+	}
+	if got["uplog_db_file_size_bytes"] <= 0 {
+		t.Errorf("uplog_db_file_size_bytes = %v, want > 0", got["uplog_db_file_size_bytes"])
+	}
+	if got["uplog_progress_logged_total"] != 1 {
+		t.Errorf("uplog_progress_logged_total = %v, want 1", got["uplog_progress_logged_total"])
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	cfg := &Data{
+		DatabasePath: filepath.Join(t.TempDir(), "test.db"),
+	}
+
+	state, err := NewState(ctx, cfg)
+	if err != nil {
+		t.Fatalf("NewState() error = %v", err)
+	}
+	defer func() { _ = state.Close() }()
+
+	if _, err := state.WDB.Exec("CREATE TABLE test (id INTEGER)"); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	wal := cfg.DatabasePath + "-wal"
+	if info, err := os.Stat(wal); err != nil || info.Size() == 0 {
+		t.Fatalf("expected a non-empty write-ahead log, got %v, %v", info, err)
+	}
+
+	if err := checkpoint(state.WDB); err != nil {
+		t.Fatalf("checkpoint() error = %v", err)
+	}
+	info, err := os.Stat(wal)
+	if err != nil {
+		t.Fatalf("failed to stat write-ahead log: %v", err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("write-ahead log is %d bytes after checkpoint, want 0", info.Size())
+	}
+}
+
+func TestSetupDatabases_Pragmas(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	cfg := &Database{
+		ReadPoolSize:      2,
+		BusyTimeoutMillis: 1234,
+		Synchronous:       "NORMAL",
+		CacheSizeKB:       4096,
+		MmapSizeBytes:     1 << 20,
+	}
+
+	wDB, rDB, err := setupDatabases(ctx, dbPath, cfg)
+	if err != nil {
+		t.Fatalf("setupDatabases() error = %v", err)
+	}
+	defer func() { _ = wDB.Close() }()
+	defer func() { _ = rDB.Close() }()
+
+	if got := rDB.Stats().MaxOpenConnections; got != 2 {
+		t.Errorf("read pool size = %d, want 2", got)
+	}
+
+	want := map[string]int64{
+		"busy_timeout": 1234,
+		"foreign_keys": 1,
+		"synchronous":  1,
+		"cache_size":   -4096,
+		"mmap_size":    1 << 20,
+	}
+	// Hold two read connections at once so the pragmas are checked on more
+	// than one connection.
+	conns := []*sql.Conn{}
+	for range 2 {
+		conn, err := rDB.Conn(ctx)
+		if err != nil {
+			t.Fatalf("failed to get read connection: %v", err)
+		}
+		defer func() { _ = conn.Close() }()
+		conns = append(conns, conn)
+	}
+	for _, db := range []interface {
+		QueryRowContext(context.Context, string, ...any) *sql.Row
+	}{wDB, conns[0], conns[1]} {
+		for pragma, value := range want {
+			var got int64
+			if err := db.QueryRowContext(ctx, "PRAGMA "+pragma).Scan(&got); err != nil {
+				t.Fatalf("PRAGMA %s error = %v", pragma, err)
+			}
+			if got != value {
+				t.Errorf("PRAGMA %s = %d, want %d", pragma, got, value)
+			}
+		}
+	}
+
+	if _, err := wDB.ExecContext(ctx,
+		"INSERT INTO progress (lift, date, weight, sets, reps) VALUES ('no such lift', '2025-01-01', 1, 1, 1)"); err == nil {
+		t.Error("expected foreign key violation to be rejected")
+	}
+}
+
+func TestSetupDatabases_RenamesCascade(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	wDB, rDB, err := setupDatabases(ctx, dbPath, &Database{})
+	if err != nil {
+		t.Fatalf("setupDatabases() error = %v", err)
+	}
+	defer func() { _ = wDB.Close() }()
+	defer func() { _ = rDB.Close() }()
+
+	for _, stmt := range []string{
+		"INSERT INTO lift (id, link) VALUES ('bench', '')",
+		"INSERT INTO workout (id, template) VALUES ('push', '')",
+		"INSERT INTO progress (lift, date, weight, sets, reps) VALUES ('bench', '2025-01-01', 1, 1, 1)",
+		"INSERT INTO lift_workout_mapping (lift, workout) VALUES ('bench', 'push')",
+		"UPDATE lift SET id = 'bench press' WHERE id = 'bench'",
+		"UPDATE workout SET id = 'push day' WHERE id = 'push'",
+	} {
+		if _, err := wDB.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	var progressLift, mappingLift, mappingWorkout string
+	if err := wDB.QueryRowContext(ctx, "SELECT lift FROM progress").Scan(&progressLift); err != nil {
+		t.Fatalf("failed to query progress: %v", err)
+	}
+	if err := wDB.QueryRowContext(ctx,
+		"SELECT lift, workout FROM lift_workout_mapping WHERE workout = 'push day'").Scan(&mappingLift, &mappingWorkout); err != nil {
+		t.Fatalf("failed to query lift_workout_mapping: %v", err)
+	}
+	if progressLift != "bench press" || mappingLift != "bench press" || mappingWorkout != "push day" {
+		t.Errorf("references = %q, %q, %q; want renamed", progressLift, mappingLift, mappingWorkout)
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/RyRose/uplog/internal/audit"
+	"github.com/RyRose/uplog/internal/telemetry"
+)
+
+// Validate performs semantic validation of the configuration that cannot be
+// expressed through Lua types alone. All problems found are joined into the
+// returned error.
+func (d *Data) Validate() error {
+	var errs []error
+	switch d.Mode {
+	case ModeDev, ModeTest, ModeProd:
+	default:
+		errs = append(errs, fmt.Errorf("mode %q must be one of %q, %q or %q", d.Mode, ModeDev, ModeTest, ModeProd))
+	}
+	if d.DatabasePath == "" {
+		errs = append(errs, errors.New("database_path must not be empty"))
+	}
+	switch d.Database.Synchronous {
+	case "", "OFF", "NORMAL", "FULL", "EXTRA":
+	default:
+		errs = append(errs, fmt.Errorf("database.synchronous %q must be one of OFF, NORMAL, FULL or EXTRA", d.Database.Synchronous))
+	}
+	for _, limit := range []struct {
+		name  string
+		value int
+	}{
+		{"read_pool_size", d.Database.ReadPoolSize},
+		{"busy_timeout_millis", d.Database.BusyTimeoutMillis},
+		{"cache_size_kb", d.Database.CacheSizeKB},
+		{"mmap_size_bytes", d.Database.MmapSizeBytes},
+	} {
+		if limit.value < 0 {
+			errs = append(errs, fmt.Errorf("database.%s %d must not be negative", limit.name, limit.value))
+		}
+	}
+	port, err := strconv.Atoi(d.Port)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("port %q is not a number: %w", d.Port, err))
+	} else if port < 0 || port > 65535 {
+		errs = append(errs, fmt.Errorf("port %d is out of range [0, 65535]", port))
+	}
+	if d.FirstDayOfWeek < 0 || d.FirstDayOfWeek > 6 {
+		errs = append(errs, fmt.Errorf("first_day_of_week %d is out of range [0, 6]", d.FirstDayOfWeek))
+	}
+	switch d.Tracing.Exporter {
+	case "", telemetry.ExporterNone, telemetry.ExporterStdout:
+	case telemetry.ExporterOTLPFile:
+		if d.Tracing.Path == "" {
+			errs = append(errs, fmt.Errorf("tracing.path must not be empty for exporter %q", telemetry.ExporterOTLPFile))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter %q must be one of %q, %q or %q",
+			d.Tracing.Exporter, telemetry.ExporterNone, telemetry.ExporterStdout, telemetry.ExporterOTLPFile))
+	}
+	if d.TrainingMetrics.CacheSeconds < 0 {
+		errs = append(errs, fmt.Errorf("training_metrics.cache_seconds %d must not be negative", d.TrainingMetrics.CacheSeconds))
+	}
+	if d.Trash.RetentionDays < 0 {
+		errs = append(errs, fmt.Errorf("trash.retention_days %d must not be negative", d.Trash.RetentionDays))
+	}
+	if d.Undo.Limit < 0 {
+		errs = append(errs, fmt.Errorf("undo.limit %d must not be negative", d.Undo.Limit))
+	}
+	if _, err := audit.ParseTrustedProxies(d.Audit.TrustedProxies); err != nil {
+		errs = append(errs, fmt.Errorf("audit.trusted_proxies: %w", err))
+	}
+	for _, timeout := range []struct {
+		name    string
+		seconds int
+	}{
+		{"read_header_timeout_seconds", d.Server.ReadHeaderTimeoutSeconds},
+		{"idle_timeout_seconds", d.Server.IdleTimeoutSeconds},
+		{"drain_timeout_seconds", d.Server.DrainTimeoutSeconds},
+	} {
+		if timeout.seconds < 0 {
+			errs = append(errs, fmt.Errorf("server.%s %d must not be negative", timeout.name, timeout.seconds))
+		}
+	}
+	return errors.Join(errs...)
+}
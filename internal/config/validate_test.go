@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/RyRose/uplog/internal/telemetry"
+)
+
+func TestValidate(t *testing.T) {
+	valid := Data{
+		Mode:           ModeProd,
+		DatabasePath:   "/tmp/db.db",
+		Port:           "8080",
+		FirstDayOfWeek: 1,
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*Data)
+		wantErr bool
+	}{
+		{"valid", func(*Data) {}, false},
+		{"empty mode", func(d *Data) { d.Mode = "" }, true},
+		{"unknown mode", func(d *Data) { d.Mode = "staging" }, true},
+		{"empty database path", func(d *Data) { d.DatabasePath = "" }, true},
+		{"normal synchronous", func(d *Data) { d.Database.Synchronous = "NORMAL" }, false},
+		{"unknown synchronous", func(d *Data) { d.Database.Synchronous = "normal" }, true},
+		{"negative read pool size", func(d *Data) { d.Database.ReadPoolSize = -1 }, true},
+		{"negative mmap size", func(d *Data) { d.Database.MmapSizeBytes = -1 }, true},
+		{"non-numeric port", func(d *Data) { d.Port = "http" }, true},
+		{"empty port", func(d *Data) { d.Port = "" }, true},
+		{"port out of range", func(d *Data) { d.Port = "70000" }, true},
+		{"negative first day of week", func(d *Data) { d.FirstDayOfWeek = -1 }, true},
+		{"first day of week too large", func(d *Data) { d.FirstDayOfWeek = 7 }, true},
+		{"saturday first day of week", func(d *Data) { d.FirstDayOfWeek = 6 }, false},
+		{"stdout exporter", func(d *Data) { d.Tracing.Exporter = telemetry.ExporterStdout }, false},
+		{"unknown exporter", func(d *Data) { d.Tracing.Exporter = "jaeger" }, true},
+		{"otlpfile exporter without path", func(d *Data) { d.Tracing.Exporter = telemetry.ExporterOTLPFile }, true},
+		{"negative training metrics cache", func(d *Data) { d.TrainingMetrics.CacheSeconds = -1 }, true},
+		{"negative drain timeout", func(d *Data) { d.Server.DrainTimeoutSeconds = -1 }, true},
+		{"trusted proxies", func(d *Data) { d.Audit.TrustedProxies = []string{"10.0.0.1", "172.16.0.0/12"} }, false},
+		{"trusted proxy host name", func(d *Data) { d.Audit.TrustedProxies = []string{"proxy.local"} }, true},
+		{"negative idle timeout", func(d *Data) { d.Server.IdleTimeoutSeconds = -5 }, true},
+		{"negative trash retention", func(d *Data) { d.Trash.RetentionDays = -1 }, true},
+		{"negative undo limit", func(d *Data) { d.Undo.Limit = -1 }, true},
+		{"otlpfile exporter", func(d *Data) { d.Tracing = Tracing{Exporter: telemetry.ExporterOTLPFile, Path: "/tmp/traces.jsonl"} }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := valid
+			tt.modify(&d)
+			err := d.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+// Package doctor finds rows that violate the schema's constraints, such as
+// progress that references a lift that has since been renamed, and applies
+// fixes for them.
+package doctor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/RyRose/uplog/internal/schema"
+)
+
+// Orphan is a group of rows whose reference does not match any row of the
+// table it references.
+type Orphan struct {
+	// Table and Column are the referencing table and column.
+	Table, Column string
+	// Parent and ParentColumn are the referenced table and column.
+	Parent, ParentColumn string
+	// Value is the missing value of ParentColumn.
+	Value string
+	// Count is the number of rows in Table referencing Value.
+	Count int64
+	// Nullable is whether Column may be cleared instead.
+	Nullable bool
+	// Candidates are the existing values of ParentColumn.
+	Candidates []string
+	// Suggestion is the candidate most similar to Value, if any.
+	Suggestion string
+}
+
+// Report describes the problems found in a database.
+type Report struct {
+	// Integrity holds the problems reported by `PRAGMA integrity_check`.
+	Integrity []string
+	// Violations is the number of rows reported by `PRAGMA foreign_key_check`.
+	Violations int64
+	// Orphans groups the violating rows by their missing reference.
+	Orphans []Orphan
+}
+
+// OK reports whether no problems were found.
+func (r *Report) OK() bool {
+	return len(r.Integrity) == 0 && r.Violations == 0
+}
+
+// foreignKey is a single-column foreign key of a table.
+type foreignKey struct {
+	table, column        string
+	parent, parentColumn string
+	nullable             bool
+}
+
+// Examine checks the integrity and foreign keys of db.
+func Examine(ctx context.Context, db *sql.DB) (*Report, error) {
+	integrity, err := integrityCheck(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Integrity: integrity}
+
+	type key struct {
+		table string
+		id    int64
+	}
+	rows, err := db.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check foreign keys: %w", err)
+	}
+	violated := make(map[key]bool)
+	var keys []key
+	for rows.Next() {
+		var (
+			k      key
+			rowid  sql.NullInt64
+			parent string
+		)
+		if err := rows.Scan(&k.table, &rowid, &parent, &k.id); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to scan foreign key violation: %w", err), rows.Close())
+		}
+		report.Violations++
+		if !violated[k] {
+			violated[k] = true
+			keys = append(keys, k)
+		}
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("failed to check foreign keys: %w", err)
+	}
+
+	for _, k := range keys {
+		fk, err := lookupForeignKey(ctx, db, k.table, func(id int64, _ string) bool { return id == k.id })
+		if err != nil {
+			return nil, err
+		}
+		orphans, err := findOrphans(ctx, db, fk)
+		if err != nil {
+			return nil, err
+		}
+		report.Orphans = append(report.Orphans, orphans...)
+	}
+	sort.SliceStable(report.Orphans, func(i, j int) bool {
+		a, b := report.Orphans[i], report.Orphans[j]
+		if a.Table != b.Table {
+			return a.Table < b.Table
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Value < b.Value
+	})
+	return report, nil
+}
+
+func integrityCheck(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check integrity: %w", err)
+	}
+	var problems []string
+	for rows.Next() {
+		var problem string
+		if err := rows.Scan(&problem); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to scan integrity check: %w", err), rows.Close())
+		}
+		if problem != "ok" {
+			problems = append(problems, problem)
+		}
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("failed to check integrity: %w", err)
+	}
+	return problems, nil
+}
+
+// lookupForeignKey returns the single-column foreign key of table matching
+// the foreign key id and referencing column.
+func lookupForeignKey(ctx context.Context, db *sql.DB, table string, match func(id int64, column string) bool) (*foreignKey, error) {
+	rows, err := db.QueryContext(ctx, "SELECT id, seq, \"table\", \"from\", \"to\" FROM pragma_foreign_key_list(?)", table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list foreign keys of %s: %w", table, err)
+	}
+	var found *foreignKey
+	for rows.Next() {
+		var (
+			id, seq      int64
+			parent, from string
+			to           sql.NullString
+		)
+		if err := rows.Scan(&id, &seq, &parent, &from, &to); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to scan foreign key of %s: %w", table, err), rows.Close())
+		}
+		if !match(id, from) {
+			continue
+		}
+		if seq > 0 {
+			return nil, errors.Join(fmt.Errorf("composite foreign key %d of %s is not supported", id, table), rows.Close())
+		}
+		found = &foreignKey{table: table, column: from, parent: parent, parentColumn: to.String}
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("failed to list foreign keys of %s: %w", table, err)
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no foreign key found on %s", table)
+	}
+
+	if found.parentColumn == "" {
+		// A foreign key without a column references the primary key.
+		if err := db.QueryRowContext(ctx,
+			"SELECT name FROM pragma_table_info(?) WHERE pk = 1", found.parent,
+		).Scan(&found.parentColumn); err != nil {
+			return nil, fmt.Errorf("failed to find primary key of %s: %w", found.parent, err)
+		}
+	}
+	var notNull bool
+	if err := db.QueryRowContext(ctx,
+		"SELECT \"notnull\" FROM pragma_table_info(?) WHERE name = ?", table, found.column,
+	).Scan(&notNull); err != nil {
+		return nil, fmt.Errorf("failed to find column %s of %s: %w", found.column, table, err)
+	}
+	found.nullable = !notNull
+	return found, nil
+}
+
+// findOrphans groups the rows of fk.table whose reference is missing from
+// fk.parent.
+func findOrphans(ctx context.Context, db *sql.DB, fk *foreignKey) ([]Orphan, error) {
+	candidates, err := parentValues(ctx, db, fk)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(
+		`SELECT CAST(c.%[2]s AS TEXT), COUNT(*) FROM %[1]s AS c
+		WHERE c.%[2]s IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM %[3]s AS p WHERE p.%[4]s = c.%[2]s)
+		GROUP BY c.%[2]s`,
+		schema.Quote(fk.table), schema.Quote(fk.column), schema.Quote(fk.parent), schema.Quote(fk.parentColumn))
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orphans of %s.%s: %w", fk.table, fk.column, err)
+	}
+	var orphans []Orphan
+	for rows.Next() {
+		orphan := Orphan{
+			Table:        fk.table,
+			Column:       fk.column,
+			Parent:       fk.parent,
+			ParentColumn: fk.parentColumn,
+			Nullable:     fk.nullable,
+			Candidates:   candidates,
+		}
+		if err := rows.Scan(&orphan.Value, &orphan.Count); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to scan orphans of %s.%s: %w", fk.table, fk.column, err), rows.Close())
+		}
+		orphan.Suggestion = suggest(orphan.Value, candidates)
+		orphans = append(orphans, orphan)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("failed to find orphans of %s.%s: %w", fk.table, fk.column, err)
+	}
+	return orphans, nil
+}
+
+func parentValues(ctx context.Context, db *sql.DB, fk *foreignKey) ([]string, error) {
+	query := fmt.Sprintf("SELECT CAST(%[1]s AS TEXT) FROM %[2]s WHERE %[1]s IS NOT NULL ORDER BY %[1]s",
+		schema.Quote(fk.parentColumn), schema.Quote(fk.parent))
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s.%s: %w", fk.parent, fk.parentColumn, err)
+	}
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to scan %s.%s: %w", fk.parent, fk.parentColumn, err), rows.Close())
+		}
+		values = append(values, value)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("failed to list %s.%s: %w", fk.parent, fk.parentColumn, err)
+	}
+	return values, nil
+}
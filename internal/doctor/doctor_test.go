@@ -0,0 +1,168 @@
+package doctor
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const testSchema = `
+CREATE TABLE side_weight (id TEXT PRIMARY KEY NOT NULL);
+CREATE TABLE lift (
+    id TEXT PRIMARY KEY NOT NULL,
+    default_side_weight TEXT REFERENCES side_weight (id)
+);
+CREATE TABLE progress (
+    id INTEGER PRIMARY KEY NOT NULL,
+    lift TEXT NOT NULL REFERENCES lift (id)
+);
+INSERT INTO side_weight VALUES ('x1'), ('x2');
+INSERT INTO lift VALUES ('bench press', 'x2'), ('squat', 'x3');
+INSERT INTO progress (lift) VALUES ('bench press'), ('bench_press'), ('bench_press'), ('deadlift');
+`
+
+func setup(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(testSchema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+func TestExamine(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+
+	report, err := Examine(ctx, db)
+	if err != nil {
+		t.Fatalf("Examine() error = %v", err)
+	}
+	if report.OK() {
+		t.Error("OK() = true, want false")
+	}
+	if len(report.Integrity) != 0 {
+		t.Errorf("Integrity = %v, want none", report.Integrity)
+	}
+	if report.Violations != 4 {
+		t.Errorf("Violations = %d, want 4", report.Violations)
+	}
+
+	type got struct {
+		table, column, value string
+		count                int64
+		nullable             bool
+		suggestion           string
+	}
+	want := []got{
+		{"lift", "default_side_weight", "x3", 1, true, "x1"},
+		{"progress", "lift", "bench_press", 2, false, "bench press"},
+		{"progress", "lift", "deadlift", 1, false, ""},
+	}
+	if len(report.Orphans) != len(want) {
+		t.Fatalf("Orphans = %+v, want %d", report.Orphans, len(want))
+	}
+	for i, o := range report.Orphans {
+		g := got{o.Table, o.Column, o.Value, o.Count, o.Nullable, o.Suggestion}
+		if g != want[i] {
+			t.Errorf("Orphans[%d] = %+v, want %+v", i, g, want[i])
+		}
+	}
+	if c := report.Orphans[1].Candidates; len(c) != 2 || c[0] != "bench press" || c[1] != "squat" {
+		t.Errorf("Candidates = %v, want [bench press squat]", c)
+	}
+}
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+
+	for _, tt := range []struct {
+		fix  Fix
+		want int64
+	}{
+		{Fix{Table: "progress", Column: "lift", Value: "bench_press", Action: Reassign, Target: "bench press"}, 2},
+		{Fix{Table: "progress", Column: "lift", Value: "deadlift", Action: Delete}, 1},
+		{Fix{Table: "lift", Column: "default_side_weight", Value: "x3", Action: Clear}, 1},
+	} {
+		n, err := Apply(ctx, db, tt.fix)
+		if err != nil {
+			t.Fatalf("Apply(%+v) error = %v", tt.fix, err)
+		}
+		if n != tt.want {
+			t.Errorf("Apply(%+v) = %d, want %d", tt.fix, n, tt.want)
+		}
+	}
+
+	report, err := Examine(ctx, db)
+	if err != nil {
+		t.Fatalf("Examine() error = %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("report after fixes = %+v, want OK", report)
+	}
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM progress WHERE lift = 'bench press'").Scan(&count); err != nil {
+		t.Fatalf("failed to count progress: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("bench press progress = %d, want 3", count)
+	}
+}
+
+func TestApply_Errors(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+
+	for name, fix := range map[string]Fix{
+		"not null":        {Table: "progress", Column: "lift", Value: "deadlift", Action: Clear},
+		"missing target":  {Table: "progress", Column: "lift", Value: "deadlift", Action: Reassign, Target: "missing"},
+		"no target":       {Table: "progress", Column: "lift", Value: "deadlift", Action: Reassign},
+		"unknown column":  {Table: "progress", Column: "id", Value: "1", Action: Delete},
+		"unknown table":   {Table: "nope", Column: "lift", Value: "deadlift", Action: Delete},
+		"unknown action":  {Table: "progress", Column: "lift", Value: "deadlift", Action: "drop"},
+		"injection table": {Table: `progress"; DROP TABLE lift; --`, Column: "lift", Value: "x", Action: Delete},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Apply(ctx, db, fix); err == nil {
+				t.Errorf("Apply(%+v) succeeded, want error", fix)
+			}
+		})
+	}
+}
+
+func TestApply_OnlyOrphans(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+
+	n, err := Apply(ctx, db, Fix{Table: "progress", Column: "lift", Value: "bench press", Action: Delete})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if n != 0 {
+		t.Errorf("Apply() deleted %d valid rows, want 0", n)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"Bench Press", "Squat", "Overhead Press"}
+	for value, want := range map[string]string{
+		"bench press":   "Bench Press",
+		"bench_press":   "Bench Press",
+		"squats":        "Squat",
+		"ohp":           "",
+		"romanian dead": "",
+	} {
+		if got := suggest(value, candidates); got != want {
+			t.Errorf("suggest(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package doctor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/RyRose/uplog/internal/schema"
+)
+
+// Action is a way of fixing orphaned rows.
+type Action string
+
+const (
+	// Delete deletes the orphaned rows.
+	Delete Action = "delete"
+	// Clear sets the reference of the orphaned rows to NULL.
+	Clear Action = "clear"
+	// Reassign points the orphaned rows at an existing row, e.g. the new id of
+	// a renamed lift.
+	Reassign Action = "reassign"
+)
+
+// ParseAction parses the name of an action.
+func ParseAction(s string) (Action, error) {
+	switch a := Action(s); a {
+	case Delete, Clear, Reassign:
+		return a, nil
+	}
+	return "", fmt.Errorf("unknown action %q, want %q, %q or %q", s, Delete, Clear, Reassign)
+}
+
+// Fix fixes the rows of Table whose Column references the missing Value.
+type Fix struct {
+	Table, Column, Value string
+	Action               Action
+	// Target is the existing value to reassign the rows to.
+	Target string
+}
+
+// Apply applies fix in a single transaction and returns the number of rows
+// changed. Only orphaned rows are changed.
+func Apply(ctx context.Context, db *sql.DB, fix Fix) (int64, error) {
+	fk, err := lookupForeignKey(ctx, db, fix.Table, func(_ int64, column string) bool { return column == fix.Column })
+	if err != nil {
+		return 0, err
+	}
+
+	// The reference must still be missing so a fix never changes valid rows.
+	orphaned := fmt.Sprintf("%[1]s = ? AND NOT EXISTS (SELECT 1 FROM %[2]s WHERE %[3]s = ?)",
+		schema.Quote(fk.column), schema.Quote(fk.parent), schema.Quote(fk.parentColumn))
+	var (
+		query string
+		args  []any
+	)
+	switch fix.Action {
+	case Delete:
+		query = fmt.Sprintf("DELETE FROM %s WHERE %s", schema.Quote(fk.table), orphaned)
+		args = []any{fix.Value, fix.Value}
+	case Clear:
+		if !fk.nullable {
+			return 0, fmt.Errorf("%s.%s cannot be cleared since it is NOT NULL", fk.table, fk.column)
+		}
+		query = fmt.Sprintf("UPDATE %s SET %s = NULL WHERE %s", schema.Quote(fk.table), schema.Quote(fk.column), orphaned)
+		args = []any{fix.Value, fix.Value}
+	case Reassign:
+		if fix.Target == "" {
+			return 0, errors.New("reassign requires a target")
+		}
+		query = fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s", schema.Quote(fk.table), schema.Quote(fk.column), orphaned)
+		args = []any{fix.Target, fix.Value, fix.Value}
+	default:
+		return 0, fmt.Errorf("unknown action %q", fix.Action)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if fix.Action == Reassign {
+		var exists bool
+		if err := tx.QueryRowContext(ctx,
+			fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE %s = ?)", schema.Quote(fk.parent), schema.Quote(fk.parentColumn)),
+			fix.Target,
+		).Scan(&exists); err != nil {
+			return 0, fmt.Errorf("failed to look up %s %q: %w", fk.parent, fix.Target, err)
+		}
+		if !exists {
+			return 0, fmt.Errorf("%s %q does not exist", fk.parent, fix.Target)
+		}
+	}
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to %s %s.%s = %q: %w", fix.Action, fk.table, fk.column, fix.Value, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count changed rows: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return n, nil
+}
+
+// suggest returns the candidate most similar to value, ignoring case, or ""
+// if none are similar enough to be a likely rename.
+func suggest(value string, candidates []string) string {
+	var (
+		best     string
+		bestDist = len(value)/2 + 1
+	)
+	lower := strings.ToLower(value)
+	for _, c := range candidates {
+		if d := distance(lower, strings.ToLower(c)); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,290 @@
+// Package history records the rows changed by each session so that its most
+// recent changes can be undone.
+package history
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/RyRose/uplog/internal/schema"
+	"github.com/RyRose/uplog/internal/sqlc/dbtx"
+)
+
+// Action is how rows were changed.
+type Action string
+
+const (
+	Inserted Action = "insert"
+	Updated  Action = "update"
+	Deleted  Action = "delete"
+)
+
+// DefaultLimit is the number of changes kept per session when no limit is
+// given.
+const DefaultLimit = 10
+
+// ErrNotFound is returned when there are no rows to record or no change to
+// undo.
+var ErrNotFound = errors.New("not found")
+
+// Change is a recorded change of the rows of a table.
+type Change struct {
+	ID      int64
+	Session string
+	// Table and Key identify the changed rows.
+	Table, Key string
+	Action     Action
+	ChangedAt  time.Time
+}
+
+// Message describes c in the toast offering to undo it.
+func (c Change) Message() string {
+	switch c.Action {
+	case Inserted:
+		return fmt.Sprintf("Added %s %s", c.Table, c.Key)
+	case Updated:
+		return fmt.Sprintf("Edited %s %s", c.Table, c.Key)
+	default:
+		return fmt.Sprintf("Deleted %s %s", c.Table, c.Key)
+	}
+}
+
+// Log records changes in a database and undoes them.
+type Log struct {
+	db    dbtx.Beginner
+	limit int
+	now   func() time.Time
+}
+
+// New returns a Log keeping the last limit changes of each session in db, or
+// DefaultLimit changes if limit is not positive.
+func New(db dbtx.Beginner, limit int) *Log {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	return &Log{db: db, limit: limit, now: time.Now}
+}
+
+// Record copies the rows of table whose column is value into the history of
+// session as a change of action, and forgets the changes of the session
+// beyond the limit. It must run in the transaction making the change, before
+// rows are updated or deleted and after they are inserted.
+func (l *Log) Record(ctx context.Context, tx schema.Execer, session string, action Action, table, column string, value any) (Change, error) {
+	columns, _, err := schema.Columns(ctx, tx, table)
+	if err != nil {
+		return Change{}, err
+	}
+	change := Change{
+		Session:   session,
+		Table:     table,
+		Key:       fmt.Sprint(value),
+		Action:    action,
+		ChangedAt: l.now().UTC().Truncate(time.Second),
+	}
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(
+		`INSERT INTO change_history (session, source, key, action, data, changed_at)
+		SELECT ?, ?, ?, ?, json_group_array(json(%s)), ? FROM %s WHERE %s = ?
+		HAVING COUNT(*) > 0
+		RETURNING id`,
+		schema.Object(columns), schema.Quote(table), schema.Quote(column)),
+		session, table, change.Key, string(action), change.ChangedAt.Format(time.RFC3339), value,
+	).Scan(&change.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Change{}, fmt.Errorf("%s %q: %w", table, change.Key, ErrNotFound)
+	}
+	if err != nil {
+		return Change{}, fmt.Errorf("failed to record change of %s: %w", table, err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM change_history WHERE session = ? AND id NOT IN (
+			SELECT id FROM change_history WHERE session = ? ORDER BY id DESC LIMIT ?)`,
+		session, session, l.limit,
+	); err != nil {
+		return Change{}, fmt.Errorf("failed to trim change history: %w", err)
+	}
+	return change, nil
+}
+
+// Observer runs in the transaction of an undo for each row of table it
+// reverts, with the row before and after as JSON objects. before is empty for
+// rows inserted again and after for rows deleted. The undo is rolled back if
+// it fails.
+type Observer func(ctx context.Context, tx *sql.Tx, table, before, after string) error
+
+// Undo reverts the most recent change of session and forgets it. Inserted rows
+// are deleted, updated rows get their previous values back and deleted rows
+// are inserted again. Every reverted row is passed to observe unless it is
+// nil. It fails without changing anything if a row to revert no longer exists
+// or, for deletes, exists again.
+func (l *Log) Undo(ctx context.Context, session string, observe Observer) (Change, error) {
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Change{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		change            Change
+		action, changedAt string
+	)
+	err = tx.QueryRowContext(ctx,
+		`SELECT id, source, key, action, changed_at FROM change_history
+		WHERE session = ? ORDER BY id DESC LIMIT 1`, session,
+	).Scan(&change.ID, &change.Table, &change.Key, &action, &changedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Change{}, fmt.Errorf("change to undo: %w", ErrNotFound)
+	}
+	if err != nil {
+		return Change{}, fmt.Errorf("failed to select change to undo: %w", err)
+	}
+	change.Session, change.Action = session, Action(action)
+	if change.ChangedAt, err = time.Parse(time.RFC3339, changedAt); err != nil {
+		return Change{}, fmt.Errorf("failed to parse time of change %d: %w", change.ID, err)
+	}
+
+	rows, err := changedRows(ctx, tx, change.ID)
+	if err != nil {
+		return Change{}, err
+	}
+	columns, primaryKey, err := schema.Columns(ctx, tx, change.Table)
+	if err != nil {
+		return Change{}, err
+	}
+	for _, row := range rows {
+		before, err := current(ctx, tx, change.Table, columns, primaryKey, row)
+		if err != nil {
+			return Change{}, err
+		}
+		if err := revert(ctx, tx, change, columns, primaryKey, row); err != nil {
+			return Change{}, err
+		}
+		if observe == nil {
+			continue
+		}
+		after, err := current(ctx, tx, change.Table, columns, primaryKey, row)
+		if err != nil {
+			return Change{}, err
+		}
+		if len(primaryKey) == 0 {
+			// Rows without a primary key can only be inserted again.
+			after = row
+		}
+		if err := observe(ctx, tx, change.Table, before, after); err != nil {
+			return Change{}, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM change_history WHERE id = ?", change.ID); err != nil {
+		return Change{}, fmt.Errorf("failed to forget change %d: %w", change.ID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return Change{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return change, nil
+}
+
+// changedRows returns the rows recorded for change as JSON objects.
+func changedRows(ctx context.Context, tx *sql.Tx, change int64) ([]string, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT j.value FROM change_history AS c, json_each(c.data) AS j
+		WHERE c.id = ? ORDER BY j.key`, change)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select rows of change %d: %w", change, err)
+	}
+	var all []string
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to scan row of change %d: %w", change, err), rows.Close())
+		}
+		all = append(all, row)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("failed to select rows of change %d: %w", change, err)
+	}
+	return all, nil
+}
+
+// current returns the row of table with the primary key of the JSON object row
+// as it is now, or "" if there is none or table has no primary key.
+func current(ctx context.Context, tx *sql.Tx, table string, columns, primaryKey []string, row string) (string, error) {
+	if len(primaryKey) == 0 {
+		return "", nil
+	}
+	where := make([]string, len(primaryKey))
+	args := make([]any, len(primaryKey))
+	for i, c := range primaryKey {
+		where[i] = fmt.Sprintf("%s = %s", schema.Quote(c), schema.Extract(c))
+		args[i] = row
+	}
+	var out string
+	err := tx.QueryRowContext(ctx, fmt.Sprintf("SELECT %s FROM %s WHERE %s",
+		schema.Object(columns), schema.Quote(table), strings.Join(where, " AND ")), args...).Scan(&out)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to select row of %s: %w", table, err)
+	}
+	return out, nil
+}
+
+// revert reverts the change of a single row recorded as a JSON object. Every
+// parameter of the statement is bound to the row.
+func revert(ctx context.Context, tx *sql.Tx, change Change, columns, primaryKey []string, row string) error {
+	var where []string
+	for _, c := range primaryKey {
+		where = append(where, fmt.Sprintf("%s = %s", schema.Quote(c), schema.Extract(c)))
+	}
+	if change.Action != Deleted && len(where) == 0 {
+		return fmt.Errorf("cannot undo change of %s without a primary key", change.Table)
+	}
+
+	var (
+		query  string
+		params int
+	)
+	switch change.Action {
+	case Inserted:
+		query = fmt.Sprintf("DELETE FROM %s WHERE %s", schema.Quote(change.Table), strings.Join(where, " AND "))
+		params = len(where)
+	case Updated:
+		set := make([]string, len(columns))
+		for i, c := range columns {
+			set[i] = fmt.Sprintf("%s = %s", schema.Quote(c), schema.Extract(c))
+		}
+		query = fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+			schema.Quote(change.Table), strings.Join(set, ", "), strings.Join(where, " AND "))
+		params = len(set) + len(where)
+	case Deleted:
+		quoted := make([]string, len(columns))
+		values := make([]string, len(columns))
+		for i, c := range columns {
+			quoted[i], values[i] = schema.Quote(c), schema.Extract(c)
+		}
+		query = fmt.Sprintf("INSERT INTO %s (%s) SELECT %s",
+			schema.Quote(change.Table), strings.Join(quoted, ", "), strings.Join(values, ", "))
+		params = len(values)
+	default:
+		return fmt.Errorf("unknown action %q", change.Action)
+	}
+	args := make([]any, params)
+	for i := range args {
+		args[i] = row
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to undo %s of %s %q: %w", change.Action, change.Table, change.Key, err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to undo %s of %s %q: %w", change.Action, change.Table, change.Key, err)
+	} else if n == 0 {
+		return fmt.Errorf("cannot undo %s of %s %q: %w", change.Action, change.Table, change.Key, ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func setup(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if _, err := db.Exec(`
+CREATE TABLE change_history (
+    id INTEGER PRIMARY KEY NOT NULL,
+    session TEXT NOT NULL,
+    source TEXT NOT NULL,
+    key TEXT NOT NULL,
+    action TEXT NOT NULL,
+    data TEXT NOT NULL,
+    changed_at TEXT NOT NULL
+);
+CREATE TABLE progress (
+    id INTEGER PRIMARY KEY NOT NULL,
+    lift TEXT NOT NULL,
+    weight REAL NOT NULL,
+    side_weight TEXT NULL
+);
+INSERT INTO progress VALUES (1, 'squat', 100, 'x2'), (2, 'bench', 50.5, NULL);
+`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+// change runs stmt in a transaction recording action on progress id.
+func change(t *testing.T, log *Log, session string, action Action, id int64, stmt string) {
+	t.Helper()
+	ctx := context.Background()
+	tx, err := log.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if action == Inserted {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	if _, err := log.Record(ctx, tx, session, action, "progress", "id", id); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if action != Inserted {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+}
+
+func dump(t *testing.T, db *sql.DB) string {
+	t.Helper()
+	var out string
+	if err := db.QueryRow(
+		"SELECT json_group_array(json_array(id, lift, weight, side_weight)) FROM (SELECT * FROM progress ORDER BY id)",
+	).Scan(&out); err != nil {
+		t.Fatalf("failed to dump progress: %v", err)
+	}
+	return out
+}
+
+func TestUndo(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	log := New(db, 0)
+	before := dump(t, db)
+
+	change(t, log, "a", Deleted, 1, "DELETE FROM progress WHERE id = 1")
+	change(t, log, "a", Updated, 2, "UPDATE progress SET weight = 60, side_weight = 'x1' WHERE id = 2")
+	change(t, log, "a", Inserted, 3, "INSERT INTO progress VALUES (3, 'deadlift', 140, NULL)")
+	if got, want := dump(t, db), `[[2,"bench",60.0,"x1"],[3,"deadlift",140.0,null]]`; got != want {
+		t.Fatalf("progress after changes = %s, want %s", got, want)
+	}
+
+	var observed []string
+	observe := func(ctx context.Context, tx *sql.Tx, table, before, after string) error {
+		observed = append(observed, table+" "+before+" -> "+after)
+		return nil
+	}
+	for _, want := range []Action{Inserted, Updated, Deleted} {
+		got, err := log.Undo(ctx, "a", observe)
+		if err != nil {
+			t.Fatalf("Undo() error = %v", err)
+		}
+		if got.Action != want || got.Table != "progress" || got.Session != "a" {
+			t.Errorf("Undo() = %+v, want %s of progress", got, want)
+		}
+	}
+	if after := dump(t, db); after != before {
+		t.Errorf("progress after undo = %s, want %s", after, before)
+	}
+	wantObserved := []string{
+		`progress {"id":3,"lift":"deadlift","weight":140.0,"side_weight":null} -> `,
+		`progress {"id":2,"lift":"bench","weight":60.0,"side_weight":"x1"} -> {"id":2,"lift":"bench","weight":50.5,"side_weight":null}`,
+		`progress  -> {"id":1,"lift":"squat","weight":100.0,"side_weight":"x2"}`,
+	}
+	if !reflect.DeepEqual(observed, wantObserved) {
+		t.Errorf("observed rows = %q, want %q", observed, wantObserved)
+	}
+	if _, err := log.Undo(ctx, "a", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Undo() with no changes error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUndo_Sessions(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	log := New(db, 0)
+
+	change(t, log, "a", Deleted, 1, "DELETE FROM progress WHERE id = 1")
+	if _, err := log.Undo(ctx, "b", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Undo() of another session error = %v, want ErrNotFound", err)
+	}
+	if _, err := log.Undo(ctx, "a", nil); err != nil {
+		t.Errorf("Undo() error = %v", err)
+	}
+}
+
+func TestUndo_Conflict(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	log := New(db, 0)
+
+	change(t, log, "a", Inserted, 3, "INSERT INTO progress VALUES (3, 'deadlift', 140, NULL)")
+	if _, err := db.Exec("DELETE FROM progress WHERE id = 3"); err != nil {
+		t.Fatalf("failed to delete progress: %v", err)
+	}
+	if _, err := log.Undo(ctx, "a", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Undo() of deleted insert error = %v, want ErrNotFound", err)
+	}
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM change_history").Scan(&n); err != nil {
+		t.Fatalf("failed to count history: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("failed undo left %d changes, want 1", n)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	log := New(db, 2)
+
+	for i := 0; i < 3; i++ {
+		change(t, log, "a", Updated, 1, "UPDATE progress SET weight = weight + 1 WHERE id = 1")
+	}
+	change(t, log, "b", Updated, 2, "UPDATE progress SET weight = weight + 1 WHERE id = 2")
+	var a, b int
+	if err := db.QueryRow(`SELECT
+		(SELECT COUNT(*) FROM change_history WHERE session = 'a'),
+		(SELECT COUNT(*) FROM change_history WHERE session = 'b')`).Scan(&a, &b); err != nil {
+		t.Fatalf("failed to count history: %v", err)
+	}
+	if a != 2 || b != 1 {
+		t.Errorf("changes kept = %d, %d; want 2, 1", a, b)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := log.Record(ctx, tx, "a", Deleted, "progress", "id", 42); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Record() of missing row error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUndo_PathKey(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	log := New(db, 0)
+
+	// Keys from request paths are text even for integer primary keys.
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	change, err := log.Record(ctx, tx, "a", Updated, "progress", "id", "1")
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if _, err := tx.Exec("UPDATE progress SET weight = 70 WHERE id = 1"); err != nil {
+		t.Fatalf("failed to update progress: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	if got, want := change.Message(), "Edited progress 1"; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+
+	if _, err := log.Undo(ctx, "a", nil); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	var weight float64
+	if err := db.QueryRow("SELECT weight FROM progress WHERE id = 1").Scan(&weight); err != nil {
+		t.Fatalf("failed to select progress: %v", err)
+	}
+	if weight != 100 {
+		t.Errorf("weight after undo = %v, want 100", weight)
+	}
+}
@@ -0,0 +1,30 @@
+package history
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// SessionCookie is the name of the cookie identifying the session of a
+// browser.
+const SessionCookie = "uplog_session"
+
+// Session returns the session of r, starting a new one with a cookie set on w
+// if r has none.
+func Session(w http.ResponseWriter, r *http.Request) string {
+	if c, err := r.Cookie(SessionCookie); err == nil && c.Value != "" {
+		return c.Value
+	}
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	session := hex.EncodeToString(b)
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    session,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return session
+}
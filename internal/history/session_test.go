@@ -0,0 +1,29 @@
+package history
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSession(t *testing.T) {
+	w := httptest.NewRecorder()
+	session := Session(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	if len(session) != 32 {
+		t.Errorf("Session() = %q, want 32 hex characters", session)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != SessionCookie || cookies[0].Value != session {
+		t.Fatalf("cookies = %v, want %s=%s", cookies, SessionCookie, session)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+	if got := Session(w, r); got != session {
+		t.Errorf("Session() = %q, want %q", got, session)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("Session() set a cookie for an existing session")
+	}
+}
@@ -0,0 +1,258 @@
+// Package matrix edits the tables mapping lifts to muscles and workouts as a
+// matrix of lifts by the rows they map to, saving many mappings at once.
+package matrix
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/RyRose/uplog/internal/audit"
+	"github.com/RyRose/uplog/internal/schema"
+	"github.com/RyRose/uplog/internal/sqlc/dbtx"
+)
+
+// ErrConflict is returned by Save when the mappings of the lifts changed since
+// the version they were loaded at.
+var ErrConflict = errors.New("mappings changed since they were loaded")
+
+// Mapping is a table mapping lifts, referenced by its lift column, to the rows
+// of another table.
+type Mapping struct {
+	Table string
+	// Title is the title of the tab of the matrix.
+	Title string
+	// Column references the rows of Source lifts are mapped to, which are the
+	// columns of the matrix.
+	Column, Source string
+	// Role is the column naming the role a lift has for a row it is mapped
+	// to, referencing RoleSource. Without one, lifts are only mapped or not.
+	Role, RoleSource string
+}
+
+// Mappings are the mappings edited as matrices.
+var Mappings = []Mapping{
+	{
+		Table:      "lift_muscle_mapping",
+		Title:      "Lift×Muscle",
+		Column:     "muscle",
+		Source:     "muscle",
+		Role:       "movement",
+		RoleSource: "movement",
+	},
+	{
+		Table:  "lift_workout_mapping",
+		Title:  "Lift×Workout",
+		Column: "workout",
+		Source: "workout",
+	},
+}
+
+// Endpoint returns the endpoint of the matrix view of m.
+func (m Mapping) Endpoint() string {
+	return "/view/matrix/" + m.Table
+}
+
+// Cell is a lift and a column of a matrix.
+type Cell struct {
+	Lift, Column string
+}
+
+// Matrix is the mappings of some lifts.
+type Matrix struct {
+	Lifts, Columns []string
+	// Roles are the roles a lift can have for a column, if the mapping has
+	// them.
+	Roles []string
+	// Cells are the sorted roles of the lifts for the columns they are mapped
+	// to. Lifts are mapped with the role "" if the mapping has no roles.
+	Cells map[Cell][]string
+	// Version identifies the mappings of Lifts, so it changes whenever any of
+	// them does.
+	Version string
+}
+
+// Load returns the matrix of the lifts of group, or of all lifts if group is
+// empty.
+func (m Mapping) Load(ctx context.Context, db schema.Querier, group string) (*Matrix, error) {
+	lifts, err := list(ctx, db, "SELECT id FROM lift WHERE ?1 = '' OR lift_group = ?1 ORDER BY id", group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lifts: %w", err)
+	}
+	columns, err := list(ctx, db, fmt.Sprintf("SELECT id FROM %s ORDER BY id", schema.Quote(m.Source)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", m.Source, err)
+	}
+	out := &Matrix{Lifts: lifts, Columns: columns}
+	if m.Role != "" {
+		if out.Roles, err = list(ctx, db, fmt.Sprintf("SELECT id FROM %s ORDER BY id", schema.Quote(m.RoleSource))); err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", m.RoleSource, err)
+		}
+	}
+	if out.Cells, out.Version, err = m.cells(ctx, db, lifts); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Save sets the roles of each of cells in one transaction of db, recording
+// every row it maps or unmaps in log on behalf of req. Cells without roles are
+// unmapped, and cells that are not given are left as is. It returns the
+// number of rows changed, or ErrConflict if the mappings of lifts are not the
+// version they were loaded at.
+func (m Mapping) Save(ctx context.Context, db dbtx.Beginner, log *audit.Log, req audit.Request,
+	lifts []string, version string, cells map[Cell][]string) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stored, current, err := m.cells(ctx, tx, lifts)
+	if err != nil {
+		return 0, err
+	}
+	if current != version {
+		return 0, ErrConflict
+	}
+	var changed int
+	for cell, roles := range cells {
+		if !slices.Contains(lifts, cell.Lift) {
+			return 0, fmt.Errorf("lift %q is not one of the lifts saved", cell.Lift)
+		}
+		for _, role := range stored[cell] {
+			if slices.Contains(roles, role) {
+				continue
+			}
+			if err := m.write(ctx, tx, log, req, audit.Deleted, cell, role); err != nil {
+				return 0, err
+			}
+			changed++
+		}
+		for _, role := range roles {
+			if slices.Contains(stored[cell], role) {
+				continue
+			}
+			if err := m.write(ctx, tx, log, req, audit.Inserted, cell, role); err != nil {
+				return 0, err
+			}
+			changed++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return changed, nil
+}
+
+// key returns the primary key of the row mapping the lift of cell to its
+// column with role.
+func (m Mapping) key(cell Cell, role string) map[string]string {
+	key := map[string]string{"lift": cell.Lift, m.Column: cell.Column}
+	if m.Role != "" {
+		key[m.Role] = role
+	}
+	return key
+}
+
+// write inserts or deletes the row mapping the lift of cell to its column with
+// role in tx and records it in log.
+func (m Mapping) write(ctx context.Context, tx *sql.Tx, log *audit.Log, req audit.Request,
+	action audit.Action, cell Cell, role string) error {
+	key := m.key(cell, role)
+	var before, after audit.Row
+	var err error
+	if action == audit.Deleted {
+		if before, err = audit.Snapshot(ctx, tx, m.Table, key); err != nil {
+			return err
+		}
+	}
+	var (
+		names, marks, where []string
+		args                []any
+	)
+	for _, name := range slices.Sorted(maps.Keys(key)) {
+		names = append(names, schema.Quote(name))
+		marks = append(marks, "?")
+		where = append(where, schema.Quote(name)+" = ?")
+		args = append(args, key[name])
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		schema.Quote(m.Table), strings.Join(names, ", "), strings.Join(marks, ", "))
+	if action == audit.Deleted {
+		query = fmt.Sprintf("DELETE FROM %s WHERE %s", schema.Quote(m.Table), strings.Join(where, " AND "))
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to %s %s: %w", action, m.Table, err)
+	}
+	if action == audit.Inserted {
+		if after, err = audit.Snapshot(ctx, tx, m.Table, key); err != nil {
+			return err
+		}
+	}
+	return log.Record(ctx, tx, req, m.Table, before, after)
+}
+
+// cells returns the cells of lifts as stored in db along with their version.
+func (m Mapping) cells(ctx context.Context, db schema.Querier, lifts []string) (map[Cell][]string, string, error) {
+	role := "''"
+	if m.Role != "" {
+		role = schema.Quote(m.Role)
+	}
+	encoded, err := json.Marshal(lifts)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode lifts: %w", err)
+	}
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT lift, %s, %s FROM %s WHERE lift IN (SELECT value FROM json_each(?)) ORDER BY 1, 2, 3",
+		schema.Quote(m.Column), role, schema.Quote(m.Table)), string(encoded))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to select %s: %w", m.Table, err)
+	}
+	var (
+		cells  = make(map[Cell][]string)
+		stored [][3]string
+	)
+	for rows.Next() {
+		var row [3]string
+		if err := rows.Scan(&row[0], &row[1], &row[2]); err != nil {
+			return nil, "", errors.Join(fmt.Errorf("failed to scan %s: %w", m.Table, err), rows.Close())
+		}
+		cell := Cell{Lift: row[0], Column: row[1]}
+		cells[cell] = append(cells[cell], row[2])
+		stored = append(stored, row)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, "", fmt.Errorf("failed to select %s: %w", m.Table, err)
+	}
+	encoded, err = json.Marshal(stored)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode %s: %w", m.Table, err)
+	}
+	sum := sha256.Sum256(encoded)
+	return cells, hex.EncodeToString(sum[:8]), nil
+}
+
+// list returns the values of the single column selected by query.
+func list(ctx context.Context, db schema.Querier, query string, args ...any) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, errors.Join(err, rows.Close())
+		}
+		values = append(values, v)
+	}
+	return values, errors.Join(rows.Err(), rows.Close())
+}
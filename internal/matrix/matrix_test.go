@@ -0,0 +1,78 @@
+package matrix
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/RyRose/uplog/internal/audit"
+	"github.com/RyRose/uplog/internal/sqlc/sqlctest"
+)
+
+func TestMapping_Load(t *testing.T) {
+	db := sqlctest.Open(t)
+	m, err := Mappings[0].Load(context.Background(), db, "push")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !slices.Contains(m.Lifts, "Bench (Smith)") || slices.Contains(m.Lifts, "Deadlift (con)") {
+		t.Errorf("Load() lifts = %q, want the push lifts", m.Lifts)
+	}
+	if !slices.Contains(m.Columns, "Abs") || !slices.Contains(m.Roles, "Target") {
+		t.Errorf("Load() columns = %q, roles = %q, want muscles and movements", m.Columns, m.Roles)
+	}
+	got := m.Cells[Cell{Lift: "OHP (BB)", Column: "Triceps"}]
+	if len(got) != 2 {
+		t.Errorf("Load() roles of OHP (BB) for Triceps = %q, want two roles", got)
+	}
+}
+
+func TestMapping_Save(t *testing.T) {
+	ctx := context.Background()
+	db := sqlctest.Open(t)
+	mapping := Mappings[0]
+	before, err := mapping.Load(ctx, db, "")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	cells := map[Cell][]string{
+		{Lift: "Bench (Smith)", Column: "Chest"}:       {"Target"},
+		{Lift: "Deadlift (con)", Column: "Hamstrings"}: nil,
+		// Cells set as stored are left as is.
+		{Lift: "Fly (DB)", Column: "Biceps"}: before.Cells[Cell{Lift: "Fly (DB)", Column: "Biceps"}],
+	}
+	changed, err := mapping.Save(ctx, db, audit.New(db, nil), audit.Request{ID: "test"}, before.Lifts, before.Version, cells)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	removed := len(before.Cells[Cell{Lift: "Deadlift (con)", Column: "Hamstrings"}])
+	if changed != 1+removed {
+		t.Errorf("Save() = %d, want %d", changed, 1+removed)
+	}
+
+	after, err := mapping.Load(ctx, db, "")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for cell, want := range cells {
+		if got := after.Cells[cell]; !reflect.DeepEqual(got, want) {
+			t.Errorf("roles of %v = %q, want %q", cell, got, want)
+		}
+	}
+	var entries int
+	if err := db.QueryRow("SELECT count(DISTINCT key) FROM audit_log WHERE request_id = 'test'").Scan(&entries); err != nil {
+		t.Fatalf("failed to count audit log: %v", err)
+	}
+	if entries != changed {
+		t.Errorf("audited %d rows, want %d", entries, changed)
+	}
+
+	// The version the first save was based on is out of date.
+	_, err = mapping.Save(ctx, db, audit.New(db, nil), audit.Request{}, before.Lifts, before.Version,
+		map[Cell][]string{{Lift: "Bench (Smith)", Column: "Abs"}: {"Target"}})
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("Save() error = %v, want ErrConflict", err)
+	}
+}
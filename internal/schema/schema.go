@@ -0,0 +1,170 @@
+// Package schema inspects the foreign keys between tables to find the rows
+// that depend on a row of reference data.
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Querier runs queries on a database or transaction.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Execer runs queries and statements on a database or transaction.
+type Execer interface {
+	Querier
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Reference is a column referencing a column of another table.
+type Reference struct {
+	// Table and Column are the referencing table and column.
+	Table, Column string
+	// ParentColumn is the referenced column.
+	ParentColumn string
+	// Nullable is whether Column may be NULL.
+	Nullable bool
+}
+
+// Dependent is the number of rows referencing a value through a Reference.
+type Dependent struct {
+	Reference
+	Count int64
+}
+
+// References returns the single-column foreign keys referencing parent,
+// ordered by table and column.
+func References(ctx context.Context, db Querier, parent string) ([]Reference, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT m.name, f."from", f."to", f.seq, NOT c."notnull"
+		FROM sqlite_schema AS m
+		JOIN pragma_foreign_key_list(m.name) AS f
+		JOIN pragma_table_info(m.name) AS c ON (c.name = f."from")
+		WHERE m.type = 'table' AND f."table" = ?
+		ORDER BY m.name, f."from"`, parent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list references to %s: %w", parent, err)
+	}
+	var refs []Reference
+	for rows.Next() {
+		var (
+			ref Reference
+			to  sql.NullString
+			seq int64
+		)
+		if err := rows.Scan(&ref.Table, &ref.Column, &to, &seq, &ref.Nullable); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to scan reference to %s: %w", parent, err), rows.Close())
+		}
+		if seq > 0 {
+			// Composite foreign keys are not used by the schema.
+			continue
+		}
+		ref.ParentColumn = to.String
+		refs = append(refs, ref)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("failed to list references to %s: %w", parent, err)
+	}
+
+	for i := range refs {
+		if refs[i].ParentColumn != "" {
+			continue
+		}
+		// A foreign key without a column references the primary key.
+		if err := db.QueryRowContext(ctx,
+			"SELECT name FROM pragma_table_info(?) WHERE pk = 1", parent,
+		).Scan(&refs[i].ParentColumn); err != nil {
+			return nil, fmt.Errorf("failed to find primary key of %s: %w", parent, err)
+		}
+	}
+	return refs, nil
+}
+
+// Dependents counts the rows referencing the row of parent whose column is
+// value. Only references to column are counted.
+func Dependents(ctx context.Context, db Querier, parent, column, value string) ([]Dependent, error) {
+	refs, err := References(ctx, db, parent)
+	if err != nil {
+		return nil, err
+	}
+	var deps []Dependent
+	for _, ref := range refs {
+		if ref.ParentColumn != column {
+			continue
+		}
+		dep := Dependent{Reference: ref}
+		if err := db.QueryRowContext(ctx,
+			fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = ?", Quote(ref.Table), Quote(ref.Column)),
+			value,
+		).Scan(&dep.Count); err != nil {
+			return nil, fmt.Errorf("failed to count %s.%s: %w", ref.Table, ref.Column, err)
+		}
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
+
+// Columns returns the columns of table in order and the names of its primary
+// key columns.
+func Columns(ctx context.Context, db Querier, table string) (columns, primaryKey []string, err error) {
+	rows, err := db.QueryContext(ctx, "SELECT name, pk FROM pragma_table_info(?) ORDER BY cid", table)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list columns of %s: %w", table, err)
+	}
+	for rows.Next() {
+		var (
+			name string
+			pk   int64
+		)
+		if err := rows.Scan(&name, &pk); err != nil {
+			return nil, nil, errors.Join(fmt.Errorf("failed to scan column of %s: %w", table, err), rows.Close())
+		}
+		columns = append(columns, name)
+		if pk > 0 {
+			primaryKey = append(primaryKey, name)
+		}
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, nil, fmt.Errorf("failed to list columns of %s: %w", table, err)
+	}
+	if len(columns) == 0 {
+		return nil, nil, fmt.Errorf("no such table: %s", table)
+	}
+	return columns, primaryKey, nil
+}
+
+// Total returns the number of dependent rows.
+func Total(deps []Dependent) int64 {
+	var total int64
+	for _, dep := range deps {
+		total += dep.Count
+	}
+	return total
+}
+
+// Object returns an expression building a JSON object of the columns of a row.
+func Object(columns []string) string {
+	fields := make([]string, len(columns))
+	for i, c := range columns {
+		fields[i] = fmt.Sprintf("'%s', %s", strings.ReplaceAll(c, "'", "''"), Quote(c))
+	}
+	return "json_object(" + strings.Join(fields, ", ") + ")"
+}
+
+// Extract returns an expression extracting column from the JSON object bound
+// to its parameter.
+func Extract(column string) string {
+	path := `$."` + strings.ReplaceAll(column, `"`, `\"`) + `"`
+	return fmt.Sprintf("json_extract(?, '%s')", strings.ReplaceAll(path, "'", "''"))
+}
+
+// Quote quotes an SQL identifier.
+func Quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,130 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func setup(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if _, err := db.Exec(`
+CREATE TABLE workout (id TEXT PRIMARY KEY NOT NULL);
+CREATE TABLE lift (id TEXT PRIMARY KEY NOT NULL, alias TEXT UNIQUE);
+CREATE TABLE progress (id INTEGER PRIMARY KEY NOT NULL, lift TEXT REFERENCES lift);
+CREATE TABLE lift_workout_mapping (
+    lift TEXT NOT NULL,
+    workout TEXT NOT NULL,
+    FOREIGN KEY (lift) REFERENCES lift (id),
+    FOREIGN KEY (workout) REFERENCES workout (id)
+);
+CREATE TABLE nickname (name TEXT REFERENCES lift (alias));
+INSERT INTO workout VALUES ('a'), ('b');
+INSERT INTO lift VALUES ('squat', 'sq'), ('bench', 'bp');
+INSERT INTO progress (lift) VALUES ('squat'), ('squat'), ('bench');
+INSERT INTO lift_workout_mapping VALUES ('squat', 'a'), ('squat', 'b');
+INSERT INTO nickname VALUES ('sq');
+`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+func TestReferences(t *testing.T) {
+	db := setup(t)
+
+	got, err := References(context.Background(), db, "lift")
+	if err != nil {
+		t.Fatalf("References() error = %v", err)
+	}
+	want := []Reference{
+		{Table: "lift_workout_mapping", Column: "lift", ParentColumn: "id"},
+		{Table: "nickname", Column: "name", ParentColumn: "alias", Nullable: true},
+		{Table: "progress", Column: "lift", ParentColumn: "id", Nullable: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("References() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDependents(t *testing.T) {
+	db := setup(t)
+
+	got, err := Dependents(context.Background(), db, "lift", "id", "squat")
+	if err != nil {
+		t.Fatalf("Dependents() error = %v", err)
+	}
+	want := []Dependent{
+		{Reference: Reference{Table: "lift_workout_mapping", Column: "lift", ParentColumn: "id"}, Count: 2},
+		{Reference: Reference{Table: "progress", Column: "lift", ParentColumn: "id", Nullable: true}, Count: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependents() = %+v, want %+v", got, want)
+	}
+	if total := Total(got); total != 4 {
+		t.Errorf("Total() = %d, want 4", total)
+	}
+}
+
+func TestColumns(t *testing.T) {
+	db := setup(t)
+
+	columns, primaryKey, err := Columns(context.Background(), db, "lift")
+	if err != nil {
+		t.Fatalf("Columns() error = %v", err)
+	}
+	if !reflect.DeepEqual(columns, []string{"id", "alias"}) {
+		t.Errorf("columns = %v, want [id alias]", columns)
+	}
+	if !reflect.DeepEqual(primaryKey, []string{"id"}) {
+		t.Errorf("primary key = %v, want [id]", primaryKey)
+	}
+	if _, _, err := Columns(context.Background(), db, "nope"); err == nil {
+		t.Error("Columns() of missing table succeeded, want error")
+	}
+}
+
+func TestQuote(t *testing.T) {
+	for in, want := range map[string]string{
+		"lift":       `"lift"`,
+		`a"b`:        `"a""b"`,
+		"drop table": `"drop table"`,
+	} {
+		if got := Quote(in); got != want {
+			t.Errorf("Quote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestObject(t *testing.T) {
+	db := setup(t)
+
+	var got string
+	if err := db.QueryRow("SELECT " + Object([]string{"id", "alias"}) + " FROM lift WHERE id = 'squat'").Scan(&got); err != nil {
+		t.Fatalf("failed to select object: %v", err)
+	}
+	if want := `{"id":"squat","alias":"sq"}`; got != want {
+		t.Errorf("Object() = %s, want %s", got, want)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	db := setup(t)
+
+	var got string
+	if err := db.QueryRow("SELECT "+Extract(`it's "x"`), `{"it's \"x\"": "ok"}`).Scan(&got); err != nil {
+		t.Fatalf("failed to extract: %v", err)
+	}
+	if got != "ok" {
+		t.Errorf("Extract() = %q, want ok", got)
+	}
+}
@@ -0,0 +1,232 @@
+// Package search finds the rows of the data tables matching free text. Rows
+// are matched on their text primary key columns and their notes and templates
+// using SQLite FTS5 when it is compiled in, or LIKE otherwise.
+package search
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/RyRose/uplog/internal/schema"
+	"github.com/RyRose/uplog/internal/sqlc/dbtx"
+)
+
+// prefix starts the names of the full-text tables and their triggers.
+const prefix = "search_"
+
+// textColumns are the columns besides text primary keys that are searched.
+var textColumns = []string{"notes", "template"}
+
+// Index matches rows by free text.
+type Index struct {
+	// columns are the searched columns of each indexed table.
+	columns map[string][]string
+	// keys are the primary key columns of the tables with a full-text table.
+	keys map[string][]string
+}
+
+// New returns an Index of the tables of db with searchable columns. If FTS5 is
+// available, a full-text table kept up to date by triggers is rebuilt for
+// each of them whose primary key is text. Its rows are keyed on the primary
+// key rather than the rowid, which VACUUM may renumber. Otherwise triggers
+// left by a build with FTS5 are dropped so that writes do not need it, and
+// rows are matched with LIKE.
+func New(ctx context.Context, db dbtx.Beginner) (*Index, error) {
+	var fts bool
+	if err := db.QueryRowContext(ctx, "SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts); err != nil {
+		return nil, fmt.Errorf("failed to check for FTS5: %w", err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := dropTriggers(ctx, tx); err != nil {
+		return nil, err
+	}
+	columns, keys, err := searchable(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if !fts {
+		keys = nil
+	}
+	for table, key := range keys {
+		if err := index(ctx, tx, table, columns[table], key); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &Index{columns: columns, keys: keys}, nil
+}
+
+// FTS returns whether any rows are matched with FTS5.
+func (i *Index) FTS() bool {
+	return len(i.keys) > 0
+}
+
+// Match returns a condition on the rows of table selecting those matching
+// every word of text, along with its arguments. Tables without searchable
+// columns are matched on all of their columns.
+func (i *Index) Match(ctx context.Context, db schema.Querier, table, text string) (string, []any, error) {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return "1", nil, nil
+	}
+	cols, ok := i.columns[table]
+	if key, ok := i.keys[table]; ok {
+		terms := make([]string, len(words))
+		for j, w := range words {
+			terms[j] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"*`
+		}
+		quoted := make([]string, len(key))
+		for j, c := range key {
+			quoted[j] = schema.Quote(c)
+		}
+		cols := strings.Join(quoted, ", ")
+		name := schema.Quote(prefix + table)
+		return fmt.Sprintf("(%s) IN (SELECT %s FROM %s WHERE %s MATCH ?)", cols, cols, name, name),
+			[]any{strings.Join(terms, " ")}, nil
+	}
+	if !ok {
+		var err error
+		if cols, _, err = schema.Columns(ctx, db, table); err != nil {
+			return "", nil, err
+		}
+	}
+	var (
+		conds []string
+		args  []any
+	)
+	for _, w := range words {
+		like := make([]string, len(cols))
+		for j, c := range cols {
+			like[j] = schema.Quote(c) + ` LIKE ? ESCAPE '\'`
+			args = append(args, Contains(w))
+		}
+		conds = append(conds, "("+strings.Join(like, " OR ")+")")
+	}
+	return strings.Join(conds, " AND "), args, nil
+}
+
+// Contains returns a LIKE pattern matching text containing s.
+func Contains(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+}
+
+// searchable returns the searched columns of each table with any, and the
+// primary key columns of those whose primary key is entirely text.
+func searchable(ctx context.Context, db schema.Querier) (map[string][]string, map[string][]string, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT m.name, c.name, c.pk > 0, c.type = 'TEXT'
+		FROM sqlite_schema AS m
+		JOIN pragma_table_info(m.name) AS c
+		WHERE m.type = 'table' AND m.name NOT LIKE 'sqlite\_%' ESCAPE '\'
+			AND m.name NOT LIKE ? ESCAPE '\' AND m.sql NOT LIKE 'CREATE VIRTUAL TABLE%'
+		ORDER BY m.name, c.cid`, strings.ReplaceAll(prefix, "_", `\_`)+"%")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list searchable columns: %w", err)
+	}
+	var (
+		columns = make(map[string][]string)
+		keys    = make(map[string][]string)
+		unkeyed = make(map[string]bool)
+	)
+	for rows.Next() {
+		var (
+			table, column string
+			key, text     bool
+		)
+		if err := rows.Scan(&table, &column, &key, &text); err != nil {
+			return nil, nil, errors.Join(fmt.Errorf("failed to scan searchable column: %w", err), rows.Close())
+		}
+		switch {
+		case key && text:
+			keys[table] = append(keys[table], column)
+		case key:
+			unkeyed[table] = true
+		}
+		if (key && text) || slices.Contains(textColumns, column) {
+			columns[table] = append(columns[table], column)
+		}
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, nil, fmt.Errorf("failed to list searchable columns: %w", err)
+	}
+	for table := range keys {
+		if unkeyed[table] {
+			delete(keys, table)
+		}
+	}
+	return columns, keys, nil
+}
+
+// dropTriggers drops the triggers keeping the full-text tables up to date.
+func dropTriggers(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT name FROM sqlite_schema WHERE type = 'trigger' AND name LIKE ? ESCAPE '\'`,
+		strings.ReplaceAll(prefix, "_", `\_`)+"%")
+	if err != nil {
+		return fmt.Errorf("failed to list search triggers: %w", err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return errors.Join(fmt.Errorf("failed to scan search trigger: %w", err), rows.Close())
+		}
+		names = append(names, name)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return fmt.Errorf("failed to list search triggers: %w", err)
+	}
+	for _, name := range names {
+		if _, err := tx.ExecContext(ctx, "DROP TRIGGER "+schema.Quote(name)); err != nil {
+			return fmt.Errorf("failed to drop trigger %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// index recreates the full-text table of table over columns with the triggers
+// keeping it up to date, and fills it. Rows are found again by their key
+// columns, which are among columns.
+func index(ctx context.Context, tx *sql.Tx, table string, columns, key []string) error {
+	name := schema.Quote(prefix + table)
+	quoted := make([]string, len(columns))
+	news := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = schema.Quote(c)
+		news[i] = "new." + quoted[i]
+	}
+	match := make([]string, len(key))
+	for i, c := range key {
+		match[i] = fmt.Sprintf("%s = old.%s", schema.Quote(c), schema.Quote(c))
+	}
+	cols := strings.Join(quoted, ", ")
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", name, cols, strings.Join(news, ", "))
+	remove := fmt.Sprintf("DELETE FROM %s WHERE %s;", name, strings.Join(match, " AND "))
+	for _, stmt := range []string{
+		"DROP TABLE IF EXISTS " + name,
+		fmt.Sprintf("CREATE VIRTUAL TABLE %s USING fts5(%s)", name, cols),
+		fmt.Sprintf("CREATE TRIGGER %s AFTER INSERT ON %s BEGIN %s END",
+			schema.Quote(prefix+table+"_insert"), schema.Quote(table), insert),
+		fmt.Sprintf("CREATE TRIGGER %s AFTER DELETE ON %s BEGIN %s END",
+			schema.Quote(prefix+table+"_delete"), schema.Quote(table), remove),
+		fmt.Sprintf("CREATE TRIGGER %s AFTER UPDATE ON %s BEGIN %s %s END",
+			schema.Quote(prefix+table+"_update"), schema.Quote(table), remove, insert),
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", name, cols, cols, schema.Quote(table)),
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to index %s: %w", table, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/RyRose/uplog/internal/sqlc/sqlctest"
+)
+
+// setup returns a migrated database with a few lifts and their progress.
+func setup(t *testing.T) *sql.DB {
+	t.Helper()
+	ctx := context.Background()
+	db := sqlctest.Open(t)
+	for _, stmt := range []string{
+		"INSERT INTO lift (id, link, notes) VALUES ('test bench press', 'link', 'pause at the chest')",
+		"INSERT INTO lift (id, link) VALUES ('test squat', 'link')",
+		"INSERT INTO lift (id, link, notes) VALUES ('test deadlift', 'link', '50% of 1RM')",
+		"INSERT INTO progress (lift, date, weight, sets, reps) VALUES ('test squat', '2025-01-01', 100, 3, 5)",
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	return db
+}
+
+// find returns the keys of the rows of table matching text.
+func find(t *testing.T, index *Index, db *sql.DB, table, key, text string) []string {
+	t.Helper()
+	ctx := context.Background()
+	cond, args, err := index.Match(ctx, db, table, text)
+	if err != nil {
+		t.Fatalf("Match(%q) error = %v", text, err)
+	}
+	rows, err := db.QueryContext(ctx,
+		fmt.Sprintf("SELECT %s FROM %s WHERE %s AND %s LIKE 'test%%' ORDER BY 1", key, table, cond, key), args...)
+	if err != nil {
+		t.Fatalf("Match(%q) = %s: %v", text, cond, err)
+	}
+	defer func() { _ = rows.Close() }()
+	var keys []string
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			t.Fatalf("failed to scan: %v", err)
+		}
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("failed to select: %v", err)
+	}
+	return keys
+}
+
+func TestMatch(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	index, err := New(ctx, db)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Logf("FTS5 = %v", index.FTS())
+
+	for _, tc := range []struct {
+		text string
+		want []string
+	}{
+		{"", []string{"test bench press", "test deadlift", "test squat"}},
+		{"squat", []string{"test squat"}},
+		{"BENCH", []string{"test bench press"}},
+		{"pres", []string{"test bench press"}},
+		{"chest pause", []string{"test bench press"}},
+		{"chest squat", nil},
+		{"50%", []string{"test deadlift"}},
+	} {
+		if got := find(t, index, db, "lift", "id", tc.text); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("lifts matching %q = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+
+	// Tables without searchable columns are matched on all their columns.
+	if got := find(t, index, db, "progress", "lift", "2025-01"); !reflect.DeepEqual(got, []string{"test squat"}) {
+		t.Errorf("progress matching 2025-01 = %q, want test squat", got)
+	}
+}
+
+func TestMatch_FollowsChanges(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	index, err := New(ctx, db)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for _, stmt := range []string{
+		"UPDATE lift SET id = 'test front squat', notes = 'elbows up' WHERE id = 'test squat'",
+		"DELETE FROM lift WHERE id = 'test deadlift'",
+		"INSERT INTO lift (id, link) VALUES ('test box squat', 'link')",
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	for _, tc := range []struct {
+		text string
+		want []string
+	}{
+		{"squat", []string{"test box squat", "test front squat"}},
+		{"elbows", []string{"test front squat"}},
+		{"1RM", nil},
+	} {
+		if got := find(t, index, db, "lift", "id", tc.text); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("lifts matching %q = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+
+	// Loading the index again keeps matching the same rows.
+	if index, err = New(ctx, db); err != nil {
+		t.Fatalf("New() again error = %v", err)
+	}
+	if got := find(t, index, db, "lift", "id", "squat"); !reflect.DeepEqual(got, []string{"test box squat", "test front squat"}) {
+		t.Errorf("lifts matching squat after loading again = %q", got)
+	}
+}
+
+func TestNew_FullTextTables(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	var fts bool
+	if err := db.QueryRowContext(ctx, "SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts); err != nil {
+		t.Fatalf("failed to check for FTS5: %v", err)
+	}
+	if !fts {
+		// Triggers left by a build with FTS5 cannot run without it.
+		if _, err := db.ExecContext(ctx,
+			"CREATE TRIGGER search_lift_insert AFTER INSERT ON lift BEGIN SELECT 1; END"); err != nil {
+			t.Fatalf("failed to create trigger: %v", err)
+		}
+		index, err := New(ctx, db)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if index.FTS() {
+			t.Error("FTS() without FTS5 = true, want false")
+		}
+		var n int
+		if err := db.QueryRowContext(ctx,
+			"SELECT count(*) FROM sqlite_schema WHERE type = 'trigger' AND name LIKE 'search\\_%' ESCAPE '\\'").Scan(&n); err != nil {
+			t.Fatalf("failed to count triggers: %v", err)
+		}
+		if n != 0 {
+			t.Errorf("New() without FTS5 left %d full-text triggers, want 0", n)
+		}
+		return
+	}
+
+	// Every searchable table keyed on text is indexed, whether or not the
+	// search migration ran on a build with FTS5.
+	index, err := New(ctx, db)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for _, table := range []string{"lift", "workout", "lift_muscle_mapping"} {
+		if _, ok := index.keys[table]; !ok {
+			t.Errorf("table %s has no full-text table", table)
+		}
+	}
+
+	// Rows are still found after VACUUM renumbers the rowids.
+	for _, stmt := range []string{
+		"DELETE FROM lift WHERE id = 'test bench press'",
+		"VACUUM",
+		"UPDATE lift SET notes = 'high bar' WHERE id = 'test squat'",
+		"DELETE FROM lift WHERE id = 'test deadlift'",
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	for _, tc := range []struct {
+		text string
+		want []string
+	}{
+		{"bar", []string{"test squat"}},
+		{"squat", []string{"test squat"}},
+		{"1RM", nil},
+		{"chest", nil},
+	} {
+		if got := find(t, index, db, "lift", "id", tc.text); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("lifts matching %q after VACUUM = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestSearchable(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	if _, err := New(ctx, db); err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	columns, keys, err := searchable(ctx, db)
+	if err != nil {
+		t.Fatalf("searchable() error = %v", err)
+	}
+	for table, want := range map[string][]string{
+		"lift":                 {"id", "notes"},
+		"workout":              {"id", "template"},
+		"lift_workout_mapping": {"lift", "workout"},
+	} {
+		if got := columns[table]; !reflect.DeepEqual(got, want) {
+			t.Errorf("searchable columns of %s = %q, want %q", table, got, want)
+		}
+	}
+	if got, want := keys["lift_muscle_mapping"], []string{"lift", "muscle", "movement"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("keys of lift_muscle_mapping = %q, want %q", got, want)
+	}
+	for _, table := range []string{"progress", "goose_db_version", "audit_log", "trash_batch"} {
+		if got, ok := columns[table]; ok {
+			t.Errorf("searchable columns of %s = %q, want none", table, got)
+		}
+	}
+	for table := range columns {
+		if strings.HasPrefix(table, prefix) {
+			t.Errorf("full-text table %s is searchable", table)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package admin
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/RyRose/uplog/internal/audit"
+	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/templates"
+)
+
+// auditPageSize is the number of audit entries on a page.
+const auditPageSize = 50
+
+// HandleGetAuditView godoc
+//
+//	@Summary		Get audit log view
+//	@Description	Renders a page of the changes made through the data tables, most recent first
+//	@Tags			admin
+//	@Produce		html
+//	@Param			table	query		string	false	"Only show changes of this table"
+//	@Param			from	query		string	false	"Only show changes on or after this date (YYYY-MM-DD)"
+//	@Param			to		query		string	false	"Only show changes on or before this date (YYYY-MM-DD)"
+//	@Param			page	query		integer	false	"Page number starting at 0"
+//	@Success		200		{string}	string	"HTML content"
+//	@Failure		400		{string}	string	"Invalid filter"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/view/audit [get]
+func HandleGetAuditView(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		query := r.URL.Query()
+		data := templates.AuditViewData{
+			Table: query.Get("table"),
+			From:  query.Get("from"),
+			To:    query.Get("to"),
+		}
+		filter := audit.Filter{Table: data.Table, Limit: auditPageSize + 1}
+		var err error
+		if data.From != "" {
+			if filter.From, err = time.ParseInLocation(time.DateOnly, data.From, time.Local); err != nil {
+				return mux.Errorf(http.StatusBadRequest, "failed to parse from: %w", err)
+			}
+		}
+		if data.To != "" {
+			to, err := time.ParseInLocation(time.DateOnly, data.To, time.Local)
+			if err != nil {
+				return mux.Errorf(http.StatusBadRequest, "failed to parse to: %w", err)
+			}
+			filter.To = to.AddDate(0, 0, 1)
+		}
+		if page := query.Get("page"); page != "" {
+			if data.Page, err = strconv.Atoi(page); err != nil || data.Page < 0 {
+				return &mux.Error{Code: http.StatusBadRequest, Message: fmt.Sprintf("invalid page %q", page), Field: "page", Err: err}
+			}
+		}
+		filter.Offset = data.Page * auditPageSize
+
+		data.Entries, err = state.Audit.List(ctx, filter)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to list audit log: %w", err)
+		}
+		if len(data.Entries) > auditPageSize {
+			data.Entries, data.More = data.Entries[:auditPageSize], true
+		}
+		data.Tables, err = state.Audit.Tables(ctx)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to list audited tables: %w", err)
+		}
+		if err := templates.AuditView(data).Render(ctx, w); err != nil {
+			slog.WarnContext(ctx, "failed to render audit view", "error", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,87 @@
+// Package admin serves pages for maintaining the database.
+package admin
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/doctor"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/templates"
+)
+
+// HandleGetDoctorView godoc
+//
+//	@Summary		Get doctor view
+//	@Description	Renders the results of the integrity and foreign key checks with fixes for orphaned rows
+//	@Tags			admin
+//	@Produce		html
+//	@Success		200	{string}	string	"HTML content"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/view/doctor [get]
+func HandleGetDoctorView(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		report, err := doctor.Examine(ctx, state.RDB)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to examine database: %w", err)
+		}
+		if err := templates.DoctorView(report, "").Render(ctx, w); err != nil {
+			slog.WarnContext(ctx, "failed to render doctor view", "error", err)
+		}
+		return nil
+	}
+}
+
+// HandlePostDoctorFix godoc
+//
+//	@Summary		Fix orphaned rows
+//	@Description	Deletes, clears or reassigns the rows whose column references a missing value, then renders the doctor view
+//	@Tags			admin
+//	@Accept			x-www-form-urlencoded
+//	@Produce		html
+//	@Param			table	formData	string	true	"Referencing table"
+//	@Param			column	formData	string	true	"Referencing column"
+//	@Param			value	formData	string	true	"Missing value"
+//	@Param			action	formData	string	true	"Fix to apply"	Enums(delete, clear, reassign)
+//	@Param			target	formData	string	false	"Existing value to reassign the rows to"
+//	@Success		200		{string}	string	"HTML content"
+//	@Failure		400		{string}	string	"Invalid form data"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/view/doctor/fix [post]
+func HandlePostDoctorFix(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		if err := r.ParseForm(); err != nil {
+			return mux.Errorf(http.StatusBadRequest, "failed to parse form: %w", err)
+		}
+		action, err := doctor.ParseAction(r.FormValue("action"))
+		if err != nil {
+			return &mux.Error{Code: http.StatusBadRequest, Message: err.Error(), Field: "action", Err: err}
+		}
+		fix := doctor.Fix{
+			Table:  r.FormValue("table"),
+			Column: r.FormValue("column"),
+			Value:  r.FormValue("value"),
+			Action: action,
+			Target: r.FormValue("target"),
+		}
+		n, err := doctor.Apply(ctx, state.WDB, fix)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to fix rows: %w", err)
+		}
+		state.JLog.InfoContext(ctx, "fixed orphaned rows", "fix", fix, "rows", n)
+
+		report, err := doctor.Examine(ctx, state.RDB)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to examine database: %w", err)
+		}
+		message := fmt.Sprintf("%s: changed %d rows of %s", action, n, fix.Table)
+		if err := templates.DoctorView(report, message).Render(ctx, w); err != nil {
+			slog.WarnContext(ctx, "failed to render doctor view", "error", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/templates"
+	"github.com/RyRose/uplog/internal/trash"
+)
+
+// HandleGetTrashView godoc
+//
+//	@Summary		Get trash view
+//	@Description	Renders the deleted rows that can still be restored, most recent first
+//	@Tags			admin
+//	@Produce		html
+//	@Success		200	{string}	string	"HTML content"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/view/trash [get]
+func HandleGetTrashView(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		batches, err := state.Trash.List(ctx)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to list trash: %w", err)
+		}
+		if err := templates.TrashView(batches, "").Render(ctx, w); err != nil {
+			slog.WarnContext(ctx, "failed to render trash view", "error", err)
+		}
+		return nil
+	}
+}
+
+// HandlePostTrashRestore godoc
+//
+//	@Summary		Restore deleted rows
+//	@Description	Puts the rows removed by a delete back, then renders the trash view
+//	@Tags			admin
+//	@Produce		html
+//	@Param			batch	path		integer	true	"Trash batch ID"
+//	@Success		200		{string}	string	"HTML content"
+//	@Failure		400		{string}	string	"Invalid batch ID"
+//	@Failure		404		{string}	string	"Not found"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/view/trash/{batch}/restore [post]
+func HandlePostTrashRestore(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		batch, err := strconv.ParseInt(r.PathValue("batch"), 10, 64)
+		if err != nil {
+			return mux.Errorf(http.StatusBadRequest, "failed to parse batch: %w", err)
+		}
+		n, err := state.Trash.Restore(ctx, batch, state.Audit.Observer(state.Audit.FromRequest(r)))
+		if errors.Is(err, trash.ErrNotFound) {
+			return mux.Errorf(http.StatusNotFound, "failed to restore rows: %w", err)
+		}
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to restore rows: %w", err)
+		}
+		state.JLog.InfoContext(ctx, "restored deleted rows", "batch", batch, "rows", n)
+
+		batches, err := state.Trash.List(ctx)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to list trash: %w", err)
+		}
+		message := fmt.Sprintf("restored %d rows", n)
+		if err := templates.TrashView(batches, message).Render(ctx, w); err != nil {
+			slog.WarnContext(ctx, "failed to render trash view", "error", err)
+		}
+		return nil
+	}
+}
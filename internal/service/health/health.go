@@ -0,0 +1,218 @@
+// Package health reports whether the service is alive and ready to serve.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/sqlc"
+	"github.com/pressly/goose/v3"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Defaults for the thresholds of Checker.
+const (
+	DefaultMinFreeBytes = 100 << 20
+	DefaultMaxWALBytes  = 512 << 20
+	DefaultTimeout      = 2 * time.Second
+)
+
+// Check is the result of a single readiness check.
+type Check struct {
+	Status string         `json:"status"`
+	Error  string         `json:"error,omitempty"`
+	Detail map[string]any `json:"detail,omitempty"`
+}
+
+// Report is the result of all readiness checks. Status is StatusOK only if
+// every check is.
+type Report struct {
+	Status string           `json:"status"`
+	Checks map[string]Check `json:"checks"`
+}
+
+// Checker runs the readiness checks against the service state.
+type Checker struct {
+	RDB          *sql.DB
+	WDB          *sql.DB
+	DatabasePath string
+	// MinFreeBytes is the free disk space required next to DatabasePath.
+	MinFreeBytes uint64
+	// MaxWALBytes is the largest size of the write-ahead log considered healthy.
+	MaxWALBytes int64
+	// Timeout bounds each check.
+	Timeout time.Duration
+	// Draining is set while the server shuts down, which makes it unready.
+	Draining *atomic.Bool
+
+	// provider reads the migration versions of RDB. It is created once, on
+	// the first check, rather than parsing the migrations on every request.
+	providerOnce sync.Once
+	provider     *goose.Provider
+	providerErr  error
+}
+
+// NewChecker returns a Checker with the default thresholds.
+func NewChecker(cfg *config.Data, state *config.State) *Checker {
+	return &Checker{
+		RDB:          state.RDB,
+		WDB:          state.WDB,
+		DatabasePath: cfg.DatabasePath,
+		MinFreeBytes: DefaultMinFreeBytes,
+		MaxWALBytes:  DefaultMaxWALBytes,
+		Timeout:      DefaultTimeout,
+		Draining:     &state.Draining,
+	}
+}
+
+// Ready runs every readiness check.
+func (c *Checker) Ready(ctx context.Context) Report {
+	checks := map[string]func(context.Context) (map[string]any, error){
+		"read_db":    func(ctx context.Context) (map[string]any, error) { return nil, c.RDB.PingContext(ctx) },
+		"write_db":   func(ctx context.Context) (map[string]any, error) { return nil, c.WDB.PingContext(ctx) },
+		"migrations": c.checkMigrations,
+		"disk":       c.checkDisk,
+		"wal":        c.checkWAL,
+		"draining":   c.checkDraining,
+	}
+	report := Report{Status: StatusOK, Checks: make(map[string]Check, len(checks))}
+	for name, check := range checks {
+		ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+		detail, err := check(ctx)
+		cancel()
+		result := Check{Status: StatusOK, Detail: detail}
+		if err != nil {
+			result.Status = StatusFail
+			result.Error = err.Error()
+			report.Status = StatusFail
+		}
+		report.Checks[name] = result
+	}
+	return report
+}
+
+// checkMigrations compares the applied migration version with the latest
+// embedded migration.
+func (c *Checker) checkMigrations(ctx context.Context) (map[string]any, error) {
+	c.providerOnce.Do(func() {
+		migrations, err := fs.Sub(sqlc.EmbedMigrations, "migrations")
+		if err != nil {
+			c.providerErr = fmt.Errorf("failed to open embedded migrations: %w", err)
+			return
+		}
+		// The provider is not closed since that would close RDB.
+		c.provider, err = goose.NewProvider(goose.DialectSQLite3, c.RDB, migrations)
+		if err != nil {
+			c.providerErr = fmt.Errorf("failed to create migration provider: %w", err)
+		}
+	})
+	if c.providerErr != nil {
+		return nil, c.providerErr
+	}
+	current, target, err := c.provider.GetVersions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get migration versions: %w", err)
+	}
+	detail := map[string]any{"current": current, "target": target}
+	if current != target {
+		return detail, fmt.Errorf("database is at migration %d, want %d", current, target)
+	}
+	return detail, nil
+}
+
+func (c *Checker) checkDisk(_ context.Context) (map[string]any, error) {
+	free, err := freeBytes(filepath.Dir(c.DatabasePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get free disk space: %w", err)
+	}
+	detail := map[string]any{"free_bytes": free, "min_free_bytes": c.MinFreeBytes}
+	if free < c.MinFreeBytes {
+		return detail, fmt.Errorf("%d bytes free, want at least %d", free, c.MinFreeBytes)
+	}
+	return detail, nil
+}
+
+func (c *Checker) checkWAL(_ context.Context) (map[string]any, error) {
+	var size int64
+	info, err := os.Stat(c.DatabasePath + "-wal")
+	switch {
+	case err == nil:
+		size = info.Size()
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to stat write-ahead log: %w", err)
+	}
+	detail := map[string]any{"size_bytes": size, "max_size_bytes": c.MaxWALBytes}
+	if size > c.MaxWALBytes {
+		return detail, fmt.Errorf("write-ahead log is %d bytes, want at most %d", size, c.MaxWALBytes)
+	}
+	return detail, nil
+}
+
+func (c *Checker) checkDraining(_ context.Context) (map[string]any, error) {
+	if c.Draining != nil && c.Draining.Load() {
+		return nil, errors.New("server is shutting down")
+	}
+	return nil, nil
+}
+
+func writeJSON(ctx context.Context, w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.WarnContext(ctx, "failed to write health response", "error", err)
+	}
+}
+
+// HandleLiveness godoc
+//
+//	@Summary		Liveness check
+//	@Description	Reports that the process is running
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	Report	"Alive"
+//	@Router			/healthz [get]
+func HandleLiveness(_ *config.Data, _ *config.State) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(r.Context(), w, http.StatusOK, Report{Status: StatusOK, Checks: map[string]Check{}})
+	}
+}
+
+// HandleReadiness godoc
+//
+//	@Summary		Readiness check
+//	@Description	Checks the databases, migrations, free disk space, write-ahead log size and whether the server is shutting down
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	Report	"Ready"
+//	@Failure		503	{object}	Report	"Not ready"
+//	@Router			/readyz [get]
+func HandleReadiness(cfg *config.Data, state *config.State) http.HandlerFunc {
+	checker := NewChecker(cfg, state)
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		report := checker.Ready(ctx)
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+			slog.WarnContext(ctx, "readiness check failed", "report", report)
+		}
+		writeJSON(ctx, w, status, report)
+	}
+}
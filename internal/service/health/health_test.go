@@ -0,0 +1,108 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/RyRose/uplog/internal/config"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newState(t *testing.T) (*config.Data, *config.State) {
+	t.Helper()
+	cfg := &config.Data{DatabasePath: filepath.Join(t.TempDir(), "test.db")}
+	state, err := config.NewState(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewState() error = %v", err)
+	}
+	t.Cleanup(func() { _ = state.Close() })
+	return cfg, state
+}
+
+func TestChecker_Ready(t *testing.T) {
+	cfg, state := newState(t)
+	report := NewChecker(cfg, state).Ready(context.Background())
+	if report.Status != StatusOK {
+		t.Fatalf("Ready() status = %s, want %s: %+v", report.Status, StatusOK, report)
+	}
+	for _, name := range []string{"read_db", "write_db", "migrations", "disk", "wal", "draining"} {
+		if _, ok := report.Checks[name]; !ok {
+			t.Errorf("Ready() missing check %s", name)
+		}
+	}
+	m := report.Checks["migrations"].Detail
+	if m["current"] != m["target"] {
+		t.Errorf("migrations current = %v, want target %v", m["current"], m["target"])
+	}
+}
+
+func TestChecker_Ready_ReusesProvider(t *testing.T) {
+	cfg, state := newState(t)
+	checker := NewChecker(cfg, state)
+	checker.Ready(context.Background())
+	provider := checker.provider
+	if provider == nil {
+		t.Fatal("Ready() did not create a migration provider")
+	}
+	if report := checker.Ready(context.Background()); report.Status != StatusOK {
+		t.Fatalf("Ready() status = %s, want %s: %+v", report.Status, StatusOK, report)
+	}
+	if checker.provider != provider {
+		t.Error("Ready() created another migration provider")
+	}
+}
+
+func TestChecker_Ready_Failures(t *testing.T) {
+	cfg, state := newState(t)
+	if _, err := state.WDB.Exec(
+		"INSERT INTO goose_db_version (version_id, is_applied) VALUES (99999999999999, 1)"); err != nil {
+		t.Fatalf("failed to record unknown migration: %v", err)
+	}
+
+	checker := NewChecker(cfg, state)
+	checker.MaxWALBytes = -1
+	state.Draining.Store(true)
+	report := checker.Ready(context.Background())
+	if report.Status != StatusFail {
+		t.Fatalf("Ready() status = %s, want %s", report.Status, StatusFail)
+	}
+	for _, name := range []string{"migrations", "wal", "draining"} {
+		if got := report.Checks[name]; got.Status != StatusFail || got.Error == "" {
+			t.Errorf("check %s = %+v, want failure", name, got)
+		}
+	}
+	if got := report.Checks["read_db"].Status; got != StatusOK {
+		t.Errorf("read_db status = %s, want %s", got, StatusOK)
+	}
+}
+
+func TestHandleReadiness(t *testing.T) {
+	cfg, state := newState(t)
+	handler := HandleReadiness(cfg, state)
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var report Report
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	if report.Status != StatusOK {
+		t.Errorf("report status = %s, want %s", report.Status, StatusOK)
+	}
+
+	if err := state.RDB.Close(); err != nil {
+		t.Fatalf("failed to close read database: %v", err)
+	}
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
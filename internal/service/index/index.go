@@ -1,17 +1,26 @@
 package index
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"path"
 	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/RyRose/uplog/internal/audit"
 	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/history"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/service/rawdata/util"
+	"github.com/RyRose/uplog/internal/sqlc/dbtx"
 	"github.com/RyRose/uplog/internal/sqlc/workoutdb"
 	"github.com/RyRose/uplog/internal/templates"
+	"github.com/RyRose/uplog/internal/validate"
 )
 
 func todaysDate() time.Time {
@@ -31,58 +40,64 @@ func todaysDate() time.Time {
 //	@Router			/ [get]
 //	@Router			/data [get]
 //	@Router			/data/{tabX}/{tabY} [get]
-func HandleIndexPage(tab string, cfg *config.Data, state *config.State) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+func HandleIndexPage(tab string, cfg *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
 		err := templates.IndexPage(
-			*cfg.Version,
+			cfg.AppVersion(),
 			path.Join("/view/tabs", tab, r.PathValue("tabX"), r.PathValue("tabY")),
 		).Render(ctx, w)
 		if err != nil {
-			http.Error(w, "failed to write response", http.StatusInternalServerError)
-			slog.ErrorContext(ctx, "failed to write response", "error", err)
+			return &mux.Error{Code: http.StatusInternalServerError, Message: "failed to write response", Err: err}
 		}
+		return nil
 	}
 }
 
 // HandleMainTab godoc
 //
 //	@Summary		Get main tab view
-//	@Description	Renders the main tab view with progress for today and lift groups
+//	@Description	Renders the main tab view with progress for today and lift groups, and a progress form for each lift of the workout given by the workout query parameter of the page URL
 //	@Tags			index
 //	@Produce		html
 //	@Success		200	{string}	string	"HTML content"
+//	@Failure		400	{string}	string	"Bad request"
 //	@Failure		500	{string}	string	"Internal server error"
 //	@Router			/view/tabs/main [get]
-func HandleMainTab(_ *config.Data, state *config.State) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+func HandleMainTab(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
 		date := todaysDate()
-		queries := workoutdb.New(state.RDB)
+		queries := state.RQ
 
 		ps, err := queries.ListProgressForDay(ctx, date.Format(time.DateOnly))
 		if err != nil {
-			slog.ErrorContext(ctx, "failed to retrieve progress", "date", date, "error", err)
-			http.Error(
-				w,
-				fmt.Sprintf("failed to retrieve progress for %v: %v", date, err),
-				http.StatusInternalServerError)
-			return
+			return mux.Errorf(http.StatusInternalServerError, "failed to retrieve progress for %v: %w", date, err)
 		}
 
 		lgs, err := queries.QueryLiftGroupsForDate(ctx, date.Format(time.DateOnly))
 		if err != nil {
-			http.Error(w, "failed to query lift groups", http.StatusInternalServerError)
-			slog.ErrorContext(ctx, "failed to query lift groups", "error", err)
-			return
+			return &mux.Error{Code: http.StatusInternalServerError, Message: "failed to query lift groups", Err: err}
 		}
 
-		if err := templates.MainView(templates.MainViewData{
+		data := templates.MainViewData{
 			Progress:   ps,
 			LiftGroups: lgs,
-		}).Render(ctx, w); err != nil {
+		}
+		u, err := url.Parse(r.Header.Get("HX-Current-URL"))
+		if err != nil {
+			return mux.Errorf(http.StatusBadRequest, "failed to parse current URL: %w", err)
+		}
+		if data.Workout = u.Query().Get("workout"); data.Workout != "" {
+			if data.WorkoutForms, err = workoutForms(ctx, state, data.Workout); err != nil {
+				return mux.Errorf(http.StatusInternalServerError, "failed to list lifts of workout: %w", err)
+			}
+		}
+
+		if err := templates.MainView(data).Render(ctx, w); err != nil {
 			slog.WarnContext(ctx, "failed to render main view", "error", err)
 		}
+		return nil
 	}
 }
 
@@ -95,22 +110,21 @@ func HandleMainTab(_ *config.Data, state *config.State) http.HandlerFunc {
 //	@Success		200	{string}	string	"HTML content"
 //	@Failure		500	{string}	string	"Internal server error"
 //	@Router			/view/liftgroups [get]
-func HandleGetLiftGroupListView(_ *config.Data, state *config.State) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+func HandleGetLiftGroupListView(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
 		date := todaysDate()
-		queries := workoutdb.New(state.RDB)
+		queries := state.RQ
 
 		lgs, err := queries.QueryLiftGroupsForDate(ctx, date.Format(time.DateOnly))
 		if err != nil {
-			http.Error(w, "failed to query lift groups", http.StatusInternalServerError)
-			slog.ErrorContext(ctx, "failed to query lift groups", "error", err)
-			return
+			return &mux.Error{Code: http.StatusInternalServerError, Message: "failed to query lift groups", Err: err}
 		}
 
 		if err := templates.LiftGroupList(lgs).Render(ctx, w); err != nil {
 			slog.WarnContext(ctx, "failed to render lift group list", "error", err)
 		}
+		return nil
 	}
 }
 
@@ -123,58 +137,73 @@ func HandleGetLiftGroupListView(_ *config.Data, state *config.State) http.Handle
 //	@Success		200	{string}	string	"HTML content"
 //	@Failure		500	{string}	string	"Internal server error"
 //	@Router			/view/progresstable [get]
-func HandleGetProgressTable(_ *config.Data, state *config.State) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+func HandleGetProgressTable(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
 		date := todaysDate()
-		queries := workoutdb.New(state.RDB)
+		queries := state.RQ
 		ps, err := queries.ListProgressForDay(ctx, date.Format(time.DateOnly))
 		if err != nil {
-			slog.ErrorContext(ctx, "failed to retrieve progress", "date", date, "error", err)
-			http.Error(
-				w,
-				fmt.Sprintf("failed to retrieve progress for %v: %v", date, err),
-				http.StatusInternalServerError)
-			return
+			return mux.Errorf(http.StatusInternalServerError, "failed to retrieve progress for %v: %w", date, err)
 		}
 		if err := templates.ProgressTable(ps).Render(ctx, w); err != nil {
 			slog.WarnContext(ctx, "failed to render progress table", "error", err)
 		}
+		return nil
 	}
 }
 
 // HandleDeleteProgress godoc
 //
 //	@Summary		Delete progress entry
-//	@Description	Deletes a progress entry by ID
+//	@Description	Deletes a progress entry by ID and renders a toast to undo it
 //	@Tags			index
+//	@Produce		html
 //	@Param			id	path		int		true	"Progress ID"
-//	@Success		200	{string}	string	"OK"
+//	@Success		200	{string}	string	"HTML content"
 //	@Failure		400	{string}	string	"Bad request"
+//	@Failure		404	{string}	string	"Not found"
 //	@Failure		500	{string}	string	"Internal server error"
 //	@Router			/view/progresstablerow/{id} [delete]
-func HandleDeleteProgress(_ *config.Data, state *config.State) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+func HandleDeleteProgress(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
 		idStr := r.PathValue("id")
-		queries := workoutdb.New(state.WDB)
 		idInt, err := strconv.ParseInt(idStr, 10, 64)
 		if err != nil {
-			http.Error(
-				w,
-				fmt.Sprintf("failed to parse id (%v): %v", idStr, err),
-				http.StatusBadRequest)
-			return
-		}
-		if err := queries.DeleteProgress(ctx, idInt); err != nil {
-			http.Error(
-				w,
-				fmt.Sprintf("failed to delete progress with id (%v): %v", idInt, err),
-				http.StatusInternalServerError,
-			)
-			return
+			return mux.Errorf(http.StatusBadRequest, "failed to parse id (%v): %w", idStr, err)
+		}
+		session := history.Session(w, r)
+		tx, err := state.WDB.BeginTx(ctx, nil)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to begin transaction: %w", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+		change, err := state.History.Record(ctx, tx, session, history.Deleted, "progress", "id", idInt)
+		if errors.Is(err, history.ErrNotFound) {
+			return mux.Errorf(http.StatusNotFound, "progress with id (%v) not found", idInt)
+		}
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to record deletion: %w", err)
+		}
+		before, err := audit.Snapshot(ctx, tx, "progress", map[string]string{"id": idStr})
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to snapshot progress: %w", err)
+		}
+		if err := state.Audit.Record(ctx, tx, state.Audit.FromRequest(r), "progress", before, ""); err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to audit deletion: %w", err)
+		}
+		if err := workoutdb.New(dbtx.Wrap(tx)).DeleteProgress(ctx, idInt); err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to delete progress with id (%v): %w", idInt, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to commit deletion: %w", err)
 		}
 		w.Header().Set("HX-Trigger", "deleteProgress")
+		if err := templates.UndoToast(change.Message()).Render(ctx, w); err != nil {
+			slog.WarnContext(ctx, "failed to render undo toast", "error", err)
+		}
+		return nil
 	}
 }
 
@@ -192,50 +221,98 @@ func HandleDeleteProgress(_ *config.Data, state *config.State) http.HandlerFunc
 //	@Param			side	formData	string	false	"Side weight"
 //	@Success		200		{string}	string	"HTML content"
 //	@Failure		400		{string}	string	"Bad request"
+//	@Failure		422		{string}	string	"Progress form with the invalid fields"
 //	@Failure		500		{string}	string	"Internal server error"
 //	@Router			/view/progresstablerow [post]
-func HandleCreateProgress(_ *config.Data, state *config.State) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+func HandleCreateProgress(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
-		queries := workoutdb.New(state.WDB)
 
-		weight, err := strconv.ParseFloat(r.PostFormValue("weight"), 64)
-		if err != nil {
-			http.Error(w, "failed to parse weight", http.StatusBadRequest)
-			return
-		}
-
-		sets, err := strconv.Atoi(r.PostFormValue("sets"))
-		if err != nil {
-			http.Error(w, "failed to parse sets", http.StatusBadRequest)
-			return
-		}
-
-		reps, err := strconv.Atoi(r.PostFormValue("reps"))
-		if err != nil {
-			http.Error(w, "failed to parse reps", http.StatusBadRequest)
-			return
+		var invalid validate.Errors
+		invalid.Add("weight", validate.NonNegative(r.PostFormValue("weight")))
+		weight, _ := strconv.ParseFloat(strings.TrimSpace(r.PostFormValue("weight")), 64)
+		count := func(field string) int64 {
+			n, err := strconv.ParseInt(strings.TrimSpace(r.PostFormValue(field)), 10, 64)
+			if err != nil {
+				invalid.Add(field, errors.New("must be a whole number"))
+			} else if n < 0 {
+				invalid.Add(field, errors.New("must not be negative"))
+			}
+			return n
 		}
+		sets, reps := count("sets"), count("reps")
 
 		params := workoutdb.InsertProgressParams{
 			Lift:       r.PostFormValue("lift"),
 			Date:       todaysDate().Format(time.DateOnly),
 			Weight:     weight,
-			Sets:       int64(sets),
-			Reps:       int64(reps),
-			SideWeight: r.PostFormValue("side"),
+			Sets:       sets,
+			Reps:       reps,
+			SideWeight: util.DeZero(r.PostFormValue("side")),
 		}
-		progress, err := queries.InsertProgress(ctx, params)
+		session := history.Session(w, r)
+		tx, err := state.WDB.BeginTx(ctx, nil)
 		if err != nil {
-			slog.ErrorContext(ctx, "failed to insert progress", "error", err, "params", params)
-			http.Error(w, fmt.Sprintf("Failed to insert progress: %v", err), http.StatusBadRequest)
-			return
+			return mux.Errorf(http.StatusInternalServerError, "failed to begin transaction: %w", err)
 		}
+		defer func() { _ = tx.Rollback() }()
+		refs := []validate.Ref{{Field: "lift", Table: "lift", Value: params.Lift}}
+		if side := r.PostFormValue("side"); side != "" {
+			refs = append(refs, validate.Ref{Field: "side", Table: "side_weight", Value: side})
+		}
+		missing, err := validate.References(ctx, tx, refs)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to validate progress: %w", err)
+		}
+		if invalid = append(invalid, missing...); len(invalid) > 0 {
+			slog.WarnContext(ctx, "invalid progress", "error", invalid)
+			// The form is rendered in place with the problems to fix.
+			data := progressFormData(ctx, state, r)
+			data.Errors = invalid
+			w.Header().Set("HX-Retarget", "closest form")
+			w.Header().Set("HX-Reswap", "outerHTML")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			if err := templates.ProgressForm(data).Render(ctx, w); err != nil {
+				slog.WarnContext(ctx, "failed to render progress form", "error", err)
+			}
+			return nil
+		}
+		progress, err := workoutdb.New(dbtx.Wrap(tx)).InsertProgress(ctx, params)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to insert progress: %w", err)
+		}
+		change, err := state.History.Record(ctx, tx, session, history.Inserted, "progress", "id", progress.ID)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to record insertion: %w", err)
+		}
+		after, err := audit.Snapshot(ctx, tx, "progress", map[string]string{"id": strconv.FormatInt(progress.ID, 10)})
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to snapshot progress: %w", err)
+		}
+		if err := state.Audit.Record(ctx, tx, state.Audit.FromRequest(r), "progress", "", after); err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to audit insertion: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to commit insertion: %w", err)
+		}
+
+		group := "none"
+		lift, err := state.RQ.GetLift(ctx, progress.Lift)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to get lift group for metrics", "error", err, "lift", progress.Lift)
+		} else if lift.LiftGroup != nil {
+			group = *lift.LiftGroup
+		}
+		state.Metrics.ProgressLogged.WithLabelValues(group).Inc()
 
 		w.Header().Set("HX-Trigger", "newProgress")
 		if err := templates.ProgressTableRow(progress).Render(ctx, w); err != nil {
 			slog.WarnContext(ctx, "failed to render progress table row", "error", err)
 		}
+		if err := templates.UndoToast(change.Message()).Render(ctx, w); err != nil {
+			slog.WarnContext(ctx, "failed to render undo toast", "error", err)
+		}
+		return nil
 	}
 }
 
@@ -246,10 +323,11 @@ func HandleCreateProgress(_ *config.Data, state *config.State) http.HandlerFunc
 //	@Tags			index
 //	@Success		200	{string}	string	"OK"
 //	@Router			/view/routinetable [get]
-func HandleGetRoutineTable(_ *config.Data, state *config.State) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+func HandleGetRoutineTable(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		// Routine table functionality removed - schedule concept eliminated
 		w.WriteHeader(http.StatusOK)
+		return nil
 	}
 }
 
@@ -262,15 +340,15 @@ func HandleGetRoutineTable(_ *config.Data, state *config.State) http.HandlerFunc
 //	@Param			name	query		string	false	"Input name attribute"
 //	@Param			lift	query		string	false	"Selected lift ID"
 //	@Success		200		{string}	string	"HTML content"
+//	@Failure		500		{string}	string	"Internal server error"
 //	@Router			/view/liftselect [get]
-func HandleGetLiftSelect(_ *config.Data, state *config.State) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+func HandleGetLiftSelect(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
-		queries := workoutdb.New(state.RDB)
+		queries := state.RQ
 		lifts, err := queries.RawSelectLift(ctx)
 		if err != nil {
-			slog.ErrorContext(ctx, "failed to list all lifts", "error", err)
-			return
+			return mux.Errorf(http.StatusInternalServerError, "failed to list lifts: %w", err)
 		}
 
 		mapLifts := make(map[string][]string)
@@ -306,6 +384,7 @@ func HandleGetLiftSelect(_ *config.Data, state *config.State) http.HandlerFunc {
 		).Render(ctx, w); err != nil {
 			slog.WarnContext(ctx, "failed to render lift select", "error", err)
 		}
+		return nil
 	}
 }
 
@@ -317,25 +396,26 @@ func HandleGetLiftSelect(_ *config.Data, state *config.State) http.HandlerFunc {
 //	@Produce		html
 //	@Param			name	query		string	false	"Input name attribute"
 //	@Param			lift	query		string	false	"Lift ID to get default side weight"
+//	@Param			side	query		string	false	"Selected side weight, instead of the default of the lift"
 //	@Success		200		{string}	string	"HTML content"
+//	@Failure		500		{string}	string	"Internal server error"
 //	@Router			/view/sideweightselect [get]
-func HandleGetSideWeightSelect(_ *config.Data, state *config.State) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+func HandleGetSideWeightSelect(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
-		queries := workoutdb.New(state.RDB)
+		queries := state.RQ
 		name := r.URL.Query().Get("name")
 		sideWeights, err := queries.ListAllIndividualSideWeights(ctx)
 		if err != nil {
-			slog.ErrorContext(ctx, "failed to list all side weights", "error", err)
-			return
+			return mux.Errorf(http.StatusInternalServerError, "failed to list side weights: %w", err)
 		}
 
 		liftParam := r.URL.Query().Get("lift")
-		if liftParam == "" {
-			if err := templates.SideweightSelect(name, "", sideWeights).Render(ctx, w); err != nil {
+		if side := r.URL.Query().Get("side"); liftParam == "" || side != "" {
+			if err := templates.SideweightSelect(name, side, sideWeights).Render(ctx, w); err != nil {
 				slog.WarnContext(ctx, "failed to render sideweight select", "error", err)
 			}
-			return
+			return nil
 		}
 
 		lift, err := queries.GetLift(ctx, liftParam)
@@ -344,7 +424,7 @@ func HandleGetSideWeightSelect(_ *config.Data, state *config.State) http.Handler
 			if err := templates.SideweightSelect(name, "", sideWeights).Render(ctx, w); err != nil {
 				slog.WarnContext(ctx, "failed to render sideweight select", "error", err)
 			}
-			return
+			return nil
 		}
 
 		selected := ""
@@ -357,6 +437,7 @@ func HandleGetSideWeightSelect(_ *config.Data, state *config.State) http.Handler
 			sideWeights).Render(ctx, w); err != nil {
 			slog.WarnContext(ctx, "failed to render sideweight select", "error", err)
 		}
+		return nil
 	}
 }
 
@@ -368,11 +449,12 @@ func HandleGetSideWeightSelect(_ *config.Data, state *config.State) http.Handler
 //	@Produce		html
 //	@Success		200	{string}	string	"HTML content"
 //	@Router			/view/progressform [get]
-func HandleGetProgressForm() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+func HandleGetProgressForm() mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		if err := templates.ProgressForm(templates.ProgressFormData{}).Render(r.Context(), w); err != nil {
 			slog.WarnContext(r.Context(), "failed to render progress form", "error", err)
 		}
+		return nil
 	}
 }
 
@@ -388,36 +470,43 @@ func HandleGetProgressForm() http.HandlerFunc {
 //	@Param			weight	formData	string	false	"Weight"
 //	@Param			sets	formData	string	false	"Sets"
 //	@Param			reps	formData	string	false	"Reps"
+//	@Param			keep	formData	string	false	"Whether the form is filled in again once logged, as one of the forms of a workout"
 //	@Success		200		{string}	string	"HTML content"
 //	@Router			/view/progressform [post]
-func HandleCreateProgressForm(_ *config.Data, state *config.State) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+func HandleCreateProgressForm(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
-		queries := workoutdb.New(state.RDB)
-
-		lift := r.PostFormValue("lift")
-		var progress []workoutdb.Progress
-		if lift != "" {
-			var err error
-			progress, err = queries.ListMostRecentProgressForLift(ctx,
-				workoutdb.ListMostRecentProgressForLiftParams{
-					Lift:  lift,
-					Limit: 5,
-				})
-			if err != nil {
-				slog.WarnContext(ctx, "failed to list most recent progress for lift", "lift", lift, "error", err)
-				progress = nil
-			}
-		}
-		if err := templates.ProgressForm(templates.ProgressFormData{
-			Lift:       lift,
-			SideWeight: r.PostFormValue("side"),
-			Weight:     r.PostFormValue("weight"),
-			Sets:       r.PostFormValue("sets"),
-			Reps:       r.PostFormValue("reps"),
-			Progress:   progress,
-		}).Render(ctx, w); err != nil {
+		if err := templates.ProgressForm(progressFormData(ctx, state, r)).Render(ctx, w); err != nil {
 			slog.WarnContext(ctx, "failed to render progress form", "error", err)
 		}
+		return nil
+	}
+}
+
+// progressFormData returns the progress form filled in with the values of the
+// form of r and the most recent progress of its lift.
+func progressFormData(ctx context.Context, state *config.State, r *http.Request) templates.ProgressFormData {
+	lift := r.PostFormValue("lift")
+	var progress []workoutdb.Progress
+	if lift != "" {
+		var err error
+		progress, err = state.RQ.ListMostRecentProgressForLift(ctx,
+			workoutdb.ListMostRecentProgressForLiftParams{
+				Lift:  lift,
+				Limit: 5,
+			})
+		if err != nil {
+			slog.WarnContext(ctx, "failed to list most recent progress for lift", "lift", lift, "error", err)
+			progress = nil
+		}
+	}
+	return templates.ProgressFormData{
+		Lift:       lift,
+		SideWeight: r.PostFormValue("side"),
+		Weight:     r.PostFormValue("weight"),
+		Sets:       r.PostFormValue("sets"),
+		Reps:       r.PostFormValue("reps"),
+		Keep:       r.PostFormValue("keep") != "",
+		Progress:   progress,
 	}
 }
@@ -0,0 +1,127 @@
+package index
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/RyRose/uplog/internal/audit"
+	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/service/rawdata/base"
+	"github.com/RyRose/uplog/internal/templates"
+	"github.com/RyRose/uplog/internal/training"
+)
+
+// liftHistoryLimit is the number of the most recent progress entries shown
+// on the page of a lift.
+const liftHistoryLimit = 20
+
+// HandleLiftPage godoc
+//
+//	@Summary		Get lift page
+//	@Description	Renders the index page showing everything about a lift
+//	@Tags			index
+//	@Produce		html
+//	@Param			id	path		string	true	"Lift ID"
+//	@Success		200	{string}	string	"HTML content"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/lift/{id} [get]
+func HandleLiftPage(cfg *config.Data, _ *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		err := templates.IndexPage(
+			cfg.AppVersion(),
+			"/view"+templates.LiftURL(r.PathValue("id")),
+		).Render(r.Context(), w)
+		if err != nil {
+			return &mux.Error{Code: http.StatusInternalServerError, Message: "failed to write response", Err: err}
+		}
+		return nil
+	}
+}
+
+// HandleGetLiftView godoc
+//
+//	@Summary		Get lift view
+//	@Description	Renders a lift as a row of the lifts data table to edit inline, along with the muscles it moves by movement, the routines and workouts using it, including workouts containing those as subworkouts, its records by reps and its most recent progress
+//	@Tags			index
+//	@Produce		html
+//	@Param			id	path		string	true	"Lift ID"
+//	@Success		200	{string}	string	"HTML content"
+//	@Failure		404	{string}	string	"Lift not found"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/view/lift/{id} [get]
+func HandleGetLiftView(e base.Entity, _ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		id := r.PathValue("id")
+		stored, err := audit.Snapshot(ctx, state.RPrepared, e.Table, map[string]string{"id": id})
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to select lift: %w", err)
+		}
+		if stored == "" {
+			return mux.Errorf(http.StatusNotFound, "lift %q not found", id)
+		}
+		row, err := e.Row(stored)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to convert lift to row: %w", err)
+		}
+		data := templates.LiftViewData{ID: id, Headers: e.Headers(), Row: *row}
+
+		muscles, err := state.RQ.ListMusclesForLift(ctx, id)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to list muscles: %w", err)
+		}
+		for _, m := range muscles {
+			if n := len(data.Roles); n == 0 || data.Roles[n-1].Movement != m.Movement {
+				data.Roles = append(data.Roles, templates.LiftRole{Movement: m.Movement})
+			}
+			role := &data.Roles[len(data.Roles)-1]
+			role.Muscles = append(role.Muscles, m.Muscle)
+		}
+
+		if data.Routines, err = state.RQ.ListRoutinesForLift(ctx, id); err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to list routines: %w", err)
+		}
+
+		workouts, err := state.RQ.ListWorkoutsForLift(ctx, id)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to list workouts: %w", err)
+		}
+		for _, uses := range workouts {
+			if n := len(data.Workouts); n == 0 || data.Workouts[n-1].Workout != uses.Workout {
+				data.Workouts = append(data.Workouts, templates.LiftWorkout{Workout: uses.Workout})
+			}
+			workout := &data.Workouts[len(data.Workouts)-1]
+			switch {
+			case uses.Routine != "":
+				workout.Routines = append(workout.Routines, uses.Routine)
+			case uses.Subworkout != "":
+				workout.Subworkouts = append(workout.Subworkouts, uses.Subworkout)
+			default:
+				workout.Mapped = true
+			}
+		}
+
+		progress, err := state.RQ.ListTrainingProgressForLift(ctx, id)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to list progress: %w", err)
+		}
+		data.History = progress[:min(len(progress), liftHistoryLimit)]
+		entries := make([]training.Entry, 0, len(progress))
+		for _, p := range progress {
+			entries = append(entries, training.Entry{
+				Lift:   id,
+				Date:   p.Date,
+				Weight: p.Weight*p.Multiplier + p.Addend,
+				Sets:   p.Sets,
+				Reps:   p.Reps,
+			})
+		}
+		data.Records = training.Records(entries)
+
+		if err := templates.LiftView(data).Render(ctx, w); err != nil {
+			slog.WarnContext(ctx, "failed to render lift view", "error", err)
+		}
+		return nil
+	}
+}
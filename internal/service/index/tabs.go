@@ -5,6 +5,9 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/RyRose/uplog/internal/matrix"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/service/rawdata"
 	"github.com/RyRose/uplog/internal/templates"
 )
 
@@ -19,53 +22,36 @@ import (
 //	@Success		200		{string}	string	"HTML content"
 //	@Failure		400		{string}	string	"Invalid tab index"
 //	@Router			/view/tabs/data/{tabX}/{tabY} [get]
-func HandleGetDataTabView() http.HandlerFunc {
-	tabs := [][]templates.DataTab{
-		{
-			{Title: "Lifts", Endpoint: "/view/data/lift"},
-			{Title: "Movements", Endpoint: "/view/data/movement"},
-			{Title: "Muscles", Endpoint: "/view/data/muscle"},
-			{Title: "Routines", Endpoint: "/view/data/routine"},
-			{Title: "Workouts", Endpoint: "/view/data/workout"},
-		},
-		{
-			{Title: "Variables", Endpoint: "/view/data/template_variable"},
-			{Title: "Lift Groups", Endpoint: "/view/data/lift_group"},
-		},
-		{
-			{Title: "Side Weight", Endpoint: "/view/data/side_weight"},
-			{Title: "Progress", Endpoint: "/view/data/progress"},
-			{Title: "Subworkouts", Endpoint: "/view/data/subworkout"},
-		},
-		{
-			{Title: "Routine:Workout", Endpoint: "/view/data/routine_workout_mapping"},
-			{Title: "Lift:Muscle", Endpoint: "/view/data/lift_muscle_mapping"},
-			{Title: "Lift:Workout", Endpoint: "/view/data/lift_workout_mapping"},
-		},
+func HandleGetDataTabView() mux.HandlerFunc {
+	var matrices []templates.DataTab
+	for _, m := range matrix.Mappings {
+		matrices = append(matrices, templates.DataTab{Title: m.Title, Endpoint: m.Endpoint()})
 	}
-	return func(w http.ResponseWriter, r *http.Request) {
+	tabs := append(rawdata.Tabs(), matrices, []templates.DataTab{
+		{Title: "Doctor", Endpoint: "/view/doctor"},
+		{Title: "Trash", Endpoint: "/view/trash"},
+		{Title: "Audit", Endpoint: "/view/audit"},
+	})
+	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
 		rawTabX, rawTabY := r.PathValue("tabX"), r.PathValue("tabY")
 		if rawTabX == "" && rawTabY == "" {
 			if err := templates.DataTabView(tabs, 0, 0).Render(ctx, w); err != nil {
 				slog.WarnContext(ctx, "failed to render data tab view", "error", err)
 			}
-			return
+			return nil
 		}
 		tabX, err := strconv.Atoi(rawTabX)
 		if err != nil || tabX < 0 || tabX >= len(tabs) {
-			http.Error(w, "invalid tab index", http.StatusBadRequest)
-			slog.ErrorContext(ctx, "invalid tab index", "tabX", r.PathValue("tabX"), "error", err)
-			return
+			return &mux.Error{Code: http.StatusBadRequest, Message: "invalid tab index", Field: "tabX", Err: err}
 		}
 		tabY, err := strconv.Atoi(rawTabY)
 		if err != nil || tabY < 0 || tabY >= len(tabs[tabX]) {
-			http.Error(w, "invalid tab index", http.StatusBadRequest)
-			slog.ErrorContext(ctx, "invalid tab index", "tabY", r.PathValue("tabY"), "error", err)
-			return
+			return &mux.Error{Code: http.StatusBadRequest, Message: "invalid tab index", Field: "tabY", Err: err}
 		}
 		if err := templates.DataTabView(tabs, tabX, tabY).Render(ctx, w); err != nil {
 			slog.WarnContext(ctx, "failed to render data tab view", "error", err)
 		}
+		return nil
 	}
 }
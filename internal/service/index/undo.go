@@ -0,0 +1,36 @@
+package index
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/history"
+	"github.com/RyRose/uplog/internal/service/mux"
+)
+
+// HandlePostUndo godoc
+//
+//	@Summary		Undo the last change
+//	@Description	Reverts the most recent change made by the session and triggers undoChange so views reload
+//	@Tags			index
+//	@Success		200	{string}	string	"OK"
+//	@Failure		404	{string}	string	"Nothing to undo"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/view/undo [post]
+func HandlePostUndo(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		change, err := state.History.Undo(ctx, history.Session(w, r), state.Audit.Observer(state.Audit.FromRequest(r)))
+		if errors.Is(err, history.ErrNotFound) {
+			return mux.Errorf(http.StatusNotFound, "nothing to undo: %w", err)
+		}
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to undo: %w", err)
+		}
+		state.JLog.InfoContext(ctx, "undid change", "table", change.Table, "key", change.Key, "action", change.Action)
+		w.Header().Set("HX-Trigger", "undoChange")
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
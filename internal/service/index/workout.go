@@ -0,0 +1,147 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/sqlc/workoutdb"
+	"github.com/RyRose/uplog/internal/templates"
+	"github.com/RyRose/uplog/internal/workout"
+)
+
+// HandleWorkoutPage godoc
+//
+//	@Summary		Get workout page
+//	@Description	Renders the index page showing everything about a workout
+//	@Tags			index
+//	@Produce		html
+//	@Param			id	path		string	true	"Workout ID"
+//	@Success		200	{string}	string	"HTML content"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/workout/{id} [get]
+func HandleWorkoutPage(cfg *config.Data, _ *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		err := templates.IndexPage(
+			cfg.AppVersion(),
+			"/view"+templates.WorkoutURL(r.PathValue("id")),
+		).Render(r.Context(), w)
+		if err != nil {
+			return &mux.Error{Code: http.StatusInternalServerError, Message: "failed to write response", Err: err}
+		}
+		return nil
+	}
+}
+
+// HandleGetWorkoutView godoc
+//
+//	@Summary		Get workout view
+//	@Description	Renders a workout with its template variables expanded, the lifts and routines mapped to it and a graph of the superworkouts containing it and the subworkouts it contains, with a link to log each of its lifts
+//	@Tags			index
+//	@Produce		html
+//	@Param			id	path		string	true	"Workout ID"
+//	@Success		200	{string}	string	"HTML content"
+//	@Failure		404	{string}	string	"Workout not found"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/view/workout/{id} [get]
+func HandleGetWorkoutView(_ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		id := r.PathValue("id")
+		stored, err := state.RQ.GetWorkout(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return mux.Errorf(http.StatusNotFound, "workout %q not found", id)
+		}
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to get workout: %w", err)
+		}
+		data := templates.WorkoutViewData{ID: id}
+
+		variables, err := state.RQ.ListTemplateVariables(ctx)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to list template variables: %w", err)
+		}
+		values := make(map[string]string, len(variables))
+		for _, v := range variables {
+			values[v.ID] = v.Value
+		}
+		data.Template = workout.Expand(stored.Template, values)
+
+		if data.Lifts, err = state.RQ.ListLiftsForWorkout(ctx, id); err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to list lifts: %w", err)
+		}
+		if data.Routines, err = state.RQ.ListRoutinesForWorkout(ctx, id); err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to list routines: %w", err)
+		}
+
+		subworkouts, err := state.RQ.ListSubworkouts(ctx)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to list subworkouts: %w", err)
+		}
+		relations := make([]workout.Relation, 0, len(subworkouts))
+		for _, s := range subworkouts {
+			relations = append(relations, workout.Relation{Subworkout: s.Subworkout, Superworkout: s.Superworkout})
+		}
+		data.Graph = workout.Hierarchy(id, relations)
+
+		if err := templates.WorkoutView(data).Render(ctx, w); err != nil {
+			slog.WarnContext(ctx, "failed to render workout view", "error", err)
+		}
+		return nil
+	}
+}
+
+// workoutForms returns a progress form for each lift of the workout id, mapped
+// to it or to its routines, filled in with the most recent progress of the
+// lift and kept filled in as it is logged.
+func workoutForms(ctx context.Context, state *config.State, id string) ([]templates.ProgressFormData, error) {
+	lifts, err := state.RQ.ListLiftsForWorkout(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	routines, err := state.RQ.ListRoutinesForWorkout(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, routine := range routines {
+		if !slices.Contains(ids, routine.Lift) {
+			ids = append(ids, routine.Lift)
+		}
+	}
+	for _, lift := range lifts {
+		if !slices.Contains(ids, lift.ID) {
+			ids = append(ids, lift.ID)
+		}
+	}
+
+	var forms []templates.ProgressFormData
+	for _, lift := range ids {
+		progress, err := state.RQ.ListMostRecentProgressForLift(ctx,
+			workoutdb.ListMostRecentProgressForLiftParams{
+				Lift:  lift,
+				Limit: 5,
+			})
+		if err != nil {
+			return nil, err
+		}
+		form := templates.ProgressFormData{Lift: lift, Progress: progress, Keep: true}
+		if len(progress) > 0 {
+			latest := progress[0]
+			form.Weight = fmt.Sprint(latest.Weight)
+			form.Sets = fmt.Sprint(latest.Sets)
+			form.Reps = fmt.Sprint(latest.Reps)
+			if side, ok := latest.SideWeight.(string); ok {
+				form.SideWeight = side
+			}
+		}
+		forms = append(forms, form)
+	}
+	return forms, nil
+}
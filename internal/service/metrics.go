@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/training"
+)
+
+// registerTrainingMetrics registers the training metrics collector on the
+// state's registry if enabled.
+func registerTrainingMetrics(cfg *config.Data, state *config.State) error {
+	if !cfg.TrainingMetrics.Enabled {
+		return nil
+	}
+	queries := state.RQ
+	collector := training.NewCollector(func(ctx context.Context, since string) ([]training.Entry, error) {
+		rows, err := queries.ListTrainingProgress(ctx, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list training progress: %w", err)
+		}
+		entries := make([]training.Entry, 0, len(rows))
+		for _, row := range rows {
+			var group string
+			if row.LiftGroup != nil {
+				group = *row.LiftGroup
+			}
+			entries = append(entries, training.Entry{
+				Lift:      row.Lift,
+				LiftGroup: group,
+				Date:      row.Date,
+				Weight:    row.Weight*row.Multiplier + row.Addend,
+				Sets:      row.Sets,
+				Reps:      row.Reps,
+			})
+		}
+		return entries, nil
+	}, time.Duration(cfg.TrainingMetrics.CacheSeconds)*time.Second)
+	if err := state.PrometheusRegistry.Register(collector); err != nil {
+		return fmt.Errorf("failed to register training metrics: %w", err)
+	}
+	return nil
+}
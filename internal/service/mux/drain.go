@@ -0,0 +1,24 @@
+package mux
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Drain rejects requests that may write with 503 Service Unavailable once
+// draining is set, so that in-flight requests can finish during shutdown
+// without new changes being started. Safe methods are still served.
+func Drain(draining *atomic.Bool, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			if draining.Load() {
+				w.Header().Set("Connection", "close")
+				WriteError(w, r, Errorf(http.StatusServiceUnavailable, "server is shutting down"))
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
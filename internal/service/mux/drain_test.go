@@ -0,0 +1,37 @@
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDrain(t *testing.T) {
+	var draining atomic.Bool
+	handler := Drain(&draining, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		method   string
+		draining bool
+		want     int
+	}{
+		{"GET", false, http.StatusOK},
+		{"POST", false, http.StatusOK},
+		{"GET", true, http.StatusOK},
+		{"HEAD", true, http.StatusOK},
+		{"POST", true, http.StatusServiceUnavailable},
+		{"PATCH", true, http.StatusServiceUnavailable},
+		{"DELETE", true, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		draining.Store(tt.draining)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(tt.method, "/", nil))
+		if w.Code != tt.want {
+			t.Errorf("%s while draining=%v: status = %d, want %d", tt.method, tt.draining, w.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package mux
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/RyRose/uplog/internal/telemetry"
+	"github.com/RyRose/uplog/internal/templates"
+)
+
+// Error is an error of a handler along with how to respond to it.
+type Error struct {
+	// Code is the HTTP status of the response.
+	Code int
+	// Message is shown to the user. It should not contain the text of
+	// internal errors, which belong in Err.
+	Message string
+	// Field is the form field the error is about, if any.
+	Field string
+	// Retryable reports whether the request may succeed if it is repeated
+	// later as is.
+	Retryable bool
+	// Err is the cause of the error, which is logged.
+	Err error
+}
+
+// Error returns the message shown to the user followed by the cause.
+func (e *Error) Error() string {
+	if e.Err == nil || e.Err.Error() == e.Message {
+		return e.userMessage()
+	}
+	if strings.HasPrefix(e.Err.Error(), e.Message+": ") {
+		// The cause was formatted by Errorf and already starts with Message.
+		return e.fieldPrefix() + e.Err.Error()
+	}
+	return e.userMessage() + ": " + e.Err.Error()
+}
+
+func (e *Error) fieldPrefix() string {
+	if e.Field != "" {
+		return e.Field + ": "
+	}
+	return ""
+}
+
+// userMessage returns the message shown to the user, prefixed with its field.
+func (e *Error) userMessage() string {
+	return e.fieldPrefix() + e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Errorf returns an Error with code whose cause is formatted as by fmt.Errorf.
+// Its message leaves out the errors wrapped with %w, along with the ": "
+// before them, so that their internal text is only logged. Service
+// Unavailable errors are retryable.
+func Errorf(code int, format string, args ...any) *Error {
+	err := fmt.Errorf(format, args...)
+	userFormat, userArgs := withoutWrapped(format, args)
+	return &Error{
+		Code:      code,
+		Message:   fmt.Sprintf(userFormat, userArgs...),
+		Retryable: code == http.StatusServiceUnavailable,
+		Err:       err,
+	}
+}
+
+// withoutWrapped returns format and args without the %w verbs and their
+// operands.
+func withoutWrapped(format string, args []any) (string, []any) {
+	var (
+		b    strings.Builder
+		kept []any
+		arg  int
+	)
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			b.WriteByte(format[i])
+			continue
+		}
+		if i+1 < len(format) && format[i+1] == '%' {
+			b.WriteString("%%")
+			i++
+			continue
+		}
+		// Flags, width and precision run up to the verb letter.
+		j := i + 1
+		for j < len(format) && !unicode.IsLetter(rune(format[j])) {
+			j++
+		}
+		if j == len(format) {
+			b.WriteString(format[i:])
+			break
+		}
+		if format[j] == 'w' {
+			trimmed := strings.TrimRight(strings.TrimSuffix(strings.TrimRight(b.String(), " "), ":"), " ")
+			b.Reset()
+			b.WriteString(trimmed)
+		} else {
+			b.WriteString(format[i : j+1])
+			if arg < len(args) {
+				kept = append(kept, args[arg])
+			}
+		}
+		arg++
+		i = j
+	}
+	return b.String(), kept
+}
+
+// HandlerFunc is a handler that returns its errors rather than writing them,
+// so that they are written by WriteError.
+type HandlerFunc func(http.ResponseWriter, *http.Request) error
+
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		WriteError(w, r, err)
+	}
+}
+
+// WriteError logs err and writes its message as the response to r with its
+// status. Errors that are not an Error are internal server errors whose text
+// is only logged. htmx requests are
+// answered with an alert retargeted to #alerts, leaving their target as is,
+// and other requests with the message as text.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	ctx := r.Context()
+	var e *Error
+	if !errors.As(err, &e) {
+		e = &Error{Code: http.StatusInternalServerError, Message: "internal server error", Err: err}
+	}
+	if e.Code >= http.StatusInternalServerError {
+		slog.ErrorContext(ctx, "request failed", "error", err, "status", e.Code, "path", r.URL.Path)
+	} else {
+		slog.WarnContext(ctx, "request failed", "error", err, "status", e.Code, "path", r.URL.Path)
+	}
+
+	message := e.userMessage()
+	if id := telemetry.TraceID(ctx); id != "" {
+		message += fmt.Sprintf(" (trace %s)", id)
+	}
+	// Errors are written as is rather than as an alert of an alert.
+	if ew, ok := w.(*errorResponseWriter); ok {
+		w = ew.ResponseWriter
+	}
+	if e.Retryable {
+		w.Header().Set("Retry-After", "5")
+	}
+	if r.Header.Get("HX-Request") != "true" {
+		http.Error(w, message, e.Code)
+		return
+	}
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("HX-Retarget", "#alerts")
+	w.Header().Set("HX-Reswap", "beforeend")
+	w.WriteHeader(e.Code)
+	if err := templates.Alert(message, e.Retryable).Render(ctx, w); err != nil {
+		slog.WarnContext(ctx, "failed to render alert", "error", err)
+	}
+}
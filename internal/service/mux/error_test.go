@@ -0,0 +1,105 @@
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrorf(t *testing.T) {
+	cause := errors.New("database is locked")
+	err := Errorf(http.StatusServiceUnavailable, "failed to insert data: %w", cause)
+
+	if err.Code != http.StatusServiceUnavailable || !err.Retryable {
+		t.Errorf("expected a retryable %d error, got %+v", http.StatusServiceUnavailable, err)
+	}
+	if err.Message != "failed to insert data" {
+		t.Errorf("unexpected message %q", err.Message)
+	}
+	if err.Error() != "failed to insert data: database is locked" {
+		t.Errorf("unexpected error %q", err.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("expected %v to wrap %v", err, cause)
+	}
+
+	// Every wrapped error is kept in the cause but left out of the message.
+	other := errors.New("disk I/O error")
+	err = Errorf(http.StatusInternalServerError, "failed to save %q at 100%%: %w: %w", "x", cause, other)
+	if err.Message != `failed to save "x" at 100%` {
+		t.Errorf("unexpected message %q", err.Message)
+	}
+	if !errors.Is(err, cause) || !errors.Is(err, other) {
+		t.Errorf("expected %v to wrap %v and %v", err, cause, other)
+	}
+	if Errorf(http.StatusBadRequest, "bad").Retryable {
+		t.Error("expected bad requests not to be retryable")
+	}
+}
+
+func TestHandlerFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		htmx       bool
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "no error",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "field error",
+			err:        &Error{Code: http.StatusBadRequest, Message: "must not be empty", Field: "id"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "id: must not be empty\n",
+		},
+		{
+			name:       "other error",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "internal server error\n",
+		},
+		{
+			name:       "wrapped error",
+			err:        Errorf(http.StatusBadRequest, "failed to patch row: %w", errors.New("UNIQUE constraint failed")),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "failed to patch row\n",
+		},
+		{
+			name:       "htmx",
+			err:        Errorf(http.StatusServiceUnavailable, "server is shutting down"),
+			htmx:       true,
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "Try again",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+				return tt.err
+			})
+			req := httptest.NewRequest("POST", "/", nil)
+			if tt.htmx {
+				req.Header.Set("HX-Request", "true")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if body := w.Body.String(); !strings.Contains(body, tt.wantBody) || (tt.wantBody == "" && body != "") {
+				t.Errorf("expected body %q, got %q", tt.wantBody, body)
+			}
+			var appErr *Error
+			if retryable := errors.As(tt.err, &appErr) && appErr.Retryable; retryable != (w.Header().Get("Retry-After") != "") {
+				t.Errorf("expected Retry-After only for retryable errors, got %q", w.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
@@ -1,57 +1,59 @@
 package mux
 
 import (
-	"context"
-	"fmt"
+	"bytes"
 	"net/http"
-	"strings"
 
-	"github.com/RyRose/uplog/internal/templates"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
+// Trace registers handlers that record a span named after their route pattern
+// for each request.
 type Trace struct {
 	Mux *http.ServeMux
 }
 
 func (t *Trace) Handle(pattern string, handler http.Handler) {
-	t.Mux.Handle(pattern, handler)
-	// TODO: Handle tracing here.
-	// t.Mux.Handle(pattern, otelhttp.NewHandler(handler, pattern))
+	t.Mux.Handle(pattern, otelhttp.NewHandler(handler, pattern))
 }
 
 func (t *Trace) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
 	t.Handle(pattern, http.HandlerFunc(handler))
 }
 
+// Web registers handlers of htmx requests on Mux. Their errors are written by
+// WriteError, whether they return them as a HandlerFunc or write them.
 type Web struct {
 	Mux *Trace
 }
 
+// errorResponseWriter writes the errors of handlers writing them themselves,
+// as by http.Error, with WriteError.
 type errorResponseWriter struct {
 	http.ResponseWriter
-	ctx        context.Context
+	r          *http.Request
 	statusCode int
 }
 
+// passThrough reports whether responses with statusCode are written as is
+// rather than as an error. Conflicts render the conflicting state to resolve,
+// and invalid fields render the form with its problems to fix.
+func passThrough(statusCode int) bool {
+	return statusCode < 400 || statusCode == http.StatusConflict || statusCode == http.StatusUnprocessableEntity
+}
+
 func (w *errorResponseWriter) Write(b []byte) (int, error) {
-	if w.statusCode < 400 {
+	if passThrough(w.statusCode) {
 		return w.ResponseWriter.Write(b)
 	}
-
-	w.ResponseWriter.WriteHeader(http.StatusUnprocessableEntity)
-	var s strings.Builder
-	_, err := fmt.Fprint(&s, w.statusCode, ": ")
-	if err != nil {
-		return 0, fmt.Errorf("failed to write status code: %w", err)
-	}
-	n, _ := s.Write(b)
-	return n, templates.Alert(s.String()).Render(w.ctx, w.ResponseWriter)
+	WriteError(w.ResponseWriter, w.r, &Error{Code: w.statusCode, Message: string(bytes.TrimRight(b, "\n"))})
+	return len(b), nil
 }
 
 func (w *errorResponseWriter) WriteHeader(statusCode int) {
 	w.statusCode = statusCode
 
-	if statusCode < 400 {
+	if passThrough(statusCode) {
 		w.ResponseWriter.WriteHeader(statusCode)
 	}
 }
@@ -59,7 +61,7 @@ func (w *errorResponseWriter) WriteHeader(statusCode int) {
 func (w *Web) Handle(pattern string, handler http.Handler) {
 	w.Mux.Handle(pattern,
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			handler.ServeHTTP(&errorResponseWriter{ResponseWriter: w, ctx: r.Context()}, r)
+			handler.ServeHTTP(&errorResponseWriter{ResponseWriter: w, r: r}, r)
 		}))
 }
 
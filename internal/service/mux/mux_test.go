@@ -1,18 +1,21 @@
 package mux
 
 import (
-	"context"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestErrorResponseWriter_WriteHeader_Success(t *testing.T) {
 	w := httptest.NewRecorder()
 	ew := &errorResponseWriter{
 		ResponseWriter: w,
-		ctx:            context.Background(),
+		r:              httptest.NewRequest("GET", "/", nil),
 		statusCode:     0,
 	}
 
@@ -27,7 +30,7 @@ func TestErrorResponseWriter_WriteHeader_Error(t *testing.T) {
 	w := httptest.NewRecorder()
 	ew := &errorResponseWriter{
 		ResponseWriter: w,
-		ctx:            context.Background(),
+		r:              httptest.NewRequest("GET", "/", nil),
 		statusCode:     0,
 	}
 
@@ -43,7 +46,7 @@ func TestErrorResponseWriter_Write_Success(t *testing.T) {
 	w := httptest.NewRecorder()
 	ew := &errorResponseWriter{
 		ResponseWriter: w,
-		ctx:            context.Background(),
+		r:              httptest.NewRequest("GET", "/", nil),
 		statusCode:     http.StatusOK,
 	}
 
@@ -127,14 +130,94 @@ func TestWeb_HandleError(t *testing.T) {
 
 	mux.ServeHTTP(w, req)
 
-	// Should transform to UnprocessableEntity
+	// Should keep the status for clients other than htmx
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	body := w.Body.String()
+	if body != "error message\n" {
+		t.Errorf("expected body %q, got %q", "error message\n", body)
+	}
+}
+
+func TestWeb_HandleError_Htmx(t *testing.T) {
+	mux := &http.ServeMux{}
+	web := &Web{Mux: &Trace{Mux: mux}}
+
+	web.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "error message", http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest("GET", "/error", nil)
+	req.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+	// Should render an alert into #alerts rather than the target
+	if got := w.Header().Get("HX-Retarget"); got != "#alerts" {
+		t.Errorf("expected HX-Retarget #alerts, got %q", got)
+	}
+	if got := w.Header().Get("HX-Reswap"); got != "beforeend" {
+		t.Errorf("expected HX-Reswap beforeend, got %q", got)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `role="alert"`) || !strings.Contains(body, "error message") {
+		t.Errorf("expected body to be an alert with the message, got %q", body)
+	}
+}
+
+func TestWeb_HandleConflict(t *testing.T) {
+	mux := &http.ServeMux{}
+	web := &Web{Mux: &Trace{Mux: mux}}
+
+	web.HandleFunc("/conflict", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte("<tr></tr>"))
+	})
+
+	req := httptest.NewRequest("PATCH", "/conflict", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	// Conflicts are passed through to render them
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+
+	body := w.Body.String()
+	if body != "<tr></tr>" {
+		t.Errorf("expected body %q, got %q", "<tr></tr>", body)
+	}
+}
+
+func TestWeb_HandleInvalid(t *testing.T) {
+	mux := &http.ServeMux{}
+	web := &Web{Mux: &Trace{Mux: mux}}
+
+	web.HandleFunc("/invalid", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte("<tr></tr>"))
+	})
+
+	req := httptest.NewRequest("PATCH", "/invalid", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	// Invalid fields are passed through to render them
 	if w.Code != http.StatusUnprocessableEntity {
 		t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
 	}
 
 	body := w.Body.String()
-	if !strings.Contains(body, "400") {
-		t.Errorf("expected body to contain status code, got %q", body)
+	if body != "<tr></tr>" {
+		t.Errorf("expected body %q, got %q", "<tr></tr>", body)
 	}
 }
 
@@ -142,7 +225,7 @@ func TestErrorResponseWriter_MultipleWrites(t *testing.T) {
 	w := httptest.NewRecorder()
 	ew := &errorResponseWriter{
 		ResponseWriter: w,
-		ctx:            context.Background(),
+		r:              httptest.NewRequest("GET", "/", nil),
 		statusCode:     http.StatusOK,
 	}
 
@@ -154,3 +237,33 @@ func TestErrorResponseWriter_MultipleWrites(t *testing.T) {
 		t.Errorf("expected body %q, got %q", "first second", body)
 	}
 }
+
+func TestWeb_HandleError_TraceID(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	mux := &http.ServeMux{}
+	web := &Web{Mux: &Trace{Mux: mux}}
+
+	var traceID string
+	web.HandleFunc("GET /error", func(w http.ResponseWriter, r *http.Request) {
+		traceID = trace.SpanContextFromContext(r.Context()).TraceID().String()
+		http.Error(w, "error message", http.StatusBadRequest)
+	})
+
+	req := httptest.NewRequest("GET", "/error", nil)
+	req.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if traceID == "" || traceID == (trace.TraceID{}).String() {
+		t.Fatalf("expected handler to run within a span, got trace ID %q", traceID)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "trace "+traceID) {
+		t.Errorf("expected body to contain trace ID %s, got %q", traceID, body)
+	}
+}
@@ -0,0 +1,100 @@
+package rawdata
+
+import (
+	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/service/rawdata/base"
+)
+
+// HandleListAPI godoc
+//
+//	@Summary		List data table rows
+//	@Description	Lists the rows of a data table as objects keyed by column
+//	@Tags			api
+//	@Produce		json
+//	@Param			table	path		string	true	"Data table"	Enums(lift, movement, muscle, routine, workout, template_variable, lift_group, side_weight, progress, subworkout, routine_workout_mapping, lift_muscle_mapping, lift_workout_mapping)
+//	@Param			q		query		string	false	"Free text search"
+//	@Param			sort	query		string	false	"Column to sort by, prefixed with - to reverse"
+//	@Param			limit	query		integer	false	"Page size"
+//	@Param			offset	query		integer	false	"Pagination offset"
+//	@Success		200		{array}		object	"Rows"
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/api/data/{table} [get]
+func HandleListAPI(e base.Entity, _ *config.Data, state *config.State) mux.HandlerFunc {
+	return base.HandleListAPI(e, dataTable(state, e.Table))
+}
+
+// HandleGetAPI godoc
+//
+//	@Summary		Get data table row
+//	@Description	Gets a row of a data table with its version as the ETag. The row is identified by one path segment per primary key column
+//	@Tags			api
+//	@Produce		json
+//	@Param			table	path		string	true	"Data table"	Enums(lift, movement, muscle, routine, workout, template_variable, lift_group, side_weight, progress, subworkout, routine_workout_mapping, lift_muscle_mapping, lift_workout_mapping)
+//	@Param			key		path		string	true	"Primary key of the row"
+//	@Success		200		{object}	object	"Row"
+//	@Failure		404		{string}	string	"Row not found"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/api/data/{table}/{key} [get]
+func HandleGetAPI(e base.Entity, _ *config.Data, state *config.State) mux.HandlerFunc {
+	return base.HandleGetAPI(e, dataTable(state, e.Table))
+}
+
+// HandlePostAPI godoc
+//
+//	@Summary		Create data table row
+//	@Description	Creates a row of a data table from an object keyed by column. Generated IDs are left out
+//	@Tags			api
+//	@Accept			json
+//	@Produce		json
+//	@Param			table	path		string	true	"Data table"	Enums(lift, movement, muscle, routine, workout, template_variable, lift_group, side_weight, progress, subworkout, routine_workout_mapping, lift_muscle_mapping, lift_workout_mapping)
+//	@Param			row		body		object	true	"Row"
+//	@Success		201		{object}	object	"Row"
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		422		{object}	object	"Problems with the fields"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/api/data/{table} [post]
+func HandlePostAPI(e base.Entity, _ *config.Data, state *config.State) mux.HandlerFunc {
+	return base.HandlePostAPI(e, dataTable(state, e.Table))
+}
+
+// HandlePatchAPI godoc
+//
+//	@Summary		Update data table row
+//	@Description	Updates the given fields of a row of a data table in one transaction
+//	@Tags			api
+//	@Accept			json
+//	@Produce		json
+//	@Param			table		path		string	true	"Data table"	Enums(lift, movement, muscle, routine, workout, template_variable, lift_group, side_weight, progress, subworkout, routine_workout_mapping, lift_muscle_mapping, lift_workout_mapping)
+//	@Param			key			path		string	true	"Primary key of the row"
+//	@Param			fields		body		object	true	"Fields to update"
+//	@Param			If-Match	header		string	false	"Version of the row the patch is based on"
+//	@Success		200			{object}	object	"Updated row"
+//	@Failure		400			{string}	string	"Bad request"
+//	@Failure		404			{string}	string	"Row not found"
+//	@Failure		409			{object}	object	"Stored row if it changed since that version"
+//	@Failure		422			{object}	object	"Problems with the fields"
+//	@Failure		500			{string}	string	"Internal server error"
+//	@Router			/api/data/{table}/{key} [patch]
+func HandlePatchAPI(e base.Entity, _ *config.Data, state *config.State) mux.HandlerFunc {
+	return base.HandlePatchAPI(e, dataTable(state, e.Table))
+}
+
+// HandleDeleteAPI godoc
+//
+//	@Summary		Delete data table row
+//	@Description	Deletes a row of a data table. Rows with a text ID are moved to the trash unless other rows depend on them and cascade is not set
+//	@Tags			api
+//	@Produce		json
+//	@Param			table	path		string	true	"Data table"	Enums(lift, movement, muscle, routine, workout, template_variable, lift_group, side_weight, progress, subworkout, routine_workout_mapping, lift_muscle_mapping, lift_workout_mapping)
+//	@Param			key		path		string	true	"Primary key of the row"
+//	@Param			cascade	query		bool	false	"Also remove the rows depending on it"
+//	@Success		204		{string}	string	"Deleted"
+//	@Failure		404		{string}	string	"Not found"
+//	@Failure		409		{array}		object	"Rows a cascading delete would remove"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/api/data/{table}/{key} [delete]
+func HandleDeleteAPI(e base.Entity, _ *config.Data, state *config.State) mux.HandlerFunc {
+	return base.HandleDeleteAPI(state.Trash, e, dataTable(state, e.Table))
+}
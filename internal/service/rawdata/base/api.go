@@ -0,0 +1,210 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/RyRose/uplog/internal/audit"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/trash"
+	"github.com/RyRose/uplog/internal/validate"
+)
+
+// HandleListAPI writes the rows of e selected by the query of the request, as
+// parsed by ParseQuery, as a JSON array of objects keyed by column.
+func HandleListAPI(e Entity, table Table) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		q, err := ParseQuery(r.URL.Query(), e.Names())
+		if err != nil {
+			return mux.Errorf(http.StatusBadRequest, "failed to parse query: %w", err)
+		}
+		rows, err := selectRows(ctx, table.ReadDB, table.Search, table.Name, e.Names(), q)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to select data: %w", err)
+		}
+		out := make([]json.RawMessage, len(rows))
+		for i, row := range rows {
+			out[i] = json.RawMessage(row)
+		}
+		writeJSON(ctx, w, http.StatusOK, out)
+		return nil
+	}
+}
+
+// HandleGetAPI writes the row of e identified by its path values as a JSON
+// object, with its version as the ETag.
+func HandleGetAPI(e Entity, table Table) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		row, err := audit.Snapshot(ctx, table.ReadDB, table.Name, e.PathKey(r))
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to select row: %w", err)
+		}
+		if row == "" {
+			return mux.Errorf(http.StatusNotFound, "row not found")
+		}
+		writeRow(ctx, w, http.StatusOK, row)
+		return nil
+	}
+}
+
+// HandlePostAPI inserts a row of e with the fields of the JSON object of the
+// request and writes it.
+func HandlePostAPI(e Entity, table Table) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		fields, err := jsonFields(r)
+		if writeInvalid(ctx, w, err) {
+			return nil
+		}
+		if err != nil {
+			return mux.Errorf(http.StatusBadRequest, "failed to decode row: %w", err)
+		}
+		insert, err := e.Insert(fields)
+		if writeInvalid(ctx, w, err) {
+			return nil
+		}
+		after, err := table.Insert(r, insert)
+		if writeInvalid(ctx, w, err) {
+			return nil
+		}
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to insert data: %w", err)
+		}
+		writeRow(ctx, w, http.StatusCreated, after[0])
+		return nil
+	}
+}
+
+// HandlePatchAPI sets the fields of the JSON object of the request for the row
+// of e identified by its path values in one transaction and writes the row as
+// stored. If the row changed since the version given by IfMatch, nothing is
+// set and the stored row is written with a 409 status.
+func HandlePatchAPI(e Entity, table Table) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		fields, err := jsonFields(r)
+		if writeInvalid(ctx, w, err) {
+			return nil
+		}
+		if err != nil {
+			return mux.Errorf(http.StatusBadRequest, "failed to decode row: %w", err)
+		}
+		update, err := e.Update(e.PathKey(r), fields)
+		if writeInvalid(ctx, w, err) {
+			return nil
+		}
+		if err != nil {
+			return mux.Errorf(http.StatusBadRequest, "failed to patch row: %w", err)
+		}
+		after, err := table.Update(r, update)
+		var (
+			conflict *ConflictError
+			invalid  validate.Errors
+		)
+		switch {
+		case errors.As(err, &invalid):
+			writeInvalid(ctx, w, invalid)
+		case errors.As(err, &conflict):
+			slog.WarnContext(ctx, "failed to patch row", "error", err, "path", r.URL.Path)
+			writeRow(ctx, w, http.StatusConflict, conflict.Row)
+		case errors.Is(err, ErrNotFound):
+			return mux.Errorf(http.StatusNotFound, "failed to patch row: %w", err)
+		case err != nil:
+			return mux.Errorf(http.StatusInternalServerError, "failed to patch row: %w", err)
+		default:
+			writeRow(ctx, w, http.StatusOK, after)
+		}
+		return nil
+	}
+}
+
+// HandleDeleteAPI deletes the row of e identified by its path values like
+// HandleDeleteTableRowView. If other rows block the delete, the changes a
+// cascading delete would make are written with a 409 status.
+func HandleDeleteAPI(bin *trash.Bin, e Entity, table Table) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		var (
+			blocked *trash.BlockedError
+			err     error
+		)
+		if e.Renamable() {
+			blocked, err = trashRow(r, bin, e, table)
+		} else {
+			err = table.Delete(r, e.Delete(e.PathKey(r)))
+		}
+		switch {
+		case blocked != nil:
+			writeJSON(ctx, w, http.StatusConflict, blocked.Changes)
+		case errors.Is(err, trash.ErrNotFound), errors.Is(err, ErrNotFound):
+			return mux.Errorf(http.StatusNotFound, "failed to delete row: %w", err)
+		case err != nil:
+			return mux.Errorf(http.StatusInternalServerError, "failed to delete row: %w", err)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return nil
+	}
+}
+
+// jsonFields returns the fields of the JSON object of the request. Null
+// fields are nil, and numbers are kept as written. Fields of other types are
+// reported as validate.Errors.
+func jsonFields(r *http.Request) (map[string]*string, error) {
+	var object map[string]any
+	d := json.NewDecoder(r.Body)
+	d.UseNumber()
+	if err := d.Decode(&object); err != nil {
+		return nil, err
+	}
+	var errs validate.Errors
+	fields := make(map[string]*string, len(object))
+	for _, name := range slices.Sorted(maps.Keys(object)) {
+		v := object[name]
+		switch v := v.(type) {
+		case nil:
+			fields[name] = nil
+		case string:
+			fields[name] = &v
+		case json.Number:
+			s := v.String()
+			fields[name] = &s
+		default:
+			errs.Add(name, errors.New("must be a string, number or null"))
+		}
+	}
+	return fields, errs.Err()
+}
+
+// writeInvalid writes the problems with the fields of the request with a 422
+// status if err reports them as validate.Errors. It reports whether it did.
+func writeInvalid(ctx context.Context, w http.ResponseWriter, err error) bool {
+	var invalid validate.Errors
+	if !errors.As(err, &invalid) {
+		return false
+	}
+	slog.WarnContext(ctx, "invalid fields", "error", err)
+	writeJSON(ctx, w, http.StatusUnprocessableEntity, map[string]validate.Errors{"errors": invalid})
+	return true
+}
+
+// writeRow writes row as a JSON object with its version as the ETag.
+func writeRow(ctx context.Context, w http.ResponseWriter, status int, row audit.Row) {
+	w.Header().Set("ETag", ETag(row.Version()))
+	writeJSON(ctx, w, status, json.RawMessage(row))
+}
+
+func writeJSON(ctx context.Context, w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.WarnContext(ctx, "failed to write response", "error", err)
+	}
+}
@@ -1,6 +0,0 @@
-package base
-
-type TableViewMetadata struct {
-	Headers []string
-	Post    string
-}
@@ -1,51 +1,71 @@
 package base
 
 import (
-	"context"
-	"database/sql"
-	"fmt"
+	"errors"
 	"log/slog"
 	"net/http"
 
-	"github.com/RyRose/uplog/internal/sqlc/workoutdb"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/templates"
+	"github.com/RyRose/uplog/internal/trash"
 )
 
-func HandleDeleteTableRowViewID(
-	wDB *sql.DB,
-	deleteQ func(*workoutdb.Queries, context.Context, string) error,
-) http.HandlerFunc {
-	queries := workoutdb.New(wDB)
-	return func(w http.ResponseWriter, r *http.Request) {
+// HandleDeleteTableRowView deletes the row of e identified by its path
+// values. Rows of renamable entities are moved to the trash. If other rows
+// depend on them, nothing is deleted unless the "cascade" query value is true,
+// and a dialog listing them is rendered instead so the delete can be
+// confirmed.
+func HandleDeleteTableRowView(bin *trash.Bin, e Entity, table Table) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
-		id := r.PathValue("id")
-		if err := deleteQ(queries, ctx, id); err != nil {
-			http.Error(w, fmt.Sprintf("failed to delete row: %v", err), http.StatusInternalServerError)
-			slog.ErrorContext(ctx, "failed to delete row", "error", err)
-			return
+		if !e.Renamable() {
+			err := table.Delete(r, e.Delete(e.PathKey(r)))
+			if errors.Is(err, ErrNotFound) {
+				return mux.Errorf(http.StatusNotFound, "failed to delete row: %w", err)
+			}
+			if err != nil {
+				return mux.Errorf(http.StatusInternalServerError, "failed to delete row: %w", err)
+			}
+			w.WriteHeader(http.StatusOK)
+			return nil
+		}
+		blocked, err := trashRow(r, bin, e, table)
+		switch {
+		case blocked != nil:
+			dialog := templates.DeleteDialog{
+				Table:          table.Name,
+				Key:            r.PathValue(e.Keys()[0].Name),
+				DeleteEndpoint: r.URL.Path + "?cascade=true",
+				Changes:        blocked.Changes,
+			}
+			w.Header().Set("HX-Retarget", "body")
+			w.Header().Set("HX-Reswap", "beforeend")
+			if err := templates.DeleteDialogView(dialog).Render(ctx, w); err != nil {
+				slog.WarnContext(ctx, "failed to render delete dialog", "error", err)
+			}
+			return nil
+		case errors.Is(err, trash.ErrNotFound):
+			return mux.Errorf(http.StatusNotFound, "failed to delete row: %w", err)
+		case err != nil:
+			return mux.Errorf(http.StatusInternalServerError, "failed to delete row: %w", err)
 		}
 		w.WriteHeader(http.StatusOK)
+		return nil
 	}
 }
 
-func HandleDeleteTableRowViewRequest[idType any](
-	wDB *sql.DB,
-	deleteQ func(*workoutdb.Queries, context.Context, idType) error,
-	convert func(*http.Request) (*idType, error),
-) http.HandlerFunc {
-	queries := workoutdb.New(wDB)
-	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-		id, err := convert(r)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("failed to convert id: %v", err), http.StatusBadRequest)
-			slog.ErrorContext(ctx, "failed to convert id", "error", err)
-			return
-		}
-		if err := deleteQ(queries, ctx, *id); err != nil {
-			http.Error(w, fmt.Sprintf("failed to delete row: %v", err), http.StatusInternalServerError)
-			slog.ErrorContext(ctx, "failed to delete row", "error", err)
-			return
-		}
-		w.WriteHeader(http.StatusOK)
+// trashRow moves the row of the renamable entity e identified by the path of
+// r to the trash, cascading to the rows depending on it if the "cascade" query
+// value is true. Each removed row is audited. It returns the error listing
+// them if they block the delete.
+func trashRow(r *http.Request, bin *trash.Bin, e Entity, table Table) (*trash.BlockedError, error) {
+	name := e.Keys()[0].Name
+	id := r.PathValue(name)
+	cascade := r.URL.Query().Get("cascade") == "true"
+	_, err := bin.Delete(r.Context(), table.Name, name, id, cascade, table.Audit.Observer(table.Audit.FromRequest(r)))
+	var blocked *trash.BlockedError
+	if errors.As(err, &blocked) {
+		return blocked, err
 	}
+	return nil, err
 }
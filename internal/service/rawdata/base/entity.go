@@ -0,0 +1,494 @@
+package base
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/RyRose/uplog/internal/audit"
+	"github.com/RyRose/uplog/internal/schema"
+	"github.com/RyRose/uplog/internal/service/rawdata/util"
+	"github.com/RyRose/uplog/internal/templates"
+	"github.com/RyRose/uplog/internal/validate"
+)
+
+// Kind is the type of the values of a column.
+type Kind int
+
+const (
+	Text Kind = iota
+	Integer
+	Real
+)
+
+// Input is how the values of a column are entered in the data table. Auto
+// infers it from the other fields of the column.
+type Input int
+
+const (
+	Auto Input = iota
+	// Date is a date picker for values like 2006-01-02.
+	Date
+	// DateTime is a date and time picker for values like 2006-01-02T15:04.
+	DateTime
+	// Checkbox is a checkbox for Integer values that are 1 if it is checked
+	// and 0 otherwise.
+	Checkbox
+	// URL is a URL input linking to its value.
+	URL
+)
+
+// Column is a column of an Entity. Its form field and JSON field are named
+// after it.
+type Column struct {
+	Name string
+	// Header is the title of the column in the data table.
+	Header string
+	Kind   Kind
+	// Key is whether the column is part of the primary key.
+	Key bool
+	// Null is whether the column may be NULL. Empty values are stored as NULL.
+	Null bool
+	// Source is the table whose primary key values the column references.
+	// They are the choices of the column.
+	Source string
+	// Long is whether values span several lines.
+	Long bool
+	// Input is how values are entered, if not inferred.
+	Input Input
+	// Multiple is whether several values can be chosen for a new row of a
+	// column with a Source, adding a row for each. It suits the keys of
+	// mapping tables.
+	Multiple bool
+	// Check checks values other than NULL, as by the functions of package
+	// validate.
+	Check func(string) error
+}
+
+// Entity is a data table declared once. Its routes, tab, handlers and
+// validation all follow from the declaration.
+type Entity struct {
+	Table string
+	// Title is the title of the tab of the data table.
+	Title string
+	// Group is the row of tabs the tab of the data table is in.
+	Group   int
+	Columns []Column
+}
+
+// Endpoint returns the endpoint of the data table view of e.
+func (e Entity) Endpoint() string {
+	return "/view/data/" + e.Table
+}
+
+// APIEndpoint returns the endpoint of the JSON API of e.
+func (e Entity) APIEndpoint() string {
+	return "/api/data/" + e.Table
+}
+
+// RowPattern returns the pattern of the paths below endpoint identifying a row
+// of e. Path values are named after the primary key columns they hold.
+func (e Entity) RowPattern(endpoint string) string {
+	pattern := endpoint
+	for _, c := range e.Keys() {
+		pattern += "/{" + c.Name + "}"
+	}
+	return pattern
+}
+
+// Keys returns the primary key columns of e.
+func (e Entity) Keys() []Column {
+	var keys []Column
+	for _, c := range e.Columns {
+		if c.Key {
+			keys = append(keys, c)
+		}
+	}
+	return keys
+}
+
+// Names returns the names of the columns of e in order.
+func (e Entity) Names() []string {
+	names := make([]string, len(e.Columns))
+	for i, c := range e.Columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Headers returns the headers of the columns of e in order.
+func (e Entity) Headers() []string {
+	headers := make([]string, len(e.Columns))
+	for i, c := range e.Columns {
+		headers[i] = c.Header
+	}
+	return headers
+}
+
+// Column returns the column of e named name.
+func (e Entity) Column(name string) (Column, bool) {
+	i := slices.IndexFunc(e.Columns, func(c Column) bool { return c.Name == name })
+	if i < 0 {
+		return Column{}, false
+	}
+	return e.Columns[i], true
+}
+
+// Renamable returns whether rows of e are identified by a single text key.
+// Such rows are renamed after confirming the rows referencing them, and
+// deleted to the trash.
+func (e Entity) Renamable() bool {
+	keys := e.Keys()
+	return len(keys) == 1 && keys[0].Kind == Text
+}
+
+// Generated returns whether the key of e is a row ID generated on insert.
+func (e Entity) Generated() bool {
+	keys := e.Keys()
+	return len(keys) == 1 && keys[0].Kind == Integer
+}
+
+// PathKey returns the primary key of the row of e identified by the path of r.
+func (e Entity) PathKey(r *http.Request) map[string]string {
+	key := make(map[string]string)
+	for _, c := range e.Keys() {
+		key[c.Name] = r.PathValue(c.Name)
+	}
+	return key
+}
+
+// RowPath returns the path below endpoint identifying the row with values.
+func (e Entity) RowPath(endpoint string, values map[string]*string) string {
+	var parts []string
+	for _, c := range e.Keys() {
+		parts = append(parts, util.Zero(values[c.Name]))
+	}
+	return util.UrlPathJoin(endpoint, parts...)
+}
+
+// bind returns the SQL value of the value of c, where nil means no value.
+// Empty values of columns that may be NULL are NULL. Keys, references and
+// numbers need a value otherwise.
+func bind(c Column, value *string) (any, error) {
+	v := util.Zero(value)
+	if c.Kind != Text {
+		v = strings.TrimSpace(v)
+	}
+	// Unchecked checkboxes are not submitted.
+	if c.Input == Checkbox && v == "" {
+		v = "0"
+	}
+	if v == "" {
+		switch {
+		case c.Null:
+			return nil, nil
+		case c.Key || c.Source != "" || c.Kind != Text:
+			return nil, errors.New("value is required")
+		}
+	}
+	var out any = v
+	switch c.Kind {
+	case Integer:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, errors.New("must be a whole number")
+		}
+		if c.Input == Checkbox && n != 0 && n != 1 {
+			return nil, errors.New("must be 0 or 1")
+		}
+		out = n
+	case Real:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, errors.New("must be a number")
+		}
+		out = f
+	}
+	if c.Check != nil {
+		if err := c.Check(v); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// Write is a validated change of a row of an entity, made by Exec in a
+// transaction.
+type Write struct {
+	query string
+	args  []any
+	// refs are the values referencing other tables, which must exist.
+	refs []validate.Ref
+	// Fields are the fields the change sets, with NULL as "".
+	Fields map[string]string
+}
+
+// Exec makes the change in tx. It returns validate.Errors if values reference
+// rows that do not exist.
+func (w Write) Exec(ctx context.Context, tx *sql.Tx) error {
+	errs, err := validate.References(ctx, tx, w.refs)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	if _, err := tx.ExecContext(ctx, w.query, w.args...); err != nil {
+		return fmt.Errorf("failed to write row: %w", err)
+	}
+	return nil
+}
+
+// Insert validates fields and returns the insert of a row of e with them. The
+// values of columns without a field are empty, and fields that are not
+// columns are ignored. Generated keys are left out. Invalid values are
+// reported as validate.Errors.
+func (e Entity) Insert(fields map[string]*string) (Write, error) {
+	var (
+		names, marks []string
+		errs         validate.Errors
+		w            = Write{Fields: make(map[string]string)}
+	)
+	for _, c := range e.Columns {
+		if c.Key && e.Generated() {
+			continue
+		}
+		v, err := bind(c, fields[c.Name])
+		errs.Add(c.Name, err)
+		names = append(names, schema.Quote(c.Name))
+		marks = append(marks, "?")
+		w.bind(c, v, fields[c.Name])
+	}
+	if len(errs) > 0 {
+		return Write{}, errs
+	}
+	w.query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		schema.Quote(e.Table), strings.Join(names, ", "), strings.Join(marks, ", "))
+	return w, nil
+}
+
+// Update validates fields and returns the update of the row of e with key
+// setting them. Every field must be a column, and generated keys cannot be
+// set. Invalid values are reported as validate.Errors.
+func (e Entity) Update(key map[string]string, fields map[string]*string) (Write, error) {
+	if len(fields) == 0 {
+		return Write{}, errors.New("no fields to update")
+	}
+	var unknown []string
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		if c, ok := e.Column(name); !ok || (c.Key && e.Generated()) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return Write{}, fmt.Errorf("unknown fields %v", unknown)
+	}
+	var (
+		sets, where []string
+		errs        validate.Errors
+		w           = Write{Fields: make(map[string]string)}
+	)
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		c, _ := e.Column(name)
+		v, err := bind(c, fields[name])
+		errs.Add(name, err)
+		sets = append(sets, schema.Quote(name)+" = ?")
+		w.bind(c, v, fields[name])
+	}
+	if len(errs) > 0 {
+		return Write{}, errs
+	}
+	for _, c := range e.Keys() {
+		where = append(where, schema.Quote(c.Name)+" = ?")
+		w.args = append(w.args, key[c.Name])
+	}
+	w.query = fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+		schema.Quote(e.Table), strings.Join(sets, ", "), strings.Join(where, " AND "))
+	return w, nil
+}
+
+// bind adds the value v of c bound from value to the arguments of w.
+func (w *Write) bind(c Column, v any, value *string) {
+	w.args = append(w.args, v)
+	w.Fields[c.Name] = util.Zero(value)
+	if s, ok := v.(string); ok && c.Source != "" {
+		w.refs = append(w.refs, validate.Ref{Field: c.Name, Table: c.Source, Value: s})
+	}
+}
+
+// Delete returns the delete of the row of e with key.
+func (e Entity) Delete(key map[string]string) Write {
+	var (
+		where []string
+		w     = Write{Fields: make(map[string]string)}
+	)
+	for _, c := range e.Keys() {
+		where = append(where, schema.Quote(c.Name)+" = ?")
+		w.args = append(w.args, key[c.Name])
+	}
+	w.query = fmt.Sprintf("DELETE FROM %s WHERE %s", schema.Quote(e.Table), strings.Join(where, " AND "))
+	return w
+}
+
+// Options returns the choices of the columns of e that can have several values
+// for a new row, keyed by column. Other references are looked up as they are
+// typed with Suggest, since there may be too many rows to list.
+func (e Entity) Options(ctx context.Context, db schema.Querier) (map[string][]string, error) {
+	options := make(map[string][]string)
+	for _, c := range e.Columns {
+		if c.Source == "" || !c.Multiple {
+			continue
+		}
+		values, err := c.sourceKeys(ctx, db, "SELECT %[1]s FROM %[2]s ORDER BY 1")
+		if err != nil {
+			return nil, err
+		}
+		options[c.Name] = values
+	}
+	return options, nil
+}
+
+// Suggest returns up to limit values of the table referenced by c containing
+// text, those starting with it first.
+func (c Column) Suggest(ctx context.Context, db schema.Querier, text string, limit int) ([]string, error) {
+	if c.Source == "" {
+		return nil, fmt.Errorf("%s references no table", c.Name)
+	}
+	pattern := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(text)
+	return c.sourceKeys(ctx, db, `SELECT %[1]s FROM %[2]s WHERE %[1]s LIKE '%%' || ?1 || '%%' ESCAPE '\'
+ORDER BY %[1]s NOT LIKE ?1 || '%%' ESCAPE '\', 1 LIMIT ?2`, pattern, limit)
+}
+
+// sourceKeys returns the primary key values of the table referenced by c
+// selected by query, formatted with the quoted key column and table.
+func (c Column) sourceKeys(ctx context.Context, db schema.Querier, query string, args ...any) ([]string, error) {
+	_, primaryKey, err := schema.Columns(ctx, db, c.Source)
+	if err != nil {
+		return nil, err
+	}
+	if len(primaryKey) != 1 {
+		return nil, fmt.Errorf("%s has no single column primary key to choose from", c.Source)
+	}
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(query, schema.Quote(primaryKey[0]), schema.Quote(c.Source)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", c.Source, err)
+	}
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to scan %s: %w", c.Source, err), rows.Close())
+		}
+		values = append(values, v)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", c.Source, err)
+	}
+	return values, nil
+}
+
+// Values returns the values of the columns of the stored row as shown in the
+// data table, keyed by column. NULL values are nil.
+func (e Entity) Values(row audit.Row) (map[string]*string, error) {
+	values, err := row.Values()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range e.Columns {
+		v := values[c.Name]
+		if c.Kind != Real || v == nil {
+			continue
+		}
+		f, err := strconv.ParseFloat(*v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", c.Name, err)
+		}
+		s := fmt.Sprint(f)
+		values[c.Name] = &s
+	}
+	return values, nil
+}
+
+// Row returns the data table row of the stored row.
+func (e Entity) Row(row audit.Row) (*templates.DataTableRow, error) {
+	values, err := e.Values(row)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := e.RowPath(e.Endpoint(), values)
+	out := &templates.DataTableRow{
+		PatchEndpoint:  endpoint,
+		DeleteEndpoint: endpoint,
+		Version:        row.Version(),
+	}
+	if e.Renamable() {
+		out.RenameEndpoint = util.UrlPathJoin(endpoint, "rename")
+	}
+	for _, c := range e.Columns {
+		value := e.value(c)
+		if c.Key && e.Renamable() {
+			value.Type = templates.InputKey
+		}
+		value.Value = util.Zero(values[c.Name])
+		out.Values = append(out.Values, value)
+	}
+	return out, nil
+}
+
+// Footer returns the footer of the data table of e, which posts new rows.
+func (e Entity) Footer() templates.DataTableFooter {
+	return templates.DataTableFooter{
+		PostEndpoint: e.Endpoint(),
+		FormID:       "datatableform",
+	}
+}
+
+// OptionsEndpoint returns the endpoint suggesting values of the column of e
+// named column as they are typed.
+func (e Entity) OptionsEndpoint(column string) string {
+	return "/view/options/" + e.Table + "/" + column
+}
+
+// NewRow returns the data table row for the values of a new row of e, with
+// the options of the columns that can have several values.
+func (e Entity) NewRow(options map[string][]string) templates.DataTableRow {
+	var row templates.DataTableRow
+	for _, c := range e.Columns {
+		value := e.value(c)
+		if c.Source != "" && c.Multiple {
+			value.Type, value.SelectOptions = templates.SelectMultiple, options[c.Name]
+		}
+		row.Values = append(row.Values, value)
+	}
+	return row
+}
+
+// value returns the empty value of the column c of e.
+func (e Entity) value(c Column) templates.DataTableValue {
+	value := templates.DataTableValue{Name: c.Name, Type: templates.InputString}
+	switch {
+	case c.Key && e.Generated():
+		value.Type = templates.Static
+	case c.Source != "":
+		value.Type, value.OptionsEndpoint = templates.InputReference, e.OptionsEndpoint(c.Name)
+	case c.Long:
+		value.Type = templates.TextArea
+	case c.Input == Date:
+		value.Type = templates.InputDate
+	case c.Input == DateTime:
+		value.Type = templates.InputDateTime
+	case c.Input == Checkbox:
+		value.Type = templates.Checkbox
+	case c.Input == URL:
+		value.Type = templates.InputURL
+	case c.Kind != Text:
+		value.Type = templates.InputNumber
+	}
+	return value
+}
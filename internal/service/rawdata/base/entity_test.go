@@ -0,0 +1,131 @@
+package base
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/RyRose/uplog/internal/validate"
+)
+
+var progress = Entity{
+	Table: "progress",
+	Columns: []Column{
+		{Name: "id", Kind: Integer, Key: true},
+		{Name: "lift", Source: "lift"},
+		{Name: "weight", Kind: Real},
+		{Name: "sets", Kind: Integer},
+		{Name: "notes"},
+		{Name: "side_weight", Null: true, Source: "side_weight"},
+	},
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestEntity_Insert(t *testing.T) {
+	w, err := progress.Insert(map[string]*string{
+		"id":      ptr("7"),
+		"lift":    ptr("squat"),
+		"weight":  ptr(" 102.5 "),
+		"sets":    ptr("3"),
+		"unknown": ptr("ignored"),
+	})
+	if err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+	wantQuery := `INSERT INTO "progress" ("lift", "weight", "sets", "notes", "side_weight") VALUES (?, ?, ?, ?, ?)`
+	if w.query != wantQuery {
+		t.Errorf("Insert() query = %s, want %s", w.query, wantQuery)
+	}
+	if want := []any{"squat", 102.5, int64(3), "", nil}; !reflect.DeepEqual(w.args, want) {
+		t.Errorf("Insert() args = %#v, want %#v", w.args, want)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		fields map[string]*string
+		field  string
+	}{
+		{"missing reference", map[string]*string{"weight": ptr("1"), "sets": ptr("1")}, "lift"},
+		{"missing number", map[string]*string{"lift": ptr("squat"), "sets": ptr("1")}, "weight"},
+		{"not a number", map[string]*string{"lift": ptr("squat"), "weight": ptr("heavy"), "sets": ptr("1")}, "weight"},
+		{"not a whole number", map[string]*string{"lift": ptr("squat"), "weight": ptr("1"), "sets": ptr("1.5")}, "sets"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := progress.Insert(tc.fields)
+			var errs validate.Errors
+			if !errors.As(err, &errs) || errs.Message(tc.field) == "" {
+				t.Errorf("Insert() error = %v, want an error for %s", err, tc.field)
+			}
+		})
+	}
+}
+
+func TestEntity_Insert_Checkbox(t *testing.T) {
+	e := Entity{
+		Table: "lift",
+		Columns: []Column{
+			{Name: "id", Key: true},
+			{Name: "active", Kind: Integer, Input: Checkbox},
+		},
+	}
+	// Unchecked checkboxes are not submitted.
+	w, err := e.Insert(map[string]*string{"id": ptr("squat")})
+	if err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+	if want := []any{"squat", int64(0)}; !reflect.DeepEqual(w.args, want) {
+		t.Errorf("Insert() args = %#v, want %#v", w.args, want)
+	}
+	_, err = e.Insert(map[string]*string{"id": ptr("squat"), "active": ptr("2")})
+	var errs validate.Errors
+	if !errors.As(err, &errs) || errs.Message("active") == "" {
+		t.Errorf("Insert() error = %v, want an error for active", err)
+	}
+}
+
+func TestEntity_Update(t *testing.T) {
+	mapping := Entity{
+		Table: "lift_workout_mapping",
+		Columns: []Column{
+			{Name: "lift", Key: true, Source: "lift"},
+			{Name: "workout", Key: true, Source: "workout"},
+		},
+	}
+	w, err := mapping.Update(map[string]string{"lift": "squat", "workout": "legs"}, map[string]*string{"workout": ptr("lower")})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	wantQuery := `UPDATE "lift_workout_mapping" SET "workout" = ? WHERE "lift" = ? AND "workout" = ?`
+	if w.query != wantQuery {
+		t.Errorf("Update() query = %s, want %s", w.query, wantQuery)
+	}
+	if want := []any{"lower", "squat", "legs"}; !reflect.DeepEqual(w.args, want) {
+		t.Errorf("Update() args = %#v, want %#v", w.args, want)
+	}
+	if want := map[string]string{"workout": "lower"}; !reflect.DeepEqual(w.Fields, want) {
+		t.Errorf("Update() fields = %v, want %v", w.Fields, want)
+	}
+
+	// Empty values of columns that may be NULL are NULL.
+	w, err = progress.Update(map[string]string{"id": "7"}, map[string]*string{"side_weight": ptr("")})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if want := []any{nil, "7"}; !reflect.DeepEqual(w.args, want) {
+		t.Errorf("Update() args = %#v, want %#v", w.args, want)
+	}
+
+	for _, fields := range []map[string]*string{
+		{},
+		{"id": ptr("8")},
+		{"unknown": ptr("x")},
+		{"lift": nil},
+	} {
+		if _, err := progress.Update(map[string]string{"id": "7"}, fields); err == nil {
+			t.Errorf("Update(%v) error = nil, want an error", fields)
+		}
+	}
+}
@@ -1,76 +1,87 @@
 package base
 
 import (
-	"context"
-	"database/sql"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/url"
-	"strconv"
 
+	"github.com/RyRose/uplog/internal/service/mux"
 	"github.com/RyRose/uplog/internal/service/rawdata/util"
-	"github.com/RyRose/uplog/internal/sqlc/workoutdb"
 	"github.com/RyRose/uplog/internal/templates"
 )
 
-func HandleGetDataTableView[dataType any, limitParams any](
-	roDB *sql.DB,
-	metadata TableViewMetadata,
-	selectQ func(*workoutdb.Queries, context.Context, limitParams) ([]dataType, error),
-	convertQ func(int64, int64) limitParams,
-	convert func(context.Context, *sql.DB, []dataType) ([]templates.DataTableRow, error),
-) http.HandlerFunc {
-	queries := workoutdb.New(roDB)
-	return func(w http.ResponseWriter, r *http.Request) {
+// HandleGetDataTableView renders the page of the data table of e selected by
+// the query of the page URL, as parsed by ParseQuery.
+func HandleGetDataTableView(e Entity, table Table) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
-		var limit, offset int64
-		limit, offset = 50, 0
 		u, err := url.Parse(r.Header.Get("HX-Current-URL"))
 		if err != nil {
-			http.Error(w, fmt.Sprintf("failed to parse current URL: %v", err), http.StatusBadRequest)
-			slog.ErrorContext(ctx, "failed to parse current URL", "error", err, "url", r.Header.Get("HX-Current-URL"))
-			return
+			return mux.Errorf(http.StatusBadRequest, "failed to parse current URL: %w", err)
 		}
-		rawOffset := u.Query().Get("offset")
-		if rawOffset != "" {
-			var err error
-			offset, err = strconv.ParseInt(rawOffset, 10, 64)
-			if err != nil {
-				http.Error(w, fmt.Sprintf("failed to parse offset: %v", err), http.StatusBadRequest)
-				slog.ErrorContext(ctx, "failed to parse offset", "error", err)
-				return
-			}
+		columns := e.Names()
+		q, err := ParseQuery(u.Query(), columns)
+		if err != nil {
+			return mux.Errorf(http.StatusBadRequest, "failed to parse query: %w", err)
 		}
-		rawValues, err := selectQ(queries, ctx, convertQ(limit, offset))
+		values, err := selectRows(ctx, table.ReadDB, table.Search, table.Name, columns, q)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("failed to select data: %v", err), http.StatusInternalServerError)
-			slog.ErrorContext(ctx, "failed to select data", "error", err)
-			return
+			return mux.Errorf(http.StatusInternalServerError, "failed to select data: %w", err)
 		}
-		rows, err := convert(ctx, roDB, rawValues)
+		options, err := e.Options(ctx, table.ReadDB)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("failed to convert data: %v", err), http.StatusInternalServerError)
-			slog.ErrorContext(ctx, "failed to convert data", "error", err)
-			return
+			return mux.Errorf(http.StatusInternalServerError, "failed to list options: %w", err)
 		}
-		viewLimit := util.Minimum(limit, int64(len(rows)-1))
+		// Each value is converted to a row, followed by the row for new values.
+		var rows []templates.DataTableRow
+		for _, value := range values {
+			row, err := e.Row(value)
+			if err != nil {
+				return mux.Errorf(http.StatusInternalServerError, "failed to convert data: %w", err)
+			}
+			rows = append(rows, *row)
+		}
+		rows = append(rows, e.NewRow(options))
+		viewLimit := util.Minimum(q.Limit, int64(len(rows)-1))
 		tbl := templates.DataTable{
 			Header: templates.DataTableHeader{
-				Values: metadata.Headers,
-			},
-			Rows: rows,
-			Footer: templates.DataTableFooter{
-				PostEndpoint: metadata.Post,
-				FormID:       "datatableform",
+				Values: e.Headers(),
 			},
-			Start:       fmt.Sprint(util.Maximum(offset, 0) + 1),
-			StartOffset: fmt.Sprint(util.Maximum(offset-limit, 0)),
-			End:         fmt.Sprint(offset + viewLimit),
-			LastPage:    viewLimit != limit,
+			Rows:      rows,
+			Footer:    e.Footer(),
+			Search:    q.Search,
+			Limit:     q.Limit,
+			PageSizes: PageSizes,
+			Start:     fmt.Sprint(q.Offset + 1),
+			End:       fmt.Sprint(q.Offset + viewLimit),
+		}
+		for _, name := range columns {
+			column := templates.DataTableColumn{
+				Name:    name,
+				Filter:  q.Filters[name],
+				SortURL: q.SortURL(name),
+			}
+			if q.Sort == name {
+				column.Sort = "asc"
+				if q.Desc {
+					column.Sort = "desc"
+				}
+			}
+			tbl.Columns = append(tbl.Columns, column)
+		}
+		if q.Sort != "" {
+			tbl.Sort = q.Values().Get("sort")
+		}
+		if q.Offset > 0 {
+			tbl.PrevURL = q.PageURL(q.Offset - q.Limit)
+		}
+		if viewLimit == q.Limit {
+			tbl.NextURL = q.PageURL(q.Offset + q.Limit)
 		}
 		if err := templates.DataTableView(tbl).Render(ctx, w); err != nil {
 			slog.WarnContext(ctx, "failed to render data table view", "error", err)
 		}
+		return nil
 	}
 }
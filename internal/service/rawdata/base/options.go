@@ -0,0 +1,36 @@
+package base
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/templates"
+)
+
+// suggestLimit is the number of values suggested for a reference as it is
+// typed.
+const suggestLimit = 20
+
+// HandleGetOptionsView renders the values suggested by Suggest for the column
+// of e named by the column path value, given the text of the query parameter
+// named after the column. They are the options of the datalist of its input,
+// which sends its value under its name.
+func HandleGetOptionsView(e Entity, table Table) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		name := r.PathValue("column")
+		c, ok := e.Column(name)
+		if !ok || c.Source == "" {
+			return mux.Errorf(http.StatusNotFound, "%s has no column %q referencing another table", e.Table, name)
+		}
+		options, err := c.Suggest(ctx, table.ReadDB, r.FormValue(name), suggestLimit)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to suggest options: %w", err)
+		}
+		if err := templates.DataTableOptionsView(options).Render(ctx, w); err != nil {
+			slog.WarnContext(ctx, "failed to render options", "error", err)
+		}
+		return nil
+	}
+}
@@ -1,113 +1,117 @@
 package base
 
 import (
-	"context"
-	"database/sql"
-	"fmt"
+	"errors"
 	"log/slog"
+	"maps"
 	"net/http"
+	"slices"
 
-	"github.com/RyRose/uplog/internal/sqlc/workoutdb"
+	"github.com/RyRose/uplog/internal/audit"
+	"github.com/RyRose/uplog/internal/history"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/templates"
+	"github.com/RyRose/uplog/internal/validate"
 )
 
-type PatchIDParams[dataType any] struct {
-	Query   func(*workoutdb.Queries, context.Context, dataType) error
-	Convert func(string, string) (*dataType, error)
-}
-
-func (p *PatchIDParams[dataType]) Patch(
-	ctx context.Context, queries *workoutdb.Queries, key, value string) error {
-	data, err := p.Convert(key, value)
-	if err != nil {
-		return fmt.Errorf("failed to convert patch data: %w", err)
-	}
-	return p.Query(queries, ctx, *data)
-}
-
-type PatcherID interface {
-	Patch(ctx context.Context, queries *workoutdb.Queries, key, value string) error
-}
-
-type PatchReqParams[dataType any] struct {
-	Query   func(*workoutdb.Queries, context.Context, dataType) error
-	Convert func(*http.Request, string) (*dataType, error)
-}
-
-func (p *PatchReqParams[dataType]) Patch(
-	ctx context.Context, queries *workoutdb.Queries, r *http.Request, value string) error {
-	data, err := p.Convert(r, value)
-	if err != nil {
-		return fmt.Errorf("failed to convert patch data: %w", err)
-	}
-	return p.Query(queries, ctx, *data)
-}
-
-type PatcherReq interface {
-	Patch(context.Context, *workoutdb.Queries, *http.Request, string) error
-}
-
-func HandlePatchTableRowViewID(
-	wDB *sql.DB, patchQ map[string]PatcherID) http.HandlerFunc {
-	queries := workoutdb.New(wDB)
-	return func(w http.ResponseWriter, r *http.Request) {
+// HandlePatchTableRowView applies every field of the form to the row of e
+// identified by its path values in one transaction, so either all of them
+// change or none do, then renders the row as stored. If the row changed since
+// the version the request is based on, no field is applied and the stored row
+// is rendered with the conflicting patch and a 409 status. Invalid fields are
+// not applied either: the stored row is rendered with their values and
+// problems and a 422 status. Updates recorded in the History of table are
+// followed by a toast offering to undo them.
+func HandlePatchTableRowView(e Entity, table Table) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
-		id := r.PathValue("id")
-		if err := r.ParseForm(); err != nil {
-			http.Error(w, fmt.Sprintf("failed to parse form: %v", err), http.StatusBadRequest)
-			slog.ErrorContext(ctx, "failed to parse form", "error", err)
-			return
+		fields, err := formFields(r)
+		if err != nil {
+			return err
 		}
-		for param, values := range r.Form {
-			patcher, ok := patchQ[param]
-			if !ok {
-				slog.WarnContext(ctx, "unknown patch parameter", "param", param)
-				continue
+		update, err := e.Update(e.PathKey(r), fields)
+		var invalid validate.Errors
+		if err != nil && !errors.As(err, &invalid) {
+			return mux.Errorf(http.StatusBadRequest, "failed to patch row: %w", err)
+		}
+		var (
+			after  audit.Row
+			change history.Change
+		)
+		if err == nil {
+			var session string
+			if table.History != nil {
+				session = history.Session(w, r)
 			}
-			if len(values) != 1 {
-				slog.WarnContext(ctx, "unexpected number of values", "param", param, "values", values)
-				continue
+			after, change, err = table.Update(r, session, update)
+		}
+		var conflict *ConflictError
+		switch {
+		case errors.As(err, &invalid):
+			// The stored row is rendered with the invalid values to fix.
+			slog.WarnContext(ctx, "failed to patch row", "error", err, "path", r.URL.Path)
+			if after, err = audit.Snapshot(ctx, table.ReadDB, table.Name, e.PathKey(r)); err != nil {
+				return mux.Errorf(http.StatusInternalServerError, "failed to select row: %w", err)
 			}
-			if err := patcher.Patch(ctx, queries, id, values[0]); err != nil {
-				http.Error(w, fmt.Sprintf("failed to patch row: %v", err), http.StatusInternalServerError)
-				slog.ErrorContext(ctx, "failed to patch row", "error", err)
-				return
+			if after == "" {
+				return mux.Errorf(http.StatusNotFound, "row not found")
 			}
-			w.WriteHeader(http.StatusOK)
-			return
+		case errors.As(err, &conflict):
+			// The stored row is rendered along with the patch that was not applied.
+			slog.WarnContext(ctx, "failed to patch row", "error", err, "path", r.URL.Path)
+			after = conflict.Row
+		case errors.Is(err, ErrNotFound):
+			return mux.Errorf(http.StatusNotFound, "failed to patch row: %w", err)
+		case err != nil:
+			return mux.Errorf(http.StatusInternalServerError, "failed to patch row: %w", err)
+		case after == "":
+			return mux.Errorf(http.StatusInternalServerError, "row not found after patch")
 		}
-		http.Error(w, "no patch data provided", http.StatusBadRequest)
-		slog.ErrorContext(ctx, "no patch data provided", "id", id)
-	}
-}
 
-func HandlePatchTableRowViewRequest(
-	wDB *sql.DB, patchQ map[string]PatcherReq) http.HandlerFunc {
-	queries := workoutdb.New(wDB)
-	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-		if err := r.ParseForm(); err != nil {
-			http.Error(w, fmt.Sprintf("failed to parse form: %v", err), http.StatusBadRequest)
-			slog.ErrorContext(ctx, "failed to parse form", "error", err)
-			return
+		row, err := e.Row(after)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to convert data to row: %w", err)
 		}
-		for param, values := range r.Form {
-			patcher, ok := patchQ[param]
-			if !ok {
-				slog.WarnContext(ctx, "unknown patch parameter", "param", param)
-				continue
-			}
-			if len(values) != 1 {
-				slog.WarnContext(ctx, "unexpected number of values", "param", param, "values", values)
-				continue
+		w.Header().Set("ETag", ETag(row.Version))
+		if invalid != nil {
+			markInvalid(row, fields, invalid)
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+		if conflict != nil {
+			if row.Conflict, err = conflictOf(after, update.Fields); err != nil {
+				return mux.Errorf(http.StatusInternalServerError, "failed to compare row: %w", err)
 			}
-			if err := patcher.Patch(ctx, queries, r, values[0]); err != nil {
-				http.Error(w, fmt.Sprintf("failed to patch row: %v", err), http.StatusInternalServerError)
-				slog.ErrorContext(ctx, "failed to patch row", "error", err)
-				return
+			w.WriteHeader(http.StatusConflict)
+		}
+		if err := templates.DataTableRowView(*row).Render(ctx, w); err != nil {
+			slog.WarnContext(ctx, "failed to render row", "error", err)
+		}
+		if change.ID != 0 && invalid == nil && conflict == nil {
+			if err := templates.UndoToast(change.Message()).Render(ctx, w); err != nil {
+				slog.WarnContext(ctx, "failed to render undo toast", "error", err)
 			}
-			w.WriteHeader(http.StatusOK)
-			return
 		}
-		http.Error(w, "no patch data provided", http.StatusBadRequest)
+		return nil
+	}
+}
+
+// conflictOf returns the patch of the fields of stored that was not applied.
+func conflictOf(stored audit.Row, fields map[string]string) (*templates.DataTableConflict, error) {
+	values, err := stored.Values()
+	if err != nil {
+		return nil, err
+	}
+	var conflict templates.DataTableConflict
+	for _, field := range slices.Sorted(maps.Keys(fields)) {
+		var value string
+		if v := values[field]; v != nil {
+			value = *v
+		}
+		conflict.Fields = append(conflict.Fields, templates.DataTableConflictField{
+			Name:    field,
+			Stored:  value,
+			Patched: fields[field],
+		})
 	}
+	return &conflict, nil
 }
@@ -1,59 +1,162 @@
 package base
 
 import (
-	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
-	"net/url"
 
-	"github.com/RyRose/uplog/internal/sqlc/workoutdb"
+	"github.com/RyRose/uplog/internal/audit"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/service/rawdata/util"
 	"github.com/RyRose/uplog/internal/templates"
+	"github.com/RyRose/uplog/internal/validate"
 )
 
-// HandlePostDataTableView is a generic handler for posting data to a data table view.
-// insertQ - function to insert data into the database.
-func HandlePostDataTableView[modelType, paramType any](
-	roDB *sql.DB,
-	wDB *sql.DB,
-	insertQ func(*workoutdb.Queries, context.Context, paramType) (modelType, error),
-	convertParams func(context.Context, url.Values) (*paramType, error),
-	toRow func(context.Context, *workoutdb.Queries, modelType) (*templates.DataTableRow, error),
-) http.HandlerFunc {
-	roQ := workoutdb.New(roDB)
-	wQ := workoutdb.New(wDB)
-	return func(w http.ResponseWriter, r *http.Request) {
+// HandlePostDataTableView inserts the rows of e posted by the form, as given by
+// formRows, in one transaction and renders them. If fields are invalid,
+// nothing is inserted and the row adding rows is rendered in place with the
+// values of the form and their problems and a 422 status.
+func HandlePostDataTableView(e Entity, table Table) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
-		if err := r.ParseForm(); err != nil {
-			http.Error(w, fmt.Sprintf("failed to parse form: %v", err), http.StatusBadRequest)
-			slog.ErrorContext(ctx, "failed to parse form", "error", err)
-			return
-		}
-		params, err := convertParams(ctx, r.Form)
+		rows, err := formRows(r, e)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("failed to convert data: %v", err), http.StatusBadRequest)
-			slog.ErrorContext(ctx, "failed to convert data", "error", err)
-			return
+			return err
 		}
-		data, err := insertQ(wQ, ctx, *params)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("failed to insert data: %v", err), http.StatusInternalServerError)
-			slog.ErrorContext(ctx, "failed to insert data", "error", err)
-			return
+		var (
+			inserts []Write
+			invalid validate.Errors
+		)
+		for _, fields := range rows {
+			insert, err := e.Insert(fields)
+			var errs validate.Errors
+			if errors.As(err, &errs) {
+				invalid = append(invalid, errs...)
+				continue
+			}
+			if err != nil {
+				return mux.Errorf(http.StatusBadRequest, "failed to insert data: %w", err)
+			}
+			inserts = append(inserts, insert)
+		}
+		var after []audit.Row
+		if len(invalid) == 0 {
+			after, err = table.Insert(r, inserts...)
+			errors.As(err, &invalid)
+		}
+		if len(invalid) > 0 {
+			slog.WarnContext(ctx, "failed to insert data", "error", invalid)
+			return renderInvalidNewRow(w, r, e, table, invalid)
 		}
-		row, err := toRow(ctx, roQ, data)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("failed to convert data to row: %v", err),
-				http.StatusInternalServerError)
-			slog.ErrorContext(ctx, "failed to convert data to row", "error", err)
-			return
+			return mux.Errorf(http.StatusInternalServerError, "failed to insert data: %w", err)
 		}
-		if err := templates.DataTableRowView(*row).Render(ctx, w); err != nil {
-			http.Error(w, fmt.Sprintf("failed to render row: %v", err), http.StatusInternalServerError)
-			slog.ErrorContext(ctx, "failed to render row", "error", err)
-			return
+		var out []templates.DataTableRow
+		for _, a := range after {
+			row, err := e.Row(a)
+			if err != nil {
+				return mux.Errorf(http.StatusInternalServerError, "failed to convert data to row: %w", err)
+			}
+			out = append(out, *row)
+		}
+		if len(out) == 1 {
+			w.Header().Set("ETag", ETag(out[0].Version))
 		}
 		w.WriteHeader(http.StatusCreated)
+		for _, row := range out {
+			if err := templates.DataTableRowView(row).Render(ctx, w); err != nil {
+				slog.WarnContext(ctx, "failed to render row", "error", err)
+				return nil
+			}
+		}
+		return nil
+	}
+}
+
+// renderInvalidNewRow renders the row adding rows of e in place of the one
+// that posted the form of r, showing its values and the problems invalid with
+// them.
+func renderInvalidNewRow(w http.ResponseWriter, r *http.Request, e Entity, table Table, invalid validate.Errors) error {
+	ctx := r.Context()
+	options, err := e.Options(ctx, table.ReadDB)
+	if err != nil {
+		return mux.Errorf(http.StatusInternalServerError, "failed to list options: %w", err)
+	}
+	row := e.NewRow(options)
+	for i, value := range row.Values {
+		row.Values[i].Value = r.Form.Get(value.Name)
+		row.Values[i].Selected = r.Form[value.Name]
+		row.Values[i].Error = invalid.Message(value.Name)
+	}
+	// The row posting the fields is replaced rather than added to.
+	w.Header().Set("HX-Reswap", "outerHTML")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	if err := templates.DataTableNewRowView(row, e.Footer()).Render(ctx, w); err != nil {
+		slog.WarnContext(ctx, "failed to render row", "error", err)
+	}
+	return nil
+}
+
+// markInvalid shows the values of the invalid fields in row along with their
+// problems.
+func markInvalid(row *templates.DataTableRow, fields map[string]*string, invalid validate.Errors) {
+	for i, value := range row.Values {
+		if message := invalid.Message(value.Name); message != "" {
+			row.Values[i].Value = util.Zero(fields[value.Name])
+			row.Values[i].Error = message
+		}
+	}
+}
+
+// formRows returns the rows of e posted by the form of r. Columns that can have
+// several values for a new row add a row for each value, and other fields must
+// have one value.
+func formRows(r *http.Request, e Entity) ([]map[string]*string, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, mux.Errorf(http.StatusBadRequest, "failed to parse form: %w", err)
+	}
+	rows := []map[string]*string{make(map[string]*string, len(r.Form))}
+	for param, values := range r.Form {
+		if c, ok := e.Column(param); !ok || !c.Multiple {
+			if len(values) != 1 {
+				return nil, &mux.Error{
+					Code:    http.StatusBadRequest,
+					Message: fmt.Sprintf("expected one value, got %d", len(values)),
+					Field:   param,
+				}
+			}
+		}
+		var next []map[string]*string
+		for _, row := range rows {
+			for i := range values {
+				row := maps.Clone(row)
+				row[param] = &values[i]
+				next = append(next, row)
+			}
+		}
+		rows = next
+	}
+	return rows, nil
+}
+
+// formFields returns the fields of the form of r. Each field must have one
+// value.
+func formFields(r *http.Request) (map[string]*string, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, mux.Errorf(http.StatusBadRequest, "failed to parse form: %w", err)
+	}
+	fields := make(map[string]*string, len(r.Form))
+	for param, values := range r.Form {
+		if len(values) != 1 {
+			return nil, &mux.Error{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("expected one value, got %d", len(values)),
+				Field:   param,
+			}
+		}
+		fields[param] = &values[0]
 	}
+	return fields, nil
 }
@@ -0,0 +1,181 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/RyRose/uplog/internal/audit"
+	"github.com/RyRose/uplog/internal/schema"
+	"github.com/RyRose/uplog/internal/search"
+)
+
+// DefaultLimit is the number of rows on a page unless another page size is
+// chosen.
+const DefaultLimit = 50
+
+// PageSizes are the page sizes that can be chosen.
+var PageSizes = []int64{25, 50, 100, 200}
+
+// filterPrefix starts the names of the query parameters filtering columns.
+const filterPrefix = "f."
+
+// Query selects a page of the rows of a data table. It is kept in the query of
+// the page URL so that it survives reloads and can be linked to.
+type Query struct {
+	// Search is free text every selected row matches.
+	Search string
+	// Filters maps columns to text their values contain.
+	Filters map[string]string
+	// Sort is the column rows are ordered by, in descending order if Desc.
+	Sort string
+	Desc bool
+	// Limit and Offset select the page.
+	Limit, Offset int64
+}
+
+// ParseQuery parses the query of a page URL selecting rows of a table with
+// columns. Parameters are q for the search, f.<column> for filters, sort for
+// the column to order by prefixed with - to reverse it, and limit and offset.
+func ParseQuery(values url.Values, columns []string) (Query, error) {
+	q := Query{
+		Search:  strings.TrimSpace(values.Get("q")),
+		Filters: make(map[string]string),
+		Limit:   DefaultLimit,
+	}
+	for name := range values {
+		column, ok := strings.CutPrefix(name, filterPrefix)
+		if !ok {
+			continue
+		}
+		if !slices.Contains(columns, column) {
+			return Query{}, fmt.Errorf("cannot filter unknown column %q", column)
+		}
+		if value := strings.TrimSpace(values.Get(name)); value != "" {
+			q.Filters[column] = value
+		}
+	}
+	if sort := values.Get("sort"); sort != "" {
+		q.Sort, q.Desc = strings.CutPrefix(sort, "-")
+		if !slices.Contains(columns, q.Sort) {
+			return Query{}, fmt.Errorf("cannot sort by unknown column %q", q.Sort)
+		}
+	}
+	if limit := values.Get("limit"); limit != "" {
+		var err error
+		if q.Limit, err = strconv.ParseInt(limit, 10, 64); err != nil {
+			return Query{}, fmt.Errorf("failed to parse limit: %w", err)
+		}
+		if !slices.Contains(PageSizes, q.Limit) {
+			return Query{}, fmt.Errorf("page size %d is not one of %v", q.Limit, PageSizes)
+		}
+	}
+	if offset := values.Get("offset"); offset != "" {
+		var err error
+		if q.Offset, err = strconv.ParseInt(offset, 10, 64); err != nil {
+			return Query{}, fmt.Errorf("failed to parse offset: %w", err)
+		}
+		if q.Offset < 0 {
+			return Query{}, fmt.Errorf("offset %d is negative", q.Offset)
+		}
+	}
+	return q, nil
+}
+
+// Values returns the query parameters of q, leaving out defaults.
+func (q Query) Values() url.Values {
+	values := url.Values{}
+	if q.Search != "" {
+		values.Set("q", q.Search)
+	}
+	for column, value := range q.Filters {
+		values.Set(filterPrefix+column, value)
+	}
+	if q.Sort != "" {
+		sort := q.Sort
+		if q.Desc {
+			sort = "-" + sort
+		}
+		values.Set("sort", sort)
+	}
+	if q.Limit != DefaultLimit {
+		values.Set("limit", strconv.FormatInt(q.Limit, 10))
+	}
+	if q.Offset > 0 {
+		values.Set("offset", strconv.FormatInt(q.Offset, 10))
+	}
+	return values
+}
+
+// URL returns the relative URL of the page selected by q.
+func (q Query) URL() string {
+	return "?" + q.Values().Encode()
+}
+
+// SortURL returns the URL of the first page sorted by column, reversing the
+// order if it is already sorted by it.
+func (q Query) SortURL(column string) string {
+	q.Desc = q.Sort == column && !q.Desc
+	q.Sort, q.Offset = column, 0
+	return q.URL()
+}
+
+// PageURL returns the URL of the page starting at offset.
+func (q Query) PageURL(offset int64) string {
+	q.Offset = max(offset, 0)
+	return q.URL()
+}
+
+// selectRows returns the rows of table with columns selected by q.
+func selectRows(ctx context.Context, db schema.Querier, index *search.Index, table string, columns []string, q Query) ([]audit.Row, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if q.Search != "" {
+		cond, condArgs, err := index.Match(ctx, db, table, q.Search)
+		if err != nil {
+			return nil, err
+		}
+		where, args = append(where, cond), append(args, condArgs...)
+	}
+	for _, column := range slices.Sorted(maps.Keys(q.Filters)) {
+		where = append(where, schema.Quote(column)+` LIKE ? ESCAPE '\'`)
+		args = append(args, search.Contains(q.Filters[column]))
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", schema.Object(columns), schema.Quote(table))
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	if q.Sort != "" {
+		query += " ORDER BY " + schema.Quote(q.Sort)
+		if q.Desc {
+			query += " DESC"
+		}
+		query += ", rowid"
+	}
+	query += " LIMIT ? OFFSET ?"
+	args = append(args, q.Limit, q.Offset)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select %s: %w", table, err)
+	}
+	var values []audit.Row
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to scan %s: %w", table, err), rows.Close())
+		}
+		values = append(values, audit.Row(raw))
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("failed to select %s: %w", table, err)
+	}
+	return values, nil
+}
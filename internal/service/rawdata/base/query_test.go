@@ -0,0 +1,63 @@
+package base
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/RyRose/uplog/internal/search"
+	"github.com/RyRose/uplog/internal/sqlc/sqlctest"
+)
+
+func TestSelectRows_SingleColumn(t *testing.T) {
+	ctx := context.Background()
+	db := sqlctest.Open(t)
+	// Lifts are taken out of their groups so that the groups can be cleared.
+	if _, err := db.ExecContext(ctx, "UPDATE lift SET lift_group = NULL"); err != nil {
+		t.Fatalf("failed to clear groups of lifts: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM lift_group"); err != nil {
+		t.Fatalf("failed to clear lift groups: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "INSERT INTO lift_group (id) VALUES ('push'), ('pull'), ('legs')"); err != nil {
+		t.Fatalf("failed to insert lift groups: %v", err)
+	}
+	index, err := search.New(ctx, db)
+	if err != nil {
+		t.Fatalf("failed to create search index: %v", err)
+	}
+
+	// The rows of lift_group are objects of their only column.
+	tests := []struct {
+		name string
+		q    Query
+		want []string
+	}{
+		{"sorted", Query{Sort: "id", Limit: DefaultLimit}, []string{"legs", "pull", "push"}},
+		{"reversed", Query{Sort: "id", Desc: true, Limit: 2}, []string{"push", "pull"}},
+		{"filtered", Query{Filters: map[string]string{"id": "pu"}, Sort: "id", Limit: DefaultLimit}, []string{"pull", "push"}},
+		{"searched", Query{Search: "legs", Limit: DefaultLimit}, []string{"legs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := selectRows(ctx, db, index, "lift_group", []string{"id"}, tt.q)
+			if err != nil {
+				t.Fatalf("selectRows() error = %v", err)
+			}
+			var got []string
+			for _, row := range rows {
+				values, err := row.Values()
+				if err != nil {
+					t.Fatalf("Values() of %s error = %v", row, err)
+				}
+				if len(values) != 1 || values["id"] == nil {
+					t.Fatalf("Values() of %s = %v, want only an id", row, values)
+				}
+				got = append(got, *values["id"])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectRows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package base
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/RyRose/uplog/internal/schema"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/service/rawdata/util"
+	"github.com/RyRose/uplog/internal/templates"
+)
+
+// HandleGetRenameDialog renders a dialog confirming the rename of the row of
+// the renamable entity e with the key path value to the key query value. The
+// dialog lists the rows that reference the row, which the rename cascades to.
+func HandleGetRenameDialog(roDB schema.Querier, e Entity) mux.HandlerFunc {
+	table, name := e.Table, e.Keys()[0].Name
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		from, to := r.PathValue(name), r.URL.Query().Get(name)
+		if to == "" {
+			return &mux.Error{Code: http.StatusBadRequest, Message: "must not be empty", Field: name}
+		}
+		if to == from {
+			w.WriteHeader(http.StatusNoContent)
+			return nil
+		}
+		deps, err := schema.Dependents(ctx, roDB, table, name, from)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to count dependent rows: %w", err)
+		}
+		dialog := templates.RenameDialog{
+			Table:         table,
+			Name:          name,
+			From:          from,
+			To:            to,
+			PatchEndpoint: util.UrlPathJoin(e.Endpoint(), from),
+			Dependents:    deps,
+		}
+		if err := templates.RenameDialogView(dialog).Render(ctx, w); err != nil {
+			slog.WarnContext(ctx, "failed to render rename dialog", "error", err)
+		}
+		return nil
+	}
+}
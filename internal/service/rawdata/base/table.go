@@ -0,0 +1,206 @@
+package base
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+
+	"github.com/RyRose/uplog/internal/audit"
+	"github.com/RyRose/uplog/internal/history"
+	"github.com/RyRose/uplog/internal/schema"
+	"github.com/RyRose/uplog/internal/search"
+	"github.com/RyRose/uplog/internal/sqlc/dbtx"
+)
+
+// ErrNotFound is returned when the row to change does not exist.
+var ErrNotFound = errors.New("row not found")
+
+// ConflictError is returned when the row to update changed since the version
+// the update is based on was read. Row is the row as stored.
+type ConflictError struct {
+	Row audit.Row
+}
+
+func (e *ConflictError) Error() string {
+	return "row changed since it was read"
+}
+
+// IfMatch returns the version of the row r is based on from its If-Match
+// header, or "" if r applies to any version.
+func IfMatch(r *http.Request) string {
+	v := strings.TrimSpace(r.Header.Get("If-Match"))
+	if v == "*" {
+		return ""
+	}
+	return strings.Trim(strings.TrimPrefix(v, "W/"), `"`)
+}
+
+// ETag returns the entity tag of the version of a row.
+func ETag(version string) string {
+	return `"` + version + `"`
+}
+
+// Table is a data table whose changes are made in transactions on DB and
+// recorded in Audit. Its rows are read from ReadDB and searched with Search.
+// If History is set, updates are also recorded there so they can be undone,
+// which needs a primary key of a single column.
+type Table struct {
+	Name    string
+	DB      dbtx.Beginner
+	ReadDB  schema.Querier
+	Audit   *audit.Log
+	Search  *search.Index
+	History *history.Log
+}
+
+// PathKey returns the primary key of the row of t identified by the path of r.
+// Path values are named after the primary key columns they hold.
+func (t Table) PathKey(r *http.Request, db schema.Querier) (map[string]string, error) {
+	_, primaryKey, err := schema.Columns(r.Context(), db, t.Name)
+	if err != nil {
+		return nil, err
+	}
+	key := make(map[string]string, len(primaryKey))
+	for _, c := range primaryKey {
+		key[c] = r.PathValue(c)
+	}
+	return key, nil
+}
+
+// Insert makes the inserts ws in one transaction and records the rows they
+// inserted, which it returns in order.
+func (t Table) Insert(r *http.Request, ws ...Write) ([]audit.Row, error) {
+	rows := make([]audit.Row, len(ws))
+	err := t.transact(r, func(tx *sql.Tx) error {
+		for i, w := range ws {
+			var err error
+			if rows[i], err = t.apply(r, tx, audit.Inserted, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Update makes the update w of the row identified by the path of r and
+// records the change, in History too as a change of session if t has one. It
+// returns the row as updated and the change recorded in History, ErrNotFound
+// if there is no such row, or a ConflictError if the row is not the version r
+// is based on, as given by IfMatch.
+func (t Table) Update(r *http.Request, session string, w Write) (audit.Row, history.Change, error) {
+	var (
+		after  audit.Row
+		change history.Change
+	)
+	err := t.transact(r, func(tx *sql.Tx) error {
+		var err error
+		if t.History != nil {
+			if change, err = t.record(r, tx, session); err != nil {
+				return err
+			}
+		}
+		after, err = t.apply(r, tx, audit.Updated, w)
+		return err
+	})
+	return after, change, err
+}
+
+// record records the update of the row identified by the path of r in the
+// History of t before it is made in tx.
+func (t Table) record(r *http.Request, tx *sql.Tx, session string) (history.Change, error) {
+	key, err := t.PathKey(r, tx)
+	if err != nil {
+		return history.Change{}, err
+	}
+	if len(key) != 1 {
+		return history.Change{}, fmt.Errorf("cannot record history of %s without a primary key of one column", t.Name)
+	}
+	var change history.Change
+	for column, value := range key {
+		change, err = t.History.Record(r.Context(), tx, session, history.Updated, t.Name, column, value)
+	}
+	if errors.Is(err, history.ErrNotFound) {
+		return history.Change{}, fmt.Errorf("%s %v: %w", t.Name, key, ErrNotFound)
+	}
+	return change, err
+}
+
+// Delete makes the delete w of the row identified by the path of r and
+// records the row it deleted. It returns ErrNotFound if there is no such row.
+func (t Table) Delete(r *http.Request, w Write) error {
+	return t.transact(r, func(tx *sql.Tx) error {
+		_, err := t.apply(r, tx, audit.Deleted, w)
+		return err
+	})
+}
+
+// transact calls f in a transaction, which is committed if f succeeds.
+func (t Table) transact(r *http.Request, f func(tx *sql.Tx) error) error {
+	tx, err := t.DB.BeginTx(r.Context(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := f(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// apply makes w in tx and records it, returning the row after the change.
+func (t Table) apply(r *http.Request, tx *sql.Tx, action audit.Action, w Write) (audit.Row, error) {
+	ctx := r.Context()
+	var (
+		key    map[string]string
+		before audit.Row
+		err    error
+	)
+	if action != audit.Inserted {
+		if key, err = t.PathKey(r, tx); err != nil {
+			return "", err
+		}
+		if before, err = audit.Snapshot(ctx, tx, t.Name, key); err != nil {
+			return "", err
+		}
+		if before == "" {
+			return "", fmt.Errorf("%s %v: %w", t.Name, key, ErrNotFound)
+		}
+		if v := IfMatch(r); v != "" && action == audit.Updated && v != before.Version() {
+			return "", &ConflictError{Row: before}
+		}
+	}
+	if err := w.Exec(ctx, tx); err != nil {
+		return "", err
+	}
+	var after audit.Row
+	switch action {
+	case audit.Inserted:
+		after, err = audit.SnapshotInserted(ctx, tx, t.Name)
+	case audit.Updated:
+		// The row is found by its new key if the fields are part of it.
+		next := maps.Clone(key)
+		for field, value := range w.Fields {
+			if _, ok := next[field]; ok {
+				next[field] = value
+			}
+		}
+		after, err = audit.Snapshot(ctx, tx, t.Name, next)
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := t.Audit.Record(ctx, tx, t.Audit.FromRequest(r), t.Name, before, after); err != nil {
+		return "", err
+	}
+	return after, nil
+}
@@ -0,0 +1,115 @@
+package rawdata
+
+import (
+	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/service/rawdata/base"
+)
+
+// HandleGetView godoc
+//
+//	@Summary		Get data table view
+//	@Description	Renders a paginated table view of the rows of a data table, selected by the query of the page URL in the HX-Current-URL header
+//	@Tags			rawdata
+//	@Produce		html
+//	@Param			table	path		string	true	"Data table"	Enums(lift, movement, muscle, routine, workout, template_variable, lift_group, side_weight, progress, subworkout, routine_workout_mapping, lift_muscle_mapping, lift_workout_mapping)
+//	@Param			q		query		string	false	"Free text search"
+//	@Param			sort	query		string	false	"Column to sort by, prefixed with - to reverse"
+//	@Param			limit	query		integer	false	"Page size"
+//	@Param			offset	query		integer	false	"Pagination offset"
+//	@Success		200		{string}	string	"HTML content"
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/view/data/{table} [get]
+func HandleGetView(e base.Entity, _ *config.Data, state *config.State) mux.HandlerFunc {
+	return base.HandleGetDataTableView(e, dataTable(state, e.Table))
+}
+
+// HandlePostView godoc
+//
+//	@Summary		Create data table row
+//	@Description	Creates a row of a data table from form fields named after its columns and renders it. Generated IDs are left out. Mapping table columns given several values create a row for each in one transaction
+//	@Tags			rawdata
+//	@Accept			x-www-form-urlencoded
+//	@Produce		html
+//	@Param			table	path		string	true	"Data table"	Enums(lift, movement, muscle, routine, workout, template_variable, lift_group, side_weight, progress, subworkout, routine_workout_mapping, lift_muscle_mapping, lift_workout_mapping)
+//	@Success		201		{string}	string	"HTML content"
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		422		{string}	string	"HTML content of the new row with the invalid fields"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/view/data/{table} [post]
+func HandlePostView(e base.Entity, _ *config.Data, state *config.State) mux.HandlerFunc {
+	return base.HandlePostDataTableView(e, dataTable(state, e.Table))
+}
+
+// HandlePatchView godoc
+//
+//	@Summary		Update data table row
+//	@Description	Updates the given fields of a row of a data table in one transaction and renders the updated row, followed by a toast offering to undo the edit for progress. The row is identified by one path segment per primary key column
+//	@Tags			rawdata
+//	@Accept			x-www-form-urlencoded
+//	@Produce		html
+//	@Param			table		path		string	true	"Data table"	Enums(lift, movement, muscle, routine, workout, template_variable, lift_group, side_weight, progress, subworkout, routine_workout_mapping, lift_muscle_mapping, lift_workout_mapping)
+//	@Param			key			path		string	true	"Primary key of the row"
+//	@Param			If-Match	header		string	false	"Version of the row the patch is based on"
+//	@Success		200			{string}	string	"HTML content"
+//	@Failure		400			{string}	string	"Bad request"
+//	@Failure		404			{string}	string	"Row not found"
+//	@Failure		409			{string}	string	"HTML content of the stored row if it changed since that version"
+//	@Failure		422			{string}	string	"HTML content of the stored row with the invalid fields"
+//	@Failure		500			{string}	string	"Internal server error"
+//	@Router			/view/data/{table}/{key} [patch]
+func HandlePatchView(e base.Entity, _ *config.Data, state *config.State) mux.HandlerFunc {
+	return base.HandlePatchTableRowView(e, dataTable(state, e.Table))
+}
+
+// HandleDeleteView godoc
+//
+//	@Summary		Delete data table row
+//	@Description	Deletes a row of a data table. Rows with a text ID are moved to the trash, and if other rows depend on them, a dialog listing them is rendered instead unless cascade is set
+//	@Tags			rawdata
+//	@Param			table	path		string	true	"Data table"	Enums(lift, movement, muscle, routine, workout, template_variable, lift_group, side_weight, progress, subworkout, routine_workout_mapping, lift_muscle_mapping, lift_workout_mapping)
+//	@Param			key		path		string	true	"Primary key of the row"
+//	@Param			cascade	query		bool	false	"Also remove the rows depending on it"
+//	@Success		200		{string}	string	"OK, or the HTML dialog listing dependent rows"
+//	@Failure		404		{string}	string	"Not found"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/view/data/{table}/{key} [delete]
+func HandleDeleteView(e base.Entity, _ *config.Data, state *config.State) mux.HandlerFunc {
+	return base.HandleDeleteTableRowView(state.Trash, e, dataTable(state, e.Table))
+}
+
+// HandleGetRenameView godoc
+//
+//	@Summary		Confirm data table row rename
+//	@Description	Renders a dialog confirming the rename of a row with a text ID with the number of rows referencing it
+//	@Tags			rawdata
+//	@Produce		html
+//	@Param			table	path		string	true	"Data table"	Enums(lift, movement, muscle, routine, workout, template_variable, lift_group, side_weight, progress, subworkout, routine_workout_mapping, lift_muscle_mapping, lift_workout_mapping)
+//	@Param			id		path		string	true	"Row ID"
+//	@Param			id		query		string	true	"New row ID"
+//	@Success		200		{string}	string	"HTML content"
+//	@Success		204		{string}	string	"ID unchanged"
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/view/data/{table}/{id}/rename [get]
+func HandleGetRenameView(e base.Entity, _ *config.Data, state *config.State) mux.HandlerFunc {
+	return base.HandleGetRenameDialog(state.RPrepared, e)
+}
+
+// HandleGetOptionsView godoc
+//
+//	@Summary		Suggest data table references
+//	@Description	Renders the options of the datalist of a column referencing another table, which are the keys of that table containing the typed text, those starting with it first
+//	@Tags			rawdata
+//	@Produce		html
+//	@Param			table	path		string	true	"Data table"	Enums(lift, movement, muscle, routine, workout, template_variable, lift_group, side_weight, progress, subworkout, routine_workout_mapping, lift_muscle_mapping, lift_workout_mapping)
+//	@Param			column	path		string	true	"Column referencing another table"
+//	@Param			text	query		string	false	"Typed text, sent as the query parameter named after the column"
+//	@Success		200		{string}	string	"HTML content"
+//	@Failure		404		{string}	string	"No such column"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/view/options/{table}/{column} [get]
+func HandleGetOptionsView(e base.Entity, _ *config.Data, state *config.State) mux.HandlerFunc {
+	return base.HandleGetOptionsView(e, dataTable(state, e.Table))
+}
@@ -1,225 +0,0 @@
-package rawdata
-
-import (
-	"context"
-	"database/sql"
-	"fmt"
-	"net/http"
-	"net/url"
-
-	"github.com/RyRose/uplog/internal/config"
-	"github.com/RyRose/uplog/internal/service/rawdata/base"
-	"github.com/RyRose/uplog/internal/service/rawdata/util"
-	"github.com/RyRose/uplog/internal/sqlc/workoutdb"
-	"github.com/RyRose/uplog/internal/templates"
-)
-
-// HandleGetLiftView godoc
-//
-//	@Summary		Get lift data table view
-//	@Description	Renders a paginated table view of lifts with their details
-//	@Tags			rawdata
-//	@Produce		html
-//	@Param			offset	query		integer	false	"Pagination offset"
-//	@Success		200		{string}	string	"HTML content"
-//	@Failure		400		{string}	string	"Bad request"
-//	@Failure		500		{string}	string	"Internal server error"
-//	@Router			/view/data/lift [get]
-func HandleGetLiftView(_ *config.Data, state *config.State) http.HandlerFunc {
-	return base.HandleGetDataTableView(
-		state.RDB,
-		base.TableViewMetadata{
-			Headers: []string{"ID", "Link", "Side", "Notes", "Group"},
-			Post:    "/view/data/lift",
-		},
-		(*workoutdb.Queries).RawSelectLiftPage,
-		func(limit, offset int64) workoutdb.RawSelectLiftPageParams {
-			return workoutdb.RawSelectLiftPageParams{
-				Limit:  limit,
-				Offset: offset,
-			}
-		},
-		func(ctx context.Context, roDB *sql.DB, lifts []workoutdb.Lift) ([]templates.DataTableRow, error) {
-			q := workoutdb.New(roDB)
-			sideWeightOpts, err := q.ListAllIndividualSideWeights(ctx)
-			if err != nil {
-				return nil, fmt.Errorf("failed to list side weights: %w", err)
-			}
-
-			liftGroups, err := q.ListAllIndividualLiftGroups(ctx)
-			if err != nil {
-				return nil, fmt.Errorf("failed to list side weights: %w", err)
-			}
-			var rows []templates.DataTableRow
-			for _, lift := range lifts {
-				rows = append(rows, templates.DataTableRow{
-					PatchEndpoint:  util.UrlPathJoin("/view/data/lift", lift.ID),
-					DeleteEndpoint: util.UrlPathJoin("/view/data/lift", lift.ID),
-					Values: []templates.DataTableValue{
-						{Name: "id", Value: lift.ID, Type: templates.InputString},
-						{Name: "link", Value: lift.Link, Type: templates.InputString},
-						{Name: "default_side_weight",
-							Value: util.Zero(lift.DefaultSideWeight),
-							Type:  templates.Select, SelectOptions: append(sideWeightOpts, "")},
-						{Name: "notes", Value: util.Zero(lift.Notes), Type: templates.InputString},
-						{Name: "lift_group",
-							Value: util.Zero(lift.LiftGroup),
-							Type:  templates.Select, SelectOptions: append(liftGroups, "")},
-					},
-				})
-			}
-			rows = append(rows, templates.DataTableRow{
-				Values: []templates.DataTableValue{
-					{Name: "id", Type: templates.InputString},
-					{Name: "link", Type: templates.InputString},
-					{Name: "default_side_weight",
-						Type: templates.Select, SelectOptions: append(sideWeightOpts, "")},
-					{Name: "notes", Type: templates.InputString},
-					{Name: "lift_group",
-						Type: templates.Select, SelectOptions: append(liftGroups, "")},
-				},
-			})
-			return rows, nil
-		},
-	)
-}
-
-// HandlePatchLiftView godoc
-//
-//	@Summary		Update lift data
-//	@Description	Updates specific fields of a lift entry by ID
-//	@Tags			rawdata
-//	@Accept			x-www-form-urlencoded
-//	@Param			id					path		string	true	"Lift ID"
-//	@Param			id					formData	string	false	"New lift ID"
-//	@Param			link				formData	string	false	"Lift link"
-//	@Param			default_side_weight	formData	string	false	"Default side weight"
-//	@Param			notes				formData	string	false	"Notes"
-//	@Param			lift_group			formData	string	false	"Lift group"
-//	@Success		200					{string}	string	"OK"
-//	@Failure		400					{string}	string	"Bad request"
-//	@Failure		500					{string}	string	"Internal server error"
-//	@Router			/view/data/lift/{id} [patch]
-func HandlePatchLiftView(_ *config.Data, state *config.State) http.HandlerFunc {
-	return base.HandlePatchTableRowViewID(
-		state.WDB,
-		map[string]base.PatcherID{
-			"id": &base.PatchIDParams[workoutdb.RawUpdateLiftIdParams]{
-				Query: (*workoutdb.Queries).RawUpdateLiftId,
-				Convert: func(id, value string) (*workoutdb.RawUpdateLiftIdParams, error) {
-					return &workoutdb.RawUpdateLiftIdParams{
-						In:  id,
-						Out: value,
-					}, nil
-				},
-			},
-			"link": &base.PatchIDParams[workoutdb.RawUpdateLiftLinkParams]{
-				Query: (*workoutdb.Queries).RawUpdateLiftLink,
-				Convert: func(id, value string) (*workoutdb.RawUpdateLiftLinkParams, error) {
-					return &workoutdb.RawUpdateLiftLinkParams{
-						ID:   id,
-						Link: value,
-					}, nil
-				},
-			},
-			"default_side_weight": &base.PatchIDParams[workoutdb.RawUpdateLiftDefaultSideWeightParams]{
-				Query: (*workoutdb.Queries).RawUpdateLiftDefaultSideWeight,
-				Convert: func(id, value string) (*workoutdb.RawUpdateLiftDefaultSideWeightParams, error) {
-					return &workoutdb.RawUpdateLiftDefaultSideWeightParams{
-						ID:                id,
-						DefaultSideWeight: util.DeZero(value),
-					}, nil
-				},
-			},
-			"notes": &base.PatchIDParams[workoutdb.RawUpdateLiftNotesParams]{
-				Query: (*workoutdb.Queries).RawUpdateLiftNotes,
-				Convert: func(id, value string) (*workoutdb.RawUpdateLiftNotesParams, error) {
-					return &workoutdb.RawUpdateLiftNotesParams{
-						ID:    id,
-						Notes: util.DeZero(value),
-					}, nil
-				},
-			},
-			"lift_group": &base.PatchIDParams[workoutdb.RawUpdateLiftLiftGroupParams]{
-				Query: (*workoutdb.Queries).RawUpdateLiftLiftGroup,
-				Convert: func(id, value string) (*workoutdb.RawUpdateLiftLiftGroupParams, error) {
-					return &workoutdb.RawUpdateLiftLiftGroupParams{
-						ID:        id,
-						LiftGroup: util.DeZero(value),
-					}, nil
-				},
-			},
-		},
-	)
-}
-
-// HandlePostLiftView godoc
-//
-//	@Summary		Create new lift
-//	@Description	Creates a new lift entry in the database
-//	@Tags			rawdata
-//	@Accept			x-www-form-urlencoded
-//	@Produce		html
-//	@Param			id					formData	string	true	"Lift ID"
-//	@Param			link				formData	string	true	"Lift link"
-//	@Param			default_side_weight	formData	string	false	"Default side weight"
-//	@Param			notes				formData	string	false	"Notes"
-//	@Param			lift_group			formData	string	false	"Lift group"
-//	@Success		201					{string}	string	"HTML content"
-//	@Failure		400					{string}	string	"Bad request"
-//	@Failure		500					{string}	string	"Internal server error"
-//	@Router			/view/data/lift [post]
-func HandlePostLiftView(_ *config.Data, state *config.State) http.HandlerFunc {
-	return base.HandlePostDataTableView(
-		state.RDB,
-		state.WDB,
-		(*workoutdb.Queries).RawInsertLift,
-		func(_ context.Context, values url.Values) (*workoutdb.RawInsertLiftParams, error) {
-			return &workoutdb.RawInsertLiftParams{
-				ID:                values.Get("id"),
-				Link:              values.Get("link"),
-				DefaultSideWeight: util.DeZero(values.Get("default_side_weight")),
-				Notes:             util.DeZero(values.Get("notes")),
-				LiftGroup:         util.DeZero(values.Get("lift_group")),
-			}, nil
-		},
-		func(ctx context.Context, q *workoutdb.Queries, lift workoutdb.Lift) (*templates.DataTableRow, error) {
-			sideWeightOpts, err := q.ListAllIndividualSideWeights(ctx)
-			if err != nil {
-				return nil, fmt.Errorf("failed to list side weights: %w", err)
-			}
-			liftGroups, err := q.ListAllIndividualLiftGroups(ctx)
-			if err != nil {
-				return nil, fmt.Errorf("failed to list side weights: %w", err)
-			}
-			return &templates.DataTableRow{
-				PatchEndpoint:  util.UrlPathJoin("/view/data/lift", lift.ID),
-				DeleteEndpoint: util.UrlPathJoin("/view/data/lift", lift.ID),
-				Values: []templates.DataTableValue{
-					{Name: "id", Value: lift.ID, Type: templates.InputString},
-					{Name: "link", Value: lift.Link, Type: templates.InputString},
-					{Name: "default_side_weight",
-						Value: util.Zero(lift.DefaultSideWeight),
-						Type:  templates.Select, SelectOptions: append(sideWeightOpts, "")},
-					{Name: "notes", Value: util.Zero(lift.Notes), Type: templates.InputString},
-					{Name: "lift_group",
-						Value: util.Zero(lift.LiftGroup),
-						Type:  templates.Select, SelectOptions: append(liftGroups, "")},
-				},
-			}, nil
-		},
-	)
-}
-
-// HandleDeleteLiftView godoc
-//
-//	@Summary		Delete lift
-//	@Description	Deletes a lift entry by ID
-//	@Tags			rawdata
-//	@Param			id	path		string	true	"Lift ID"
-//	@Success		200	{string}	string	"OK"
-//	@Failure		500	{string}	string	"Internal server error"
-//	@Router			/view/data/lift/{id} [delete]
-func HandleDeleteLiftView(_ *config.Data, state *config.State) http.HandlerFunc {
-	return base.HandleDeleteTableRowViewID(state.WDB, (*workoutdb.Queries).RawDeleteLift)
-}
@@ -0,0 +1,145 @@
+package rawdata
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/matrix"
+	"github.com/RyRose/uplog/internal/service/mux"
+	"github.com/RyRose/uplog/internal/templates"
+)
+
+// HandleGetMatrixView godoc
+//
+//	@Summary		Get mapping matrix view
+//	@Description	Renders the mappings of lifts as a matrix of lifts by the muscles or workouts they map to, with the role of each muscle
+//	@Tags			rawdata
+//	@Produce		html
+//	@Param			table	path		string	true	"Mapping table"	Enums(lift_muscle_mapping, lift_workout_mapping)
+//	@Param			group	query		string	false	"Lift group of the lifts shown"
+//	@Success		200		{string}	string	"HTML content"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/view/matrix/{table} [get]
+func HandleGetMatrixView(m matrix.Mapping, _ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		return renderMatrix(w, r, m, state, r.FormValue("group"), "", http.StatusOK)
+	}
+}
+
+// HandlePostMatrixView godoc
+//
+//	@Summary		Save mapping matrix
+//	@Description	Sets the cells of a mapping matrix in one transaction and renders it. Cells are named cell.{lift}.{column} after the indexes of the lift and column fields listing the lifts and columns shown, and hold the roles of the cell joined by newlines, or 1 if checked for mappings without roles. Nothing is saved if the mappings changed since the version they were shown at
+//	@Tags			rawdata
+//	@Accept			x-www-form-urlencoded
+//	@Produce		html
+//	@Param			table	path		string	true	"Mapping table"	Enums(lift_muscle_mapping, lift_workout_mapping)
+//	@Param			group	formData	string	false	"Lift group of the lifts shown"
+//	@Param			version	formData	string	true	"Version of the mappings shown"
+//	@Success		200		{string}	string	"HTML content"
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		409		{string}	string	"HTML content of the stored matrix if it changed since that version"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/view/matrix/{table} [post]
+func HandlePostMatrixView(m matrix.Mapping, _ *config.Data, state *config.State) mux.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		if err := r.ParseForm(); err != nil {
+			return mux.Errorf(http.StatusBadRequest, "failed to parse form: %w", err)
+		}
+		group := r.PostForm.Get("group")
+		shown, err := m.Load(ctx, state.RPrepared, group)
+		if err != nil {
+			return mux.Errorf(http.StatusInternalServerError, "failed to load matrix: %w", err)
+		}
+		lifts, columns := r.PostForm["lift"], r.PostForm["column"]
+		cells := make(map[matrix.Cell][]string)
+		for i, lift := range lifts {
+			for j, column := range columns {
+				value := r.PostForm.Get(templates.MatrixCellName(i, j))
+				var roles []string
+				switch {
+				case value == "":
+				case m.Role == "":
+					roles = []string{""}
+				default:
+					roles = strings.Split(value, "\n")
+				}
+				cells[matrix.Cell{Lift: lift, Column: column}] = roles
+			}
+		}
+		// Lifts, columns or roles that no longer exist changed since the
+		// matrix was shown just as its mappings can.
+		changed, err := 0, matrix.ErrConflict
+		if subset(lifts, shown.Lifts) && subset(columns, shown.Columns) && validRoles(cells, m, shown.Roles) {
+			changed, err = m.Save(ctx, state.WPrepared, state.Audit, state.Audit.FromRequest(r), lifts, r.PostForm.Get("version"), cells)
+		}
+		switch {
+		case errors.Is(err, matrix.ErrConflict):
+			slog.WarnContext(ctx, "failed to save matrix", "error", err, "table", m.Table)
+			return renderMatrix(w, r, m, state, group,
+				"The mappings changed elsewhere, so nothing was saved. They are shown as stored now.", http.StatusConflict)
+		case err != nil:
+			return mux.Errorf(http.StatusInternalServerError, "failed to save matrix: %w", err)
+		}
+		return renderMatrix(w, r, m, state, group, fmt.Sprintf("Saved %d changed mappings.", changed), http.StatusOK)
+	}
+}
+
+// renderMatrix renders the matrix of m for the lifts of group with message and
+// status.
+func renderMatrix(w http.ResponseWriter, r *http.Request, m matrix.Mapping, state *config.State, group, message string, status int) error {
+	ctx := r.Context()
+	mat, err := m.Load(ctx, state.RPrepared, group)
+	if err != nil {
+		return mux.Errorf(http.StatusInternalServerError, "failed to load matrix: %w", err)
+	}
+	groups, err := state.RQ.ListAllIndividualLiftGroups(ctx)
+	if err != nil {
+		return mux.Errorf(http.StatusInternalServerError, "failed to list lift groups: %w", err)
+	}
+	slices.Sort(groups)
+	data := templates.MatrixViewData{
+		Endpoint: m.Endpoint(),
+		Matrix:   *mat,
+		Checked:  m.Role == "",
+		Group:    group,
+		Groups:   groups,
+		Message:  message,
+		Conflict: status == http.StatusConflict,
+	}
+	w.WriteHeader(status)
+	if err := templates.MatrixView(data).Render(ctx, w); err != nil {
+		slog.WarnContext(ctx, "failed to render matrix view", "error", err)
+	}
+	return nil
+}
+
+// subset returns whether every value of values is one of all.
+func subset(values, all []string) bool {
+	for _, v := range values {
+		if !slices.Contains(all, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// validRoles returns whether the roles of cells are all one of roles, or ""
+// if m has no roles.
+func validRoles(cells map[matrix.Cell][]string, m matrix.Mapping, roles []string) bool {
+	if m.Role == "" {
+		roles = []string{""}
+	}
+	for _, r := range cells {
+		if !subset(r, roles) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,159 @@
+package rawdata
+
+import (
+	"github.com/RyRose/uplog/internal/service/rawdata/base"
+	"github.com/RyRose/uplog/internal/templates"
+	"github.com/RyRose/uplog/internal/validate"
+)
+
+// Registry declares the data tables in the order of their tabs. Their routes,
+// tabs, handlers and validation follow from the declarations, so a table is
+// added by declaring it here.
+var Registry = []base.Entity{
+	{
+		Table: "lift",
+		Title: "Lifts",
+		Columns: []base.Column{
+			{Name: "id", Header: "ID", Key: true},
+			{Name: "link", Header: "Link", Input: base.URL, Check: validate.URL},
+			{Name: "default_side_weight", Header: "Side", Null: true, Source: "side_weight"},
+			{Name: "notes", Header: "Notes", Null: true},
+			{Name: "lift_group", Header: "Group", Null: true, Source: "lift_group"},
+		},
+	},
+	{
+		Table: "movement",
+		Title: "Movements",
+		Columns: []base.Column{
+			{Name: "id", Header: "ID", Key: true},
+			{Name: "alias", Header: "Alias"},
+		},
+	},
+	{
+		Table: "muscle",
+		Title: "Muscles",
+		Columns: []base.Column{
+			{Name: "id", Header: "ID", Key: true},
+			{Name: "link", Header: "Link", Input: base.URL, Check: validate.URL},
+			{Name: "message", Header: "Message", Null: true},
+		},
+	},
+	{
+		Table: "routine",
+		Title: "Routines",
+		Columns: []base.Column{
+			{Name: "id", Header: "ID", Key: true},
+			{Name: "steps", Header: "Steps", Check: validate.Steps},
+			{Name: "lift", Header: "Lift", Source: "lift"},
+		},
+	},
+	{
+		Table: "workout",
+		Title: "Workouts",
+		Columns: []base.Column{
+			{Name: "id", Header: "ID", Key: true},
+			{Name: "template", Header: "Template", Long: true},
+		},
+	},
+	{
+		Table: "template_variable",
+		Title: "Variables",
+		Group: 1,
+		Columns: []base.Column{
+			{Name: "id", Header: "ID", Key: true},
+			{Name: "value", Header: "Value", Long: true},
+		},
+	},
+	{
+		Table: "lift_group",
+		Title: "Lift Groups",
+		Group: 1,
+		Columns: []base.Column{
+			{Name: "id", Header: "ID", Key: true},
+		},
+	},
+	{
+		Table: "side_weight",
+		Title: "Side Weight",
+		Group: 2,
+		Columns: []base.Column{
+			{Name: "id", Header: "ID", Key: true},
+			{Name: "multiplier", Header: "Mult", Kind: base.Real, Check: validate.NonNegative},
+			{Name: "addend", Header: "Addend", Kind: base.Real},
+			{Name: "format", Header: "Format", Check: validate.SideWeightFormat},
+		},
+	},
+	{
+		Table: "progress",
+		Title: "Progress",
+		Group: 2,
+		Columns: []base.Column{
+			{Name: "id", Header: "ID", Kind: base.Integer, Key: true},
+			{Name: "lift", Header: "Lift", Source: "lift"},
+			{Name: "date", Header: "Date", Input: base.Date, Check: validate.Date},
+			{Name: "weight", Header: "Weight", Kind: base.Real, Check: validate.NonNegative},
+			{Name: "sets", Header: "Sets", Kind: base.Integer, Check: validate.NonNegative},
+			{Name: "reps", Header: "Reps", Kind: base.Integer, Check: validate.NonNegative},
+			{Name: "side_weight", Header: "SW", Null: true, Source: "side_weight"},
+		},
+	},
+	{
+		Table: "subworkout",
+		Title: "Subworkouts",
+		Group: 2,
+		Columns: []base.Column{
+			{Name: "subworkout", Header: "Subworkout", Key: true, Source: "workout"},
+			{Name: "superworkout", Header: "Superworkout", Key: true, Source: "workout"},
+		},
+	},
+	{
+		Table: "routine_workout_mapping",
+		Title: "Routine:Workout",
+		Group: 3,
+		Columns: []base.Column{
+			{Name: "routine", Header: "Routine", Key: true, Source: "routine"},
+			{Name: "workout", Header: "Workout", Key: true, Source: "workout", Multiple: true},
+		},
+	},
+	{
+		Table: "lift_muscle_mapping",
+		Title: "Lift:Muscle",
+		Group: 3,
+		Columns: []base.Column{
+			{Name: "lift", Header: "Lift", Key: true, Source: "lift"},
+			{Name: "muscle", Header: "Muscle", Key: true, Source: "muscle", Multiple: true},
+			{Name: "movement", Header: "Movement", Key: true, Source: "movement"},
+		},
+	},
+	{
+		Table: "lift_workout_mapping",
+		Title: "Lift:Workout",
+		Group: 3,
+		Columns: []base.Column{
+			{Name: "lift", Header: "Lift", Key: true, Source: "lift"},
+			{Name: "workout", Header: "Workout", Key: true, Source: "workout", Multiple: true},
+		},
+	},
+}
+
+// Tabs returns the tabs of the data tables of the registry, grouped in rows.
+func Tabs() [][]templates.DataTab {
+	var tabs [][]templates.DataTab
+	for _, e := range Registry {
+		for len(tabs) <= e.Group {
+			tabs = append(tabs, nil)
+		}
+		tabs[e.Group] = append(tabs[e.Group], templates.DataTab{Title: e.Title, Endpoint: e.Endpoint()})
+	}
+	return tabs
+}
+
+// Lookup returns the data table of the registry declared for table.
+func Lookup(table string) (base.Entity, bool) {
+	for _, e := range Registry {
+		if e.Table == table {
+			return e, true
+		}
+	}
+	return base.Entity{}, false
+}
@@ -0,0 +1,147 @@
+package rawdata
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/RyRose/uplog/internal/schema"
+	"github.com/RyRose/uplog/internal/service/rawdata/base"
+	"github.com/RyRose/uplog/internal/sqlc/sqlctest"
+)
+
+// TestRegistry checks that the declarations match the tables they declare.
+func TestRegistry(t *testing.T) {
+	db := sqlctest.Open(t)
+	kinds := map[string]base.Kind{"TEXT": base.Text, "INTEGER": base.Integer, "REAL": base.Real}
+	for _, e := range Registry {
+		t.Run(e.Table, func(t *testing.T) {
+			rows, err := db.Query(
+				`SELECT c.name, c.type, c."notnull", c.pk > 0, coalesce(f."table", '')
+				FROM pragma_table_info(?) AS c
+				LEFT JOIN pragma_foreign_key_list(?) AS f ON f."from" = c.name
+				ORDER BY c.cid`, e.Table, e.Table)
+			if err != nil {
+				t.Fatalf("failed to list columns: %v", err)
+			}
+			defer func() { _ = rows.Close() }()
+			var want []base.Column
+			for rows.Next() {
+				var (
+					c       base.Column
+					typ     string
+					notNull bool
+				)
+				if err := rows.Scan(&c.Name, &typ, &notNull, &c.Key, &c.Source); err != nil {
+					t.Fatalf("failed to scan column: %v", err)
+				}
+				c.Kind, c.Null = kinds[typ], !notNull
+				want = append(want, c)
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatalf("failed to list columns: %v", err)
+			}
+			var got []base.Column
+			for _, c := range e.Columns {
+				if c.Header == "" {
+					t.Errorf("column %s has no header", c.Name)
+				}
+				if c.Multiple && c.Source == "" {
+					t.Errorf("column %s has several values but references no table", c.Name)
+				}
+				if c.Input == base.Checkbox && c.Kind != base.Integer {
+					t.Errorf("checkbox column %s is not an integer", c.Name)
+				}
+				c.Header, c.Long, c.Check, c.Input, c.Multiple = "", false, nil, base.Auto, false
+				got = append(got, c)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("columns = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+// TestRegistry_Checks checks that the default data passes the checks of the
+// columns.
+func TestRegistry_Checks(t *testing.T) {
+	db := sqlctest.Open(t)
+	for _, e := range Registry {
+		for _, c := range e.Columns {
+			if c.Check == nil {
+				continue
+			}
+			rows, err := db.Query(fmt.Sprintf("SELECT %s FROM %s WHERE %[1]s IS NOT NULL",
+				schema.Quote(c.Name), schema.Quote(e.Table)))
+			if err != nil {
+				t.Fatalf("failed to select %s.%s: %v", e.Table, c.Name, err)
+			}
+			for rows.Next() {
+				var v string
+				if err := rows.Scan(&v); err != nil {
+					t.Fatalf("failed to scan %s.%s: %v", e.Table, c.Name, err)
+				}
+				if err := c.Check(v); err != nil {
+					t.Errorf("%s.%s %q: %v", e.Table, c.Name, v, err)
+				}
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatalf("failed to select %s.%s: %v", e.Table, c.Name, err)
+			}
+			_ = rows.Close()
+		}
+	}
+}
+
+// TestRegistry_Docs checks that the handler docs list the tables of the
+// registry.
+func TestRegistry_Docs(t *testing.T) {
+	var tables []string
+	for _, e := range Registry {
+		tables = append(tables, e.Table)
+	}
+	enums := regexp.MustCompile(`Enums\(([^)]*)\)`)
+	for _, file := range []string{"handlers.go", "api.go"} {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		matches := enums.FindAllSubmatch(src, -1)
+		if len(matches) == 0 {
+			t.Errorf("%s documents no tables", file)
+		}
+		for _, m := range matches {
+			if got := strings.Split(string(m[1]), ", "); !slices.Equal(got, tables) {
+				t.Errorf("%s documents tables %q, want %q", file, got, tables)
+			}
+		}
+	}
+}
+
+func TestTabs(t *testing.T) {
+	tabs := Tabs()
+	var titles [][]string
+	for _, row := range tabs {
+		var r []string
+		for _, tab := range row {
+			r = append(r, tab.Title)
+		}
+		titles = append(titles, r)
+	}
+	want := [][]string{
+		{"Lifts", "Movements", "Muscles", "Routines", "Workouts"},
+		{"Variables", "Lift Groups"},
+		{"Side Weight", "Progress", "Subworkouts"},
+		{"Routine:Workout", "Lift:Muscle", "Lift:Workout"},
+	}
+	if !reflect.DeepEqual(titles, want) {
+		t.Errorf("Tabs() titles = %q, want %q", titles, want)
+	}
+	if got := tabs[0][0].Endpoint; got != "/view/data/lift" {
+		t.Errorf("Tabs()[0][0].Endpoint = %q, want /view/data/lift", got)
+	}
+}
@@ -0,0 +1,24 @@
+package rawdata
+
+import (
+	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/service/rawdata/base"
+)
+
+// dataTable returns the table name whose changes are written to the write
+// database and recorded in the audit log, and whose rows are read from the
+// readonly database. Updates of progress are recorded in the change history
+// too, so they can be undone like those of the main tab.
+func dataTable(state *config.State, name string) base.Table {
+	table := base.Table{
+		Name:   name,
+		DB:     state.WPrepared,
+		ReadDB: state.RPrepared,
+		Audit:  state.Audit,
+		Search: state.Search,
+	}
+	if name == "progress" {
+		table.History = state.History
+	}
+	return table
+}
@@ -7,25 +7,43 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
-// Run starts the uplog service with the given configuration file path.
-func Run(ctx context.Context, configPath string) error {
+// Run starts the uplog service with the configuration resolved from src.
+func Run(ctx context.Context, src config.Source) error {
 	ctx, cancel := signal.NotifyContext(
 		ctx, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGHUP)
 	defer cancel()
 
-	cfg, err := config.Load(ctx, configPath)
+	cfg, origins, err := config.Resolve(ctx, src)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
 
+	level := new(slog.LevelVar)
+	slog.SetDefault(slog.New(telemetry.NewLogHandler(
+		slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))))
 	if cfg.Debug {
-		slog.SetLogLoggerLevel(slog.LevelDebug)
+		if cfg.DevFeatures() {
+			level.Set(slog.LevelDebug)
+		} else {
+			slog.WarnContext(ctx, "ignoring debug in mode", "mode", cfg.Mode)
+		}
+	}
+	if !cfg.DevFeatures() {
+		slog.InfoContext(ctx, "dev-only features such as /docs/ and /swagger/ are disabled", "mode", cfg.Mode)
 	}
 
 	state, err := config.NewState(ctx, cfg)
@@ -37,24 +55,50 @@ func Run(ctx context.Context, configPath string) error {
 			slog.WarnContext(ctx, "failed to close service state", "error", err)
 		}
 	}(ctx)
+	if err := registerTrainingMetrics(cfg, state); err != nil {
+		return err
+	}
+	otel.SetTracerProvider(state.TracerProvider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
 
 	srv := &http.Server{
-		Addr:    net.JoinHostPort("", cfg.Port),
-		Handler: NewServer(ctx, cfg, state),
+		Addr:              net.JoinHostPort("", cfg.Port),
+		Handler:           NewServer(ctx, cfg, state),
+		ReadHeaderTimeout: time.Duration(cfg.Server.ReadHeaderTimeoutSeconds) * time.Second,
+		IdleTimeout:       time.Duration(cfg.Server.IdleTimeoutSeconds) * time.Second,
 	}
+	drained := make(chan struct{})
 	go func(ctx context.Context) {
+		defer close(drained)
 		<-ctx.Done()
-		slog.InfoContext(ctx, "server context done, shutting down server")
-		if err := srv.Shutdown(ctx); err != nil {
-			slog.WarnContext(ctx, "failed to shutdown server", "error", err)
+		state.Draining.Store(true)
+		drain := time.Duration(cfg.Server.DrainTimeoutSeconds) * time.Second
+		slog.InfoContext(ctx, "server context done, draining requests", "timeout", drain)
+
+		// ctx is already done, so in-flight requests are given a fresh context
+		// to finish within.
+		shutdownCtx := context.WithoutCancel(ctx)
+		if drain > 0 {
+			var cancel context.CancelFunc
+			shutdownCtx, cancel = context.WithTimeout(shutdownCtx, drain)
+			defer cancel()
+		}
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			slog.WarnContext(ctx, "failed to drain requests, closing connections", "error", err)
+			if err := srv.Close(); err != nil {
+				slog.WarnContext(ctx, "failed to close server", "error", err)
+			}
 		}
 	}(ctx)
 
 	state.TLog.InfoContext(ctx, "start listening", "addr", srv.Addr)
 	state.JLog.InfoContext(ctx, "configuration", "cfg", cfg)
+	state.JLog.InfoContext(ctx, "configuration sources", "origins", origins)
 	err = srv.ListenAndServe()
-	if errors.Is(err, http.ErrServerClosed) {
-		return nil
+	if !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to listen and serve: %w", err)
 	}
-	return fmt.Errorf("failed to listen and serve: %w", err)
+	// Wait for in-flight requests before the deferred State.Close.
+	<-drained
+	return nil
 }
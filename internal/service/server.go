@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/RyRose/uplog/internal/config"
+	"github.com/RyRose/uplog/internal/service/mux"
 	sloghttp "github.com/samber/slog-http"
 	"github.com/slok/go-http-metrics/metrics/prometheus"
 	"github.com/slok/go-http-metrics/middleware"
@@ -13,10 +14,10 @@ import (
 )
 
 func NewServer(ctx context.Context, cfg *config.Data, state *config.State) http.Handler {
-	mux := http.NewServeMux()
-	AddRoutes(ctx, mux, cfg, state)
+	serveMux := http.NewServeMux()
+	AddRoutes(ctx, serveMux, cfg, state)
 
-	var handler http.Handler = mux
+	var handler http.Handler = serveMux
 
 	// Debug logging middleware.
 	if slog.Default().Enabled(ctx, slog.LevelDebug) {
@@ -24,8 +25,10 @@ func NewServer(ctx context.Context, cfg *config.Data, state *config.State) http.
 		handler = sloghttp.New(slog.Default())(handler)
 	}
 
-	// Prometheus metrics middleware.
-	// TODO: Replace with otelhttp.
+	// Reject writes while draining on shutdown.
+	handler = mux.Drain(&state.Draining, handler)
+
+	// Prometheus metrics middleware. Tracing is added per route by mux.Trace.
 	handler = std.Handler("", middleware.New(middleware.Config{
 		Recorder: prometheus.NewRecorder(prometheus.Config{Registry: state.PrometheusRegistry}),
 	}), handler)
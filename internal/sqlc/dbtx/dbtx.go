@@ -0,0 +1,232 @@
+// Package dbtx instruments the database handle passed to workoutdb.New with
+// traces and Prometheus metrics.
+package dbtx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/RyRose/uplog/internal/sqlc/dbtx"
+
+var (
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "uplog",
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Latency of database queries by sqlc query name.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 10),
+	}, []string{"query"})
+	queryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "uplog",
+		Subsystem: "db",
+		Name:      "query_errors_total",
+		Help:      "Failed database queries by sqlc query name. sql.ErrNoRows is not a failure.",
+	}, []string{"query"})
+)
+
+// Collectors returns the metrics recorded for queries run through any DB. They
+// are shared by all DBs, so the same collectors may be registered on several
+// registries.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{queryDuration, queryErrors}
+}
+
+// DBTX matches the interface generated by sqlc in workoutdb, so that *sql.DB,
+// *sql.Tx and *DB can all be passed to workoutdb.New.
+type DBTX interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+	PrepareContext(context.Context, string) (*sql.Stmt, error)
+	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}
+
+// Beginner is a DBTX that also begins transactions, such as *sql.DB or a DB
+// wrapping one.
+type Beginner interface {
+	DBTX
+	BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
+}
+
+// DB wraps a DBTX to record a span and metrics for each query.
+type DB struct {
+	DBTX
+
+	// prepared caches statements by query if non-nil.
+	prepared *sync.Map
+}
+
+// Wrap returns db with each query traced as a child of the span in the
+// query's context.
+func Wrap(db DBTX) *DB {
+	return &DB{DBTX: db}
+}
+
+// WrapPrepared is like Wrap but also prepares each sqlc query the first time
+// it is run and reuses the statement afterwards. Other queries, which may be
+// built for each request, are not prepared so that they do not pile up. The
+// statements must be released with Close. It is intended for long-lived
+// *sql.DB handles shared between requests rather than transactions.
+func WrapPrepared(db DBTX) *DB {
+	return &DB{DBTX: db, prepared: &sync.Map{}}
+}
+
+// Close closes the statements prepared by a DB created with WrapPrepared. The
+// wrapped DBTX is left open.
+func (db *DB) Close() error {
+	if db.prepared == nil {
+		return nil
+	}
+	var errs []error
+	db.prepared.Range(func(query, stmt any) bool {
+		db.prepared.Delete(query)
+		if err := stmt.(*sql.Stmt).Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close statement %s: %w", QueryName(query.(string)), err))
+		}
+		return true
+	})
+	return errors.Join(errs...)
+}
+
+// stmt returns the prepared statement for query, preparing it if needed, or
+// nil if db does not prepare statements or query is not a sqlc query.
+func (db *DB) stmt(ctx context.Context, query string) (*sql.Stmt, error) {
+	if db.prepared == nil || QueryName(query) == "" {
+		return nil, nil
+	}
+	if stmt, ok := db.prepared.Load(query); ok {
+		return stmt.(*sql.Stmt), nil
+	}
+	stmt, err := db.DBTX.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if existing, loaded := db.prepared.LoadOrStore(query, stmt); loaded {
+		// Another query prepared the statement concurrently.
+		_ = stmt.Close()
+		return existing.(*sql.Stmt), nil
+	}
+	return stmt, nil
+}
+
+// QueryName returns the name sqlc gives query in its leading "-- name: X :kind"
+// comment, or "" if query has none.
+func QueryName(query string) string {
+	rest, ok := strings.CutPrefix(query, "-- name: ")
+	if !ok {
+		return ""
+	}
+	name, _, _ := strings.Cut(rest, " ")
+	return name
+}
+
+// observation records the span and metrics of a single query.
+type observation struct {
+	span  trace.Span
+	label string
+	start time.Time
+}
+
+func start(ctx context.Context, query string) (context.Context, *observation) {
+	name := QueryName(query)
+	label := name
+	if label == "" {
+		label = "sql"
+	}
+	ctx, span := otel.Tracer(tracerName).Start(ctx, label,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNameSQLite,
+			semconv.DBOperationName(name),
+			semconv.DBQueryText(query),
+		))
+	return ctx, &observation{span: span, label: label, start: time.Now()}
+}
+
+func (o *observation) end(err error) {
+	queryDuration.WithLabelValues(o.label).Observe(time.Since(o.start).Seconds())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		queryErrors.WithLabelValues(o.label).Inc()
+		o.span.RecordError(err)
+		o.span.SetStatus(codes.Error, err.Error())
+	}
+	o.span.End()
+}
+
+// BeginTx begins a transaction on the wrapped DBTX if it is a Beginner. Wrap
+// the transaction to trace the queries run on it.
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	b, ok := db.DBTX.(Beginner)
+	if !ok {
+		return nil, fmt.Errorf("%T does not begin transactions", db.DBTX)
+	}
+	return b.BeginTx(ctx, opts)
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, o := start(ctx, query)
+	var res sql.Result
+	stmt, err := db.stmt(ctx, query)
+	switch {
+	case err != nil:
+	case stmt != nil:
+		res, err = stmt.ExecContext(ctx, args...)
+	default:
+		res, err = db.DBTX.ExecContext(ctx, query, args...)
+	}
+	if err == nil {
+		if n, rowsErr := res.RowsAffected(); rowsErr == nil {
+			o.span.SetAttributes(attribute.Int64("db.rows_affected", n))
+		}
+	}
+	o.end(err)
+	return res, err
+}
+
+func (db *DB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	ctx, o := start(ctx, query)
+	stmt, err := db.DBTX.PrepareContext(ctx, query)
+	o.end(err)
+	return stmt, err
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, o := start(ctx, query)
+	var rows *sql.Rows
+	stmt, err := db.stmt(ctx, query)
+	switch {
+	case err != nil:
+	case stmt != nil:
+		rows, err = stmt.QueryContext(ctx, args...)
+	default:
+		rows, err = db.DBTX.QueryContext(ctx, query, args...)
+	}
+	o.end(err)
+	return rows, err
+}
+
+// QueryRowContext falls back to an unprepared query if preparing fails since
+// *sql.Row cannot carry the error.
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, o := start(ctx, query)
+	var row *sql.Row
+	if stmt, err := db.stmt(ctx, query); err == nil && stmt != nil {
+		row = stmt.QueryRowContext(ctx, args...)
+	} else {
+		row = db.DBTX.QueryRowContext(ctx, query, args...)
+	}
+	o.end(row.Err())
+	return row
+}
@@ -0,0 +1,221 @@
+package dbtx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"-- name: GetLift :one\nSELECT * FROM lift WHERE id = ?", "GetLift"},
+		{"-- name: RawDeleteLift :exec\nDELETE FROM lift", "RawDeleteLift"},
+		{"SELECT 1", ""},
+	}
+	for _, tt := range tests {
+		if got := QueryName(tt.query); got != tt.want {
+			t.Errorf("QueryName(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestDB_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	sqlDB, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+	db := Wrap(sqlDB)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	if _, err := db.ExecContext(ctx, "-- name: CreateT :exec\nCREATE TABLE t (id INTEGER)"); err != nil {
+		t.Fatalf("ExecContext() error = %v", err)
+	}
+	var id int
+	err = db.QueryRowContext(ctx, "-- name: GetT :one\nSELECT id FROM t").Scan(&id)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("QueryRowContext() error = %v, want %v", err, sql.ErrNoRows)
+	}
+	if _, err := db.QueryContext(ctx, "-- name: ListMissing :many\nSELECT * FROM missing"); err == nil {
+		t.Fatal("QueryContext() expected error for missing table")
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("got %d spans, want 4", len(spans))
+	}
+	for i, want := range []string{"CreateT", "GetT", "ListMissing"} {
+		span := spans[i]
+		if span.Name() != want {
+			t.Errorf("span %d name = %q, want %q", i, span.Name(), want)
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s is not a child of the parent span", span.Name())
+		}
+	}
+	if got := spans[1].Status().Code; got != codes.Unset {
+		t.Errorf("GetT status = %v, want %v for no rows", got, codes.Unset)
+	}
+	if got := spans[2].Status().Code; got != codes.Error {
+		t.Errorf("ListMissing status = %v, want %v", got, codes.Error)
+	}
+}
+
+func TestDB_Metrics(t *testing.T) {
+	sqlDB, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+	db := Wrap(sqlDB)
+	ctx := context.Background()
+
+	for range 2 {
+		var n int
+		if err := db.QueryRowContext(ctx, "-- name: MetricsOne :one\nSELECT 1").Scan(&n); err != nil {
+			t.Fatalf("QueryRowContext() error = %v", err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, "-- name: MetricsFail :exec\nDELETE FROM missing"); err == nil {
+		t.Fatal("ExecContext() expected error for missing table")
+	}
+
+	if got := testutil.ToFloat64(queryErrors.WithLabelValues("MetricsOne")); got != 0 {
+		t.Errorf("MetricsOne errors = %v, want 0", got)
+	}
+	if got := testutil.ToFloat64(queryErrors.WithLabelValues("MetricsFail")); got != 1 {
+		t.Errorf("MetricsFail errors = %v, want 1", got)
+	}
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(Collectors()...)
+	count, err := testutil.GatherAndCount(reg, "uplog_db_query_duration_seconds")
+	if err != nil {
+		t.Fatalf("GatherAndCount() error = %v", err)
+	}
+	if count < 2 {
+		t.Errorf("got %d query duration series, want at least 2", count)
+	}
+}
+
+func openBenchDB(tb testing.TB) *sql.DB {
+	tb.Helper()
+	sqlDB, err := sql.Open("sqlite3", "file:"+tb.TempDir()+"/bench.db?_journal_mode=WAL")
+	if err != nil {
+		tb.Fatalf("failed to open database: %v", err)
+	}
+	tb.Cleanup(func() { _ = sqlDB.Close() })
+	for _, stmt := range []string{
+		"CREATE TABLE lift (id TEXT PRIMARY KEY NOT NULL, link TEXT NOT NULL, notes TEXT)",
+		"INSERT INTO lift (id, link) VALUES ('squat', 'https://example.com/squat')",
+	} {
+		if _, err := sqlDB.Exec(stmt); err != nil {
+			tb.Fatalf("failed to set up database: %v", err)
+		}
+	}
+	return sqlDB
+}
+
+const benchQuery = "-- name: GetLift :one\nSELECT id, link, notes FROM lift\nWHERE id = ?\nLIMIT 1"
+
+func TestWrapPrepared(t *testing.T) {
+	db := WrapPrepared(openBenchDB(t))
+	ctx := context.Background()
+	for range 3 {
+		var id, link string
+		var notes *string
+		if err := db.QueryRowContext(ctx, benchQuery, "squat").Scan(&id, &link, &notes); err != nil {
+			t.Fatalf("QueryRowContext() error = %v", err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, "-- name: UpdateNotes :exec\nUPDATE lift SET notes = ? WHERE id = ?", "deep", "squat"); err != nil {
+		t.Fatalf("ExecContext() error = %v", err)
+	}
+	rows, err := db.QueryContext(ctx, "-- name: ListNotes :many\nSELECT notes FROM lift")
+	if err != nil {
+		t.Fatalf("QueryContext() error = %v", err)
+	}
+	_ = rows.Close()
+	// Queries without a sqlc name are not prepared.
+	if _, err := db.ExecContext(ctx, "UPDATE lift SET notes = NULL"); err != nil {
+		t.Fatalf("ExecContext() unnamed error = %v", err)
+	}
+
+	n := 0
+	db.prepared.Range(func(_, _ any) bool { n++; return true })
+	if n != 3 {
+		t.Errorf("got %d prepared statements, want 3", n)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	n = 0
+	db.prepared.Range(func(_, _ any) bool { n++; return true })
+	if n != 0 {
+		t.Errorf("got %d prepared statements after Close, want 0", n)
+	}
+}
+
+func TestDB_BeginTx(t *testing.T) {
+	ctx := context.Background()
+	db := WrapPrepared(openBenchDB(t))
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginTx() error = %v", err)
+	}
+	if _, err := Wrap(tx).ExecContext(ctx, "UPDATE lift SET notes = 'deep' WHERE id = 'squat'"); err != nil {
+		t.Fatalf("ExecContext() in transaction error = %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	// Transactions cannot begin transactions.
+	if _, err := Wrap(tx).BeginTx(ctx, nil); err == nil {
+		t.Error("BeginTx() on a transaction succeeded, want error")
+	}
+}
+
+func benchmarkGetLift(b *testing.B, db DBTX) {
+	ctx := context.Background()
+	b.ResetTimer()
+	for range b.N {
+		var id, link string
+		var notes *string
+		if err := db.QueryRowContext(ctx, benchQuery, "squat").Scan(&id, &link, &notes); err != nil {
+			b.Fatalf("QueryRowContext() error = %v", err)
+		}
+	}
+}
+
+func BenchmarkGetLift_Unwrapped(b *testing.B) {
+	benchmarkGetLift(b, openBenchDB(b))
+}
+
+func BenchmarkGetLift_Wrap(b *testing.B) {
+	benchmarkGetLift(b, Wrap(openBenchDB(b)))
+}
+
+func BenchmarkGetLift_WrapPrepared(b *testing.B) {
+	db := WrapPrepared(openBenchDB(b))
+	defer func() { _ = db.Close() }()
+	benchmarkGetLift(b, db)
+}
@@ -0,0 +1,28 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+
+	"github.com/pressly/goose/v3"
+)
+
+// Migrate applies the embedded migrations to db. Unlike the goose functions
+// it sets no global state, so several databases can be migrated at once.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	migrations, err := fs.Sub(EmbedMigrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("failed to open embedded migrations: %w", err)
+	}
+	// The provider is not closed since that would close db.
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, migrations)
+	if err != nil {
+		return fmt.Errorf("failed to create migration provider: %w", err)
+	}
+	if _, err := provider.Up(ctx); err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	return nil
+}
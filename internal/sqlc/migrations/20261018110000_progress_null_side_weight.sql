@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- An empty side weight references no side_weight row, which fails the foreign
+-- key check now that foreign keys are enforced.
+UPDATE progress SET side_weight = NULL WHERE side_weight = '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- NULL is the only value an empty side weight is stored as, so it is kept.
+SELECT 1;
+-- +goose StatementEnd
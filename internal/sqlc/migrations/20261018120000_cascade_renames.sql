@@ -0,0 +1,187 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- SQLite cannot alter foreign keys, so every table with a foreign key is
+-- rebuilt to add ON UPDATE CASCADE. This propagates renames of the TEXT
+-- primary keys of reference data to the rows referencing them. See
+-- https://www.sqlite.org/lang_altertable.html#otheralter for the procedure.
+-- Migrations run with foreign keys disabled.
+
+CREATE TABLE progress_new (
+    id INTEGER PRIMARY KEY NOT NULL,
+    lift TEXT NOT NULL,
+    date TEXT NOT NULL CHECK (date LIKE '____-__-__'),
+    weight REAL NOT NULL CHECK (weight >= 0),
+    sets INTEGER NOT NULL CHECK (sets >= 0),
+    reps INTEGER NOT NULL CHECK (reps >= 0),
+    side_weight TEXT NULL,
+    FOREIGN KEY (lift) REFERENCES lift (id) ON UPDATE CASCADE,
+    FOREIGN KEY (side_weight) REFERENCES side_weight (id) ON UPDATE CASCADE
+);
+INSERT INTO progress_new SELECT id, lift, date, weight, sets, reps, side_weight FROM progress;
+DROP TABLE progress;
+ALTER TABLE progress_new RENAME TO progress;
+CREATE INDEX idx_progress_date ON progress (date);
+CREATE INDEX idx_progress_lift_date ON progress (lift, date DESC);
+
+CREATE TABLE routine_new (
+    id TEXT PRIMARY KEY NOT NULL,
+    steps TEXT NOT NULL,
+    lift TEXT NOT NULL,
+    FOREIGN KEY (lift) REFERENCES lift (id) ON UPDATE CASCADE
+);
+INSERT INTO routine_new SELECT id, steps, lift FROM routine;
+DROP TABLE routine;
+ALTER TABLE routine_new RENAME TO routine;
+
+CREATE TABLE routine_workout_mapping_new (
+    routine TEXT NOT NULL,
+    workout TEXT NOT NULL,
+    PRIMARY KEY (routine, workout),
+    FOREIGN KEY (routine) REFERENCES routine (id) ON UPDATE CASCADE,
+    FOREIGN KEY (workout) REFERENCES workout (id) ON UPDATE CASCADE
+);
+INSERT INTO routine_workout_mapping_new SELECT routine, workout FROM routine_workout_mapping;
+DROP TABLE routine_workout_mapping;
+ALTER TABLE routine_workout_mapping_new RENAME TO routine_workout_mapping;
+
+CREATE TABLE subworkout_new (
+    subworkout TEXT NOT NULL,
+    superworkout TEXT NOT NULL,
+    PRIMARY KEY (subworkout, superworkout),
+    FOREIGN KEY (subworkout) REFERENCES workout (id) ON UPDATE CASCADE,
+    FOREIGN KEY (superworkout) REFERENCES workout (id) ON UPDATE CASCADE
+);
+INSERT INTO subworkout_new SELECT subworkout, superworkout FROM subworkout;
+DROP TABLE subworkout;
+ALTER TABLE subworkout_new RENAME TO subworkout;
+
+CREATE TABLE lift_workout_mapping_new (
+    lift TEXT NOT NULL,
+    workout TEXT NOT NULL,
+    PRIMARY KEY (lift, workout),
+    FOREIGN KEY (lift) REFERENCES lift (id) ON UPDATE CASCADE,
+    FOREIGN KEY (workout) REFERENCES workout (id) ON UPDATE CASCADE
+);
+INSERT INTO lift_workout_mapping_new SELECT lift, workout FROM lift_workout_mapping;
+DROP TABLE lift_workout_mapping;
+ALTER TABLE lift_workout_mapping_new RENAME TO lift_workout_mapping;
+
+CREATE TABLE lift_new (
+    id TEXT PRIMARY KEY NOT NULL,
+    link TEXT NOT NULL,
+    default_side_weight TEXT,
+    notes TEXT,
+    lift_group TEXT,
+    FOREIGN KEY (default_side_weight) REFERENCES side_weight (id) ON UPDATE CASCADE,
+    FOREIGN KEY (lift_group) REFERENCES lift_group (id) ON UPDATE CASCADE
+);
+INSERT INTO lift_new SELECT id, link, default_side_weight, notes, lift_group FROM lift;
+DROP TABLE lift;
+ALTER TABLE lift_new RENAME TO lift;
+
+CREATE TABLE lift_muscle_mapping_new (
+    lift TEXT NOT NULL,
+    muscle TEXT NOT NULL,
+    movement TEXT NOT NULL,
+    PRIMARY KEY (lift, muscle, movement),
+    FOREIGN KEY (lift) REFERENCES lift (id) ON UPDATE CASCADE,
+    FOREIGN KEY (muscle) REFERENCES muscle (id) ON UPDATE CASCADE,
+    FOREIGN KEY (movement) REFERENCES movement (id) ON UPDATE CASCADE
+);
+INSERT INTO lift_muscle_mapping_new SELECT lift, muscle, movement FROM lift_muscle_mapping;
+DROP TABLE lift_muscle_mapping;
+ALTER TABLE lift_muscle_mapping_new RENAME TO lift_muscle_mapping;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE progress_old (
+    id INTEGER PRIMARY KEY NOT NULL,
+    lift TEXT NOT NULL,
+    date TEXT NOT NULL CHECK (date LIKE '____-__-__'),
+    weight REAL NOT NULL CHECK (weight >= 0),
+    sets INTEGER NOT NULL CHECK (sets >= 0),
+    reps INTEGER NOT NULL CHECK (reps >= 0),
+    side_weight TEXT NULL,
+    FOREIGN KEY (lift) REFERENCES lift (id),
+    FOREIGN KEY (side_weight) REFERENCES side_weight (id)
+);
+INSERT INTO progress_old SELECT id, lift, date, weight, sets, reps, side_weight FROM progress;
+DROP TABLE progress;
+ALTER TABLE progress_old RENAME TO progress;
+CREATE INDEX idx_progress_date ON progress (date);
+CREATE INDEX idx_progress_lift_date ON progress (lift, date DESC);
+
+CREATE TABLE routine_old (
+    id TEXT PRIMARY KEY NOT NULL,
+    steps TEXT NOT NULL,
+    lift TEXT NOT NULL,
+    FOREIGN KEY (lift) REFERENCES lift (id)
+);
+INSERT INTO routine_old SELECT id, steps, lift FROM routine;
+DROP TABLE routine;
+ALTER TABLE routine_old RENAME TO routine;
+
+CREATE TABLE routine_workout_mapping_old (
+    routine TEXT NOT NULL,
+    workout TEXT NOT NULL,
+    PRIMARY KEY (routine, workout),
+    FOREIGN KEY (routine) REFERENCES routine (id),
+    FOREIGN KEY (workout) REFERENCES workout (id)
+);
+INSERT INTO routine_workout_mapping_old SELECT routine, workout FROM routine_workout_mapping;
+DROP TABLE routine_workout_mapping;
+ALTER TABLE routine_workout_mapping_old RENAME TO routine_workout_mapping;
+
+CREATE TABLE subworkout_old (
+    subworkout TEXT NOT NULL,
+    superworkout TEXT NOT NULL,
+    PRIMARY KEY (subworkout, superworkout),
+    FOREIGN KEY (subworkout) REFERENCES workout (id),
+    FOREIGN KEY (superworkout) REFERENCES workout (id)
+);
+INSERT INTO subworkout_old SELECT subworkout, superworkout FROM subworkout;
+DROP TABLE subworkout;
+ALTER TABLE subworkout_old RENAME TO subworkout;
+
+CREATE TABLE lift_workout_mapping_old (
+    lift TEXT NOT NULL,
+    workout TEXT NOT NULL,
+    PRIMARY KEY (lift, workout),
+    FOREIGN KEY (lift) REFERENCES lift (id),
+    FOREIGN KEY (workout) REFERENCES workout (id)
+);
+INSERT INTO lift_workout_mapping_old SELECT lift, workout FROM lift_workout_mapping;
+DROP TABLE lift_workout_mapping;
+ALTER TABLE lift_workout_mapping_old RENAME TO lift_workout_mapping;
+
+CREATE TABLE lift_old (
+    id TEXT PRIMARY KEY NOT NULL,
+    link TEXT NOT NULL,
+    default_side_weight TEXT,
+    notes TEXT,
+    lift_group TEXT,
+    FOREIGN KEY (default_side_weight) REFERENCES side_weight (id),
+    FOREIGN KEY (lift_group) REFERENCES lift_group (id)
+);
+INSERT INTO lift_old SELECT id, link, default_side_weight, notes, lift_group FROM lift;
+DROP TABLE lift;
+ALTER TABLE lift_old RENAME TO lift;
+
+CREATE TABLE lift_muscle_mapping_old (
+    lift TEXT NOT NULL,
+    muscle TEXT NOT NULL,
+    movement TEXT NOT NULL,
+    PRIMARY KEY (lift, muscle, movement),
+    FOREIGN KEY (lift) REFERENCES lift (id),
+    FOREIGN KEY (muscle) REFERENCES muscle (id),
+    FOREIGN KEY (movement) REFERENCES movement (id)
+);
+INSERT INTO lift_muscle_mapping_old SELECT lift, muscle, movement FROM lift_muscle_mapping;
+DROP TABLE lift_muscle_mapping;
+ALTER TABLE lift_muscle_mapping_old RENAME TO lift_muscle_mapping;
+-- +goose StatementEnd
+
+-- sqlfluff:dialect:sqlite
+-- sqlfluff:rules:references.keywords:ignore_words:date
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Rows removed by a single delete of reference data, such as a lift along
+-- with its progress. Batches may be restored until they expire.
+CREATE TABLE trash_batch (
+    id INTEGER PRIMARY KEY NOT NULL,
+    -- The table and primary key of the row that was deleted.
+    source TEXT NOT NULL,
+    key TEXT NOT NULL,
+    -- When the rows were deleted as an RFC 3339 UTC timestamp.
+    deleted_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_trash_batch_deleted_at ON trash_batch (deleted_at);
+
+CREATE TABLE trash_row (
+    -- Rows are restored in the reverse order they were removed in.
+    id INTEGER PRIMARY KEY NOT NULL,
+    batch INTEGER NOT NULL,
+    -- The table the row belongs to.
+    source TEXT NOT NULL,
+    -- Either 'delete' if the row was deleted or 'clear' if a column of the row
+    -- referencing a deleted row was set to NULL.
+    action TEXT NOT NULL CHECK (action IN ('delete', 'clear')),
+    -- The column that was set to NULL for 'clear'.
+    cleared TEXT NULL,
+    -- The row before it was removed as a JSON object.
+    data TEXT NOT NULL,
+    FOREIGN KEY (batch) REFERENCES trash_batch (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_trash_row_batch ON trash_row (batch);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE trash_row;
+DROP TABLE trash_batch;
+-- +goose StatementEnd
+
+-- sqlfluff:dialect:sqlite
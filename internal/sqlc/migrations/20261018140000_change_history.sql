@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Recent changes of each session with the values needed to revert them. Only
+-- the most recent changes of a session are kept.
+CREATE TABLE change_history (
+    -- Changes are undone in the reverse order they were made in.
+    id INTEGER PRIMARY KEY NOT NULL,
+    -- The session that made the change.
+    session TEXT NOT NULL,
+    -- The table and key of the changed rows.
+    source TEXT NOT NULL,
+    key TEXT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('insert', 'update', 'delete')),
+    -- The changed rows as a JSON array of objects. Rows are recorded before
+    -- they are updated or deleted and after they are inserted.
+    data TEXT NOT NULL,
+    -- When the change was made as an RFC 3339 UTC timestamp.
+    changed_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_change_history_session ON change_history (session, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE change_history;
+-- +goose StatementEnd
+
+-- sqlfluff:dialect:sqlite
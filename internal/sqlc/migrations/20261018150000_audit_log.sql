@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Every field changed through the data tables, one row per field.
+CREATE TABLE audit_log (
+    id INTEGER PRIMARY KEY NOT NULL,
+    -- When the change was made as an RFC 3339 UTC timestamp.
+    changed_at TEXT NOT NULL,
+    -- The request that made the change and the user it was made on behalf of,
+    -- or empty if unknown.
+    request_id TEXT NOT NULL,
+    user TEXT NOT NULL,
+    -- The table and primary key of the changed row. Composite keys are joined
+    -- with '/'.
+    source TEXT NOT NULL,
+    key TEXT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('insert', 'update', 'delete')),
+    field TEXT NOT NULL,
+    -- The values before and after the change as text, NULL if absent.
+    old_value TEXT NULL,
+    new_value TEXT NULL
+);
+
+CREATE INDEX idx_audit_log_changed_at ON audit_log (changed_at);
+CREATE INDEX idx_audit_log_source ON audit_log (source, changed_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE audit_log;
+-- +goose StatementEnd
+
+-- sqlfluff:dialect:sqlite
@@ -75,6 +75,137 @@ INNER JOIN
 WHERE progress."date" = ?
 GROUP BY lift.lift_group;
 
+-- Lists the progress the training metrics are computed from, the most recent
+-- session of each lift and everything logged since the given date, with the
+-- lift group of the lift and what is needed to compute the full weight lifted.
+-- Progress without a side weight is lifted as is.
+-- name: ListTrainingProgress :many
+WITH latest AS (
+    SELECT
+        lift,
+        MAX("date") AS latest_date
+    FROM progress
+    GROUP BY lift
+)
+
+SELECT
+    progress.lift,
+    lift.lift_group,
+    progress."date",
+    progress.weight,
+    progress.sets,
+    progress.reps,
+    CAST(COALESCE(side_weight.multiplier, 1) AS REAL) AS multiplier,
+    CAST(COALESCE(side_weight.addend, 0) AS REAL) AS addend
+FROM
+    progress
+INNER JOIN
+    latest ON (progress.lift = latest.lift)
+INNER JOIN
+    lift ON (progress.lift = lift.id)
+LEFT JOIN
+    side_weight ON (progress.side_weight = side_weight.id)
+WHERE
+    progress."date" = latest.latest_date
+    OR progress."date" >= sqlc.arg(since);
+
+-- Lists the progress of a lift, most recent first, with its side weight and
+-- what is needed to compute the full weight lifted. Progress without a side
+-- weight is lifted as is.
+-- name: ListTrainingProgressForLift :many
+SELECT
+    progress.id,
+    progress."date",
+    progress.weight,
+    progress.sets,
+    progress.reps,
+    progress.side_weight,
+    CAST(COALESCE(side_weight.multiplier, 1) AS REAL) AS multiplier,
+    CAST(COALESCE(side_weight.addend, 0) AS REAL) AS addend
+FROM
+    progress
+LEFT JOIN
+    side_weight ON (progress.side_weight = side_weight.id)
+WHERE progress.lift = ?
+ORDER BY progress."date" DESC, progress.id DESC;
+
+-- name: ListMusclesForLift :many
+SELECT
+    muscle,
+    movement
+FROM lift_muscle_mapping
+WHERE lift = ?
+ORDER BY movement, muscle;
+
+-- name: ListRoutinesForLift :many
+SELECT *
+FROM routine
+WHERE lift = ?
+ORDER BY id;
+
+-- Lists the workouts that use a lift, whether mapped to it, to one of its
+-- routines, or containing such a workout as a subworkout at any depth. Each
+-- workout is listed once for each routine or subworkout it uses the lift via,
+-- which are empty if it is mapped to the lift.
+-- name: ListWorkoutsForLift :many
+WITH RECURSIVE uses AS (
+    SELECT
+        workout,
+        '' AS routine,
+        '' AS subworkout
+    FROM lift_workout_mapping
+    WHERE lift_workout_mapping.lift = sqlc.arg(lift)
+    UNION
+    SELECT
+        routine_workout_mapping.workout,
+        routine.id,
+        ''
+    FROM routine_workout_mapping
+    INNER JOIN routine ON (routine_workout_mapping.routine = routine.id)
+    WHERE routine.lift = sqlc.arg(lift)
+    UNION
+    SELECT
+        subworkout.superworkout,
+        '',
+        uses.workout
+    FROM subworkout
+    INNER JOIN uses ON (subworkout.subworkout = uses.workout)
+)
+
+SELECT DISTINCT
+    workout,
+    routine,
+    subworkout
+FROM uses
+ORDER BY workout, routine, subworkout;
+
+-- name: GetWorkout :one
+SELECT * FROM workout
+WHERE id = ?
+LIMIT 1;
+
+-- name: ListTemplateVariables :many
+SELECT *
+FROM template_variable;
+
+-- name: ListLiftsForWorkout :many
+SELECT lift.*
+FROM lift_workout_mapping
+INNER JOIN lift ON (lift_workout_mapping.lift = lift.id)
+WHERE lift_workout_mapping.workout = ?
+ORDER BY lift.id;
+
+-- name: ListRoutinesForWorkout :many
+SELECT routine.*
+FROM routine_workout_mapping
+INNER JOIN routine ON (routine_workout_mapping.routine = routine.id)
+WHERE routine_workout_mapping.workout = ?
+ORDER BY routine.id;
+
+-- name: ListSubworkouts :many
+SELECT *
+FROM subworkout;
+
 -----------------------
 -- sqlfluff settings --
 -----------------------
@@ -0,0 +1,63 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddNamedMigrationContext("20261018160000_search.go", upSearch, downSearch)
+}
+
+// searchPrefix starts the names of the triggers keeping the full-text tables
+// up to date.
+const searchPrefix = "search_"
+
+// searchTables are the data tables that had full-text tables created at
+// startup by earlier versions.
+var searchTables = []string{
+	"lift",
+	"lift_group",
+	"lift_muscle_mapping",
+	"lift_workout_mapping",
+	"movement",
+	"muscle",
+	"routine",
+	"routine_workout_mapping",
+	"side_weight",
+	"subworkout",
+	"template_variable",
+	"workout",
+}
+
+// upSearch drops the triggers keeping the full-text tables up to date. SQLite
+// built without FTS5 cannot run them, so migrations writing to the data tables
+// would fail. The full-text tables and their triggers are created again when
+// the search index is loaded by a build with FTS5.
+func upSearch(ctx context.Context, tx *sql.Tx) error {
+	return dropSearchTriggers(ctx, tx)
+}
+
+// downSearch drops the triggers keeping the full-text tables up to date.
+func downSearch(ctx context.Context, tx *sql.Tx) error {
+	return dropSearchTriggers(ctx, tx)
+}
+
+func dropSearchTriggers(ctx context.Context, tx *sql.Tx) error {
+	for _, table := range searchTables {
+		name := searchPrefix + table
+		for _, stmt := range []string{
+			"DROP TRIGGER IF EXISTS " + name + "_insert",
+			"DROP TRIGGER IF EXISTS " + name + "_delete",
+			"DROP TRIGGER IF EXISTS " + name + "_update",
+		} {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("failed to drop triggers of %s: %w", table, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+// Package sqlctest provides migrated databases to tests.
+package sqlctest
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/RyRose/uplog/internal/sqlc"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Open returns a database in a temporary file migrated with the default data.
+// Foreign keys are enforced once migrated but not while migrating, as when
+// the app starts. The database has a single connection so that every query of
+// a test sees the same connection settings and is closed when the test ends.
+func Open(t testing.TB) *sql.DB {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.db")
+	mdb, err := sql.Open("sqlite3", "file:"+path+"?_foreign_keys=false")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() { _ = mdb.Close() }()
+	mdb.SetMaxOpenConns(1)
+	if err := sqlc.Migrate(context.Background(), mdb); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?_foreign_keys=true")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	db.SetMaxOpenConns(1)
+	return db
+}
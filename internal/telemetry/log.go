@@ -0,0 +1,38 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LogHandler is a slog.Handler that adds the trace_id and span_id of the span
+// in the record's context to each record.
+type LogHandler struct {
+	slog.Handler
+}
+
+// NewLogHandler wraps h to add trace and span IDs to records.
+func NewLogHandler(h slog.Handler) *LogHandler {
+	return &LogHandler{Handler: h}
+}
+
+func (h *LogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r = r.Clone()
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{Handler: h.Handler.WithGroup(name)}
+}
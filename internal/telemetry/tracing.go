@@ -0,0 +1,138 @@
+// Package telemetry sets up OpenTelemetry tracing and ties it into logging.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// ServiceName is the service.name resource attribute of exported spans.
+const ServiceName = "uplog"
+
+// Exporters that may be passed to NewTracerProvider.
+const (
+	ExporterNone     = "none"
+	ExporterStdout   = "stdout"
+	ExporterOTLPFile = "otlpfile"
+)
+
+// TracerOptions configures NewTracerProvider.
+type TracerOptions struct {
+	// Exporter is one of the Exporter constants. Empty means ExporterNone.
+	Exporter string
+	// Path is the file ExporterOTLPFile appends to.
+	Path string
+	// Version is the service.version resource attribute of exported spans.
+	Version string
+}
+
+// NewTracerProvider returns a tracer provider exporting spans as configured by
+// opts. With ExporterNone spans are still created, so trace IDs are available
+// for logs, but they are not exported.
+func NewTracerProvider(ctx context.Context, opts TracerOptions) (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(opts.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+	tpOpts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	switch opts.Exporter {
+	case "", ExporterNone:
+	case ExporterStdout:
+		exporter, err := stdouttrace.New()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		tpOpts = append(tpOpts, sdktrace.WithBatcher(exporter))
+	case ExporterOTLPFile:
+		exporter, err := otlptrace.New(ctx, &fileClient{path: opts.Path})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create otlp file exporter: %w", err)
+		}
+		tpOpts = append(tpOpts, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", opts.Exporter)
+	}
+	return sdktrace.NewTracerProvider(tpOpts...), nil
+}
+
+// fileClient is an otlptrace.Client that appends each export request to a
+// file as a line of OTLP JSON, the format read by the OpenTelemetry
+// Collector's otlpjsonfile receiver.
+type fileClient struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+var _ otlptrace.Client = (*fileClient)(nil)
+
+func (c *fileClient) Start(_ context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directories: %w", err)
+	}
+	f, err := os.OpenFile(c.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", c.path, err)
+	}
+	c.file = f
+	return nil
+}
+
+func (c *fileClient) Stop(_ context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	if err != nil {
+		return fmt.Errorf("failed to close %s: %w", c.path, err)
+	}
+	return nil
+}
+
+func (c *fileClient) UploadTraces(_ context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	b, err := protojson.Marshal(&collectorpb.ExportTraceServiceRequest{ResourceSpans: protoSpans})
+	if err != nil {
+		return fmt.Errorf("failed to marshal spans: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return fmt.Errorf("%s is not open", c.path)
+	}
+	if _, err := c.file.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("failed to write spans: %w", err)
+	}
+	return nil
+}
+
+// TraceID returns the hex trace ID of the span in ctx, or "" if there is none.
+func TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
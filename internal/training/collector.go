@@ -0,0 +1,103 @@
+package training
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	e1rmDesc = prometheus.NewDesc(
+		"uplog_training_e1rm",
+		"Best estimated one-rep max of the most recent session of a lift.",
+		[]string{"lift", "lift_group"}, nil)
+	topWeightDesc = prometheus.NewDesc(
+		"uplog_training_top_weight",
+		"Heaviest weight lifted in the most recent session of a lift.",
+		[]string{"lift", "lift_group"}, nil)
+	volumeDesc = prometheus.NewDesc(
+		"uplog_training_volume_7d",
+		"Weight × sets × reps of a lift over the last 7 days.",
+		[]string{"lift", "lift_group"}, nil)
+	groupVolumeDesc = prometheus.NewDesc(
+		"uplog_training_lift_group_volume_7d",
+		"Weight × sets × reps of all lifts in a lift group over the last 7 days.",
+		[]string{"lift_group"}, nil)
+)
+
+// Collector is a prometheus.Collector exporting the summary of each lift.
+// Summaries are cached so that frequent scrapes do not reload progress.
+type Collector struct {
+	load    func(ctx context.Context, since string) ([]Entry, error)
+	ttl     time.Duration
+	timeout time.Duration
+	now     func() time.Time
+
+	mu       sync.Mutex
+	loadedAt time.Time
+	metrics  []prometheus.Metric
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector returns a Collector that loads progress entries with load at
+// most once every ttl. load is given the first date of the volume window and
+// must return the entries Summarize needs: the most recent session of each
+// lift and everything logged since that date.
+func NewCollector(load func(ctx context.Context, since string) ([]Entry, error), ttl time.Duration) *Collector {
+	return &Collector{
+		load:    load,
+		ttl:     ttl,
+		timeout: 10 * time.Second,
+		now:     time.Now,
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e1rmDesc
+	ch <- topWeightDesc
+	ch <- volumeDesc
+	ch <- groupVolumeDesc
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if c.metrics == nil || now.Sub(c.loadedAt) >= c.ttl {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		defer cancel()
+		entries, err := c.load(ctx, Since(now))
+		if err != nil {
+			// Serve the previous metrics rather than failing the whole scrape.
+			slog.WarnContext(ctx, "failed to load training metrics", "error", err)
+		} else {
+			c.metrics = metrics(Summarize(entries, now))
+			c.loadedAt = now
+		}
+	}
+	for _, m := range c.metrics {
+		ch <- m
+	}
+}
+
+func metrics(summaries []LiftSummary) []prometheus.Metric {
+	ms := make([]prometheus.Metric, 0, 3*len(summaries))
+	groups := make(map[string]float64)
+	for _, s := range summaries {
+		ms = append(ms,
+			prometheus.MustNewConstMetric(e1rmDesc, prometheus.GaugeValue, s.E1RM, s.Lift, s.LiftGroup),
+			prometheus.MustNewConstMetric(topWeightDesc, prometheus.GaugeValue, s.TopWeight, s.Lift, s.LiftGroup),
+			prometheus.MustNewConstMetric(volumeDesc, prometheus.GaugeValue, s.Volume, s.Lift, s.LiftGroup),
+		)
+		groups[s.LiftGroup] += s.Volume
+	}
+	for group, volume := range groups {
+		ms = append(ms, prometheus.MustNewConstMetric(groupVolumeDesc, prometheus.GaugeValue, volume, group))
+	}
+	return ms
+}
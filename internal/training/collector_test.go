@@ -0,0 +1,67 @@
+package training
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	loads := 0
+	var loadErr error
+	now := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	c := NewCollector(func(_ context.Context, since string) ([]Entry, error) {
+		loads++
+		if want := Since(now); since != want {
+			t.Errorf("load since %s, want %s", since, want)
+		}
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		return []Entry{
+			{Lift: "squat", LiftGroup: "lower", Date: "2025-03-10", Weight: 100, Sets: 2, Reps: 3},
+			{Lift: "lunge", LiftGroup: "lower", Date: "2025-03-10", Weight: 50, Sets: 1, Reps: 1},
+		}, nil
+	}, time.Minute)
+	c.now = func() time.Time { return now }
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(c)
+
+	want := `
+# HELP uplog_training_lift_group_volume_7d Weight × sets × reps of all lifts in a lift group over the last 7 days.
+# TYPE uplog_training_lift_group_volume_7d gauge
+uplog_training_lift_group_volume_7d{lift_group="lower"} 650
+# HELP uplog_training_top_weight Heaviest weight lifted in the most recent session of a lift.
+# TYPE uplog_training_top_weight gauge
+uplog_training_top_weight{lift="lunge",lift_group="lower"} 50
+uplog_training_top_weight{lift="squat",lift_group="lower"} 100
+`
+	names := []string{"uplog_training_lift_group_volume_7d", "uplog_training_top_weight"}
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), names...); err != nil {
+		t.Fatal(err)
+	}
+
+	// Scrapes within the TTL are served from the cache.
+	if _, err := reg.Gather(); err != nil {
+		t.Fatal(err)
+	}
+	if loads != 1 {
+		t.Errorf("got %d loads within TTL, want 1", loads)
+	}
+
+	// Failed loads serve the previous metrics.
+	now = now.Add(time.Minute)
+	loadErr = errors.New("database is locked")
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), names...); err != nil {
+		t.Fatal(err)
+	}
+	if loads != 2 {
+		t.Errorf("got %d loads after TTL, want 2", loads)
+	}
+}
@@ -0,0 +1,128 @@
+// Package training computes lifting progress statistics and exports them as
+// Prometheus metrics.
+package training
+
+import (
+	"sort"
+	"time"
+)
+
+// VolumeWindow is the number of days, including today, that weekly volume is
+// summed over.
+const VolumeWindow = 7
+
+// Entry is a single logged progress entry.
+type Entry struct {
+	Lift string
+	// LiftGroup is the group of the lift, or "" if it has none.
+	LiftGroup string
+	// Date is formatted as YYYY-MM-DD.
+	Date string
+	// Weight is the full weight lifted, with the side weight applied.
+	Weight float64
+	Sets   int64
+	Reps   int64
+}
+
+// LiftSummary holds the statistics of a single lift.
+type LiftSummary struct {
+	Lift      string
+	LiftGroup string
+	// E1RM is the best estimated one-rep max of the most recent session.
+	E1RM float64
+	// TopWeight is the heaviest weight lifted in the most recent session.
+	TopWeight float64
+	// Volume is the total weight moved, weight × sets × reps, over the last
+	// VolumeWindow days.
+	Volume float64
+}
+
+// E1RM estimates the one-rep max of lifting weight for reps using the Epley
+// formula.
+func E1RM(weight float64, reps int64) float64 {
+	if reps <= 1 {
+		return weight
+	}
+	return weight * (1 + float64(reps)/30)
+}
+
+// Since returns the first date, formatted as YYYY-MM-DD, of the VolumeWindow
+// days ending now.
+func Since(now time.Time) string {
+	return now.AddDate(0, 0, -(VolumeWindow - 1)).Format(time.DateOnly)
+}
+
+// Summarize computes a summary of each lift in entries as of now. Summaries
+// are sorted by lift. Entries must include the most recent session of each
+// lift and everything logged since Since(now); older entries are ignored.
+func Summarize(entries []Entry, now time.Time) []LiftSummary {
+	since := Since(now)
+	today := now.Format(time.DateOnly)
+
+	latest := make(map[string]string)
+	for _, e := range entries {
+		if e.Date > latest[e.Lift] {
+			latest[e.Lift] = e.Date
+		}
+	}
+
+	summaries := make(map[string]*LiftSummary)
+	for _, e := range entries {
+		s, ok := summaries[e.Lift]
+		if !ok {
+			s = &LiftSummary{Lift: e.Lift, LiftGroup: e.LiftGroup}
+			summaries[e.Lift] = s
+		}
+		if e.Date == latest[e.Lift] && e.Sets > 0 && e.Reps > 0 {
+			s.TopWeight = max(s.TopWeight, e.Weight)
+			s.E1RM = max(s.E1RM, E1RM(e.Weight, e.Reps))
+		}
+		if e.Date >= since && e.Date <= today {
+			s.Volume += e.Weight * float64(e.Sets*e.Reps)
+		}
+	}
+
+	result := make([]LiftSummary, 0, len(summaries))
+	for _, s := range summaries {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Lift < result[j].Lift })
+	return result
+}
+
+// Record is the heaviest weight lifted for a number of reps.
+type Record struct {
+	Reps   int64
+	Weight float64
+	// Date is the first date Weight was lifted for Reps.
+	Date string
+	// E1RM is the estimated one-rep max of lifting Weight for Reps.
+	E1RM float64
+}
+
+// Records returns the record of each number of reps lifted in entries, which
+// are of a single lift, sorted by reps. Entries without sets or reps are not
+// lifted.
+func Records(entries []Entry) []Record {
+	records := make(map[int64]*Record)
+	for _, e := range entries {
+		if e.Sets <= 0 || e.Reps <= 0 {
+			continue
+		}
+		r, ok := records[e.Reps]
+		switch {
+		case !ok:
+			records[e.Reps] = &Record{Reps: e.Reps, Weight: e.Weight, Date: e.Date}
+		case e.Weight > r.Weight, e.Weight == r.Weight && e.Date < r.Date:
+			r.Weight, r.Date = e.Weight, e.Date
+		}
+	}
+
+	result := make([]Record, 0, len(records))
+	for _, r := range records {
+		r.E1RM = E1RM(r.Weight, r.Reps)
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Reps < result[j].Reps })
+	return result
+}
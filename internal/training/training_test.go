@@ -0,0 +1,89 @@
+package training
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestE1RM(t *testing.T) {
+	tests := []struct {
+		weight float64
+		reps   int64
+		want   float64
+	}{
+		{100, 1, 100},
+		{100, 0, 100},
+		{100, 3, 110},
+		{150, 10, 200},
+	}
+	for _, tt := range tests {
+		if got := E1RM(tt.weight, tt.reps); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("E1RM(%v, %v) = %v, want %v", tt.weight, tt.reps, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		// Outside of the volume window but the most recent session.
+		{Lift: "deadlift", LiftGroup: "lower", Date: "2025-03-01", Weight: 300, Sets: 1, Reps: 5},
+		{Lift: "deadlift", LiftGroup: "lower", Date: "2025-02-20", Weight: 400, Sets: 1, Reps: 1},
+		{Lift: "squat", LiftGroup: "lower", Date: "2025-03-04", Weight: 200, Sets: 3, Reps: 5},
+		{Lift: "squat", LiftGroup: "lower", Date: "2025-03-10", Weight: 225, Sets: 1, Reps: 3},
+		{Lift: "squat", LiftGroup: "lower", Date: "2025-03-10", Weight: 210, Sets: 2, Reps: 6},
+		{Lift: "squat", LiftGroup: "lower", Date: "2025-03-03", Weight: 500, Sets: 1, Reps: 1},
+		{Lift: "curl", Date: "2025-03-09", Weight: 30, Sets: 0, Reps: 10},
+	}
+
+	got := Summarize(entries, now)
+	want := []LiftSummary{
+		{Lift: "curl"},
+		{Lift: "deadlift", LiftGroup: "lower", E1RM: 350, TopWeight: 300},
+		{
+			Lift:      "squat",
+			LiftGroup: "lower",
+			E1RM:      252,
+			TopWeight: 225,
+			Volume:    200*15 + 225*3 + 210*12,
+		},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Summarize() returned %d summaries, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Lift != w.Lift || g.LiftGroup != w.LiftGroup ||
+			math.Abs(g.E1RM-w.E1RM) > 1e-9 || g.TopWeight != w.TopWeight || g.Volume != w.Volume {
+			t.Errorf("Summarize()[%d] = %+v, want %+v", i, g, w)
+		}
+	}
+}
+
+func TestRecords(t *testing.T) {
+	entries := []Entry{
+		{Date: "2025-03-04", Weight: 200, Sets: 3, Reps: 5},
+		{Date: "2025-03-10", Weight: 210, Sets: 1, Reps: 5},
+		// Lifting the record weight again does not move its date.
+		{Date: "2025-03-12", Weight: 210, Sets: 2, Reps: 5},
+		{Date: "2025-03-01", Weight: 210, Sets: 1, Reps: 5},
+		{Date: "2025-03-03", Weight: 250, Sets: 1, Reps: 1},
+		{Date: "2025-03-05", Weight: 300, Sets: 0, Reps: 1},
+		{Date: "2025-03-06", Weight: 300, Sets: 1, Reps: 0},
+	}
+	got := Records(entries)
+	want := []Record{
+		{Reps: 1, Weight: 250, Date: "2025-03-03", E1RM: 250},
+		{Reps: 5, Weight: 210, Date: "2025-03-01", E1RM: 245},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Records() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if g, w := got[i], want[i]; g.Reps != w.Reps || g.Weight != w.Weight || g.Date != w.Date ||
+			math.Abs(g.E1RM-w.E1RM) > 1e-9 {
+			t.Errorf("Records()[%d] = %+v, want %+v", i, g, w)
+		}
+	}
+}
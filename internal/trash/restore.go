@@ -0,0 +1,170 @@
+package trash
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/RyRose/uplog/internal/schema"
+)
+
+// Purge deletes the batches that have expired and returns how many there were.
+func (b *Bin) Purge(ctx context.Context) (int64, error) {
+	if b.retention <= 0 {
+		return 0, nil
+	}
+	cutoff := b.now().Add(-b.retention).UTC().Format(time.RFC3339)
+	result, err := b.db.ExecContext(ctx, "DELETE FROM trash_batch WHERE deleted_at < ?", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// List purges expired batches and returns the rest, most recent first.
+func (b *Bin) List(ctx context.Context) ([]Batch, error) {
+	if _, err := b.Purge(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := b.db.QueryContext(ctx,
+		`SELECT b.id, b.source, b.key, b.deleted_at, COUNT(r.id)
+		FROM trash_batch AS b
+		LEFT JOIN trash_row AS r ON (r.batch = b.id)
+		GROUP BY b.id
+		ORDER BY b.id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trash: %w", err)
+	}
+	var batches []Batch
+	for rows.Next() {
+		var (
+			batch     Batch
+			deletedAt string
+		)
+		if err := rows.Scan(&batch.ID, &batch.Table, &batch.Key, &deletedAt, &batch.Rows); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to scan trash batch: %w", err), rows.Close())
+		}
+		batch.DeletedAt, err = time.Parse(time.RFC3339, deletedAt)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to parse deletion time of batch %d: %w", batch.ID, err), rows.Close())
+		}
+		if b.retention > 0 {
+			batch.ExpiresAt = batch.DeletedAt.Add(b.retention)
+		}
+		batches = append(batches, batch)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("failed to list trash: %w", err)
+	}
+	return batches, nil
+}
+
+// Restore puts the rows of batch back in the order opposite to how they were
+// removed, so rows are restored before the rows referencing them, and then
+// deletes the batch. Every restored row is passed to observe unless it is nil.
+// It returns the number of rows restored.
+func (b *Bin) Restore(ctx context.Context, batch int64, observe Observer) (int64, error) {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	type removed struct {
+		source, action, data string
+		cleared              sql.NullString
+	}
+	rows, err := tx.QueryContext(ctx,
+		"SELECT source, action, cleared, data FROM trash_row WHERE batch = ? ORDER BY id DESC", batch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to select trash batch %d: %w", batch, err)
+	}
+	var all []removed
+	for rows.Next() {
+		var r removed
+		if err := rows.Scan(&r.source, &r.action, &r.cleared, &r.data); err != nil {
+			return 0, errors.Join(fmt.Errorf("failed to scan trash row: %w", err), rows.Close())
+		}
+		all = append(all, r)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return 0, fmt.Errorf("failed to select trash batch %d: %w", batch, err)
+	}
+
+	for _, r := range all {
+		columns, primaryKey, err := schema.Columns(ctx, tx, r.source)
+		if err != nil {
+			return 0, err
+		}
+		var before string
+		switch Action(r.action) {
+		case Deleted:
+			var values []string
+			for _, c := range columns {
+				values = append(values, schema.Extract(c))
+			}
+			quoted := make([]string, len(columns))
+			for i, c := range columns {
+				quoted[i] = schema.Quote(c)
+			}
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (%s) SELECT %s",
+				schema.Quote(r.source), strings.Join(quoted, ", "), strings.Join(values, ", ")),
+				repeat(r.data, len(columns))...,
+			); err != nil {
+				return 0, fmt.Errorf("failed to restore row of %s: %w", r.source, err)
+			}
+		case Cleared:
+			var where []string
+			for _, c := range primaryKey {
+				where = append(where, fmt.Sprintf("%s = %s", schema.Quote(c), schema.Extract(c)))
+			}
+			if len(where) == 0 || !r.cleared.Valid {
+				return 0, fmt.Errorf("cannot restore cleared row of %s", r.source)
+			}
+			if err := tx.QueryRowContext(ctx, "SELECT json_set(?, ?, NULL)",
+				r.data, `$."`+strings.ReplaceAll(r.cleared.String, `"`, `\"`)+`"`,
+			).Scan(&before); err != nil {
+				return 0, fmt.Errorf("failed to clear %s.%s of trash row: %w", r.source, r.cleared.String, err)
+			}
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s",
+				schema.Quote(r.source), schema.Quote(r.cleared.String), schema.Extract(r.cleared.String),
+				strings.Join(where, " AND ")),
+				repeat(r.data, 1+len(primaryKey))...,
+			); err != nil {
+				return 0, fmt.Errorf("failed to restore %s.%s: %w", r.source, r.cleared.String, err)
+			}
+		default:
+			return 0, fmt.Errorf("unknown action %q", r.action)
+		}
+		if observe != nil {
+			if err := observe(ctx, tx, r.source, before, r.data); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM trash_batch WHERE id = ?", batch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete trash batch %d: %w", batch, err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return 0, fmt.Errorf("failed to delete trash batch %d: %w", batch, err)
+	} else if n == 0 {
+		return 0, fmt.Errorf("trash batch %d: %w", batch, ErrNotFound)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return int64(len(all)), nil
+}
+
+func repeat(v any, n int) []any {
+	args := make([]any, n)
+	for i := range args {
+		args[i] = v
+	}
+	return args
+}
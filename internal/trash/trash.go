@@ -0,0 +1,316 @@
+// Package trash deletes reference data along with the rows depending on it
+// and keeps the removed rows so they can be restored until they expire.
+package trash
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RyRose/uplog/internal/schema"
+	"github.com/RyRose/uplog/internal/sqlc/dbtx"
+)
+
+// Action is how a row was removed.
+type Action string
+
+const (
+	// Deleted rows were deleted.
+	Deleted Action = "delete"
+	// Cleared rows had a column referencing a deleted row set to NULL.
+	Cleared Action = "clear"
+)
+
+// Change is the number of rows of a table removed by a delete.
+type Change struct {
+	Table string
+	// Column is the column set to NULL for Cleared rows.
+	Column string
+	Action Action
+	Count  int64
+}
+
+// BlockedError is returned by Bin.Delete when other rows depend on the row
+// and the delete does not cascade. Changes describes what a cascading delete
+// would remove.
+type BlockedError struct {
+	Changes []Change
+}
+
+func (e *BlockedError) Error() string {
+	var parts []string
+	for _, c := range e.Changes {
+		parts = append(parts, fmt.Sprintf("%d %s", c.Count, c.Table))
+	}
+	return "row is referenced by " + strings.Join(parts, ", ")
+}
+
+// ErrNotFound is returned when the row or batch to remove or restore does
+// not exist.
+var ErrNotFound = errors.New("not found")
+
+// Batch is a group of rows removed by a single delete.
+type Batch struct {
+	ID int64
+	// Table and Key identify the row that was deleted.
+	Table, Key string
+	DeletedAt  time.Time
+	// ExpiresAt is when the batch is purged, or zero if it never is.
+	ExpiresAt time.Time
+	// Rows is the number of rows removed.
+	Rows int64
+}
+
+// Bin deletes rows into the trash and restores them.
+type Bin struct {
+	db        dbtx.Beginner
+	retention time.Duration
+	now       func() time.Time
+}
+
+// New returns a Bin storing removed rows in db for retention, or forever if
+// retention is zero.
+func New(db dbtx.Beginner, retention time.Duration) *Bin {
+	return &Bin{db: db, retention: retention, now: time.Now}
+}
+
+// Observer runs in the transaction of a delete or restore for each row of
+// table it changes, with the row before and after the change as JSON objects.
+// before is empty for restored rows and after for deleted rows. The change is
+// rolled back if it fails.
+type Observer func(ctx context.Context, tx *sql.Tx, table, before, after string) error
+
+// Delete moves the rows of table whose column is value to the trash and
+// returns the batch holding them. Rows referencing them are also deleted, or
+// cleared if the reference is nullable, when cascade is set. Otherwise a
+// *BlockedError is returned if there are any. Every removed or cleared row is
+// passed to observe unless it is nil.
+func (b *Bin) Delete(ctx context.Context, table, column, value string, cascade bool, observe Observer) (int64, error) {
+	if _, err := b.Purge(ctx); err != nil {
+		return 0, err
+	}
+
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	columns, _, err := schema.Columns(ctx, tx, table)
+	if err != nil {
+		return 0, err
+	}
+	if !slices.Contains(columns, column) {
+		return 0, fmt.Errorf("no such column: %s.%s", table, column)
+	}
+	var batch int64
+	if err := tx.QueryRowContext(ctx,
+		"INSERT INTO trash_batch (source, key, deleted_at) VALUES (?, ?, ?) RETURNING id",
+		table, value, b.now().UTC().Format(time.RFC3339),
+	).Scan(&batch); err != nil {
+		return 0, fmt.Errorf("failed to create trash batch: %w", err)
+	}
+	r := &remover{tx: tx, batch: batch, counts: make(map[Change]int64), observe: observe}
+	n, err := r.remove(ctx, table, column, value)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("%s %q: %w", table, value, ErrNotFound)
+	}
+	if changes := r.changes(table, n); !cascade && len(changes) > 0 {
+		return 0, &BlockedError{Changes: changes}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return batch, nil
+}
+
+// remover removes rows within a transaction, recording them in a batch.
+type remover struct {
+	tx      *sql.Tx
+	batch   int64
+	counts  map[Change]int64
+	observe Observer
+}
+
+// remove deletes the rows of table whose column is value after removing the
+// rows referencing them. It returns the number of rows deleted.
+func (r *remover) remove(ctx context.Context, table, column string, value any) (int64, error) {
+	refs, err := schema.References(ctx, r.tx, table)
+	if err != nil {
+		return 0, err
+	}
+	for _, ref := range refs {
+		values, err := r.distinct(ctx, table, ref.ParentColumn, column, value)
+		if err != nil {
+			return 0, err
+		}
+		for _, v := range values {
+			if ref.Nullable {
+				err = r.clear(ctx, ref.Table, ref.Column, v)
+			} else {
+				_, err = r.remove(ctx, ref.Table, ref.Column, v)
+			}
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	n, err := r.record(ctx, Deleted, table, "", column, value)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.observeRows(ctx, table, "", column, value); err != nil {
+		return 0, err
+	}
+	if _, err := r.tx.ExecContext(ctx,
+		fmt.Sprintf("DELETE FROM %s WHERE %s = ?", schema.Quote(table), schema.Quote(column)), value,
+	); err != nil {
+		return 0, fmt.Errorf("failed to delete from %s: %w", table, err)
+	}
+	r.count(Change{Table: table, Action: Deleted}, n)
+	return n, nil
+}
+
+// clear sets column to NULL for the rows of table whose column is value.
+func (r *remover) clear(ctx context.Context, table, column string, value any) error {
+	n, err := r.record(ctx, Cleared, table, column, column, value)
+	if err != nil {
+		return err
+	}
+	if err := r.observeRows(ctx, table, column, column, value); err != nil {
+		return err
+	}
+	if _, err := r.tx.ExecContext(ctx,
+		fmt.Sprintf("UPDATE %[1]s SET %[2]s = NULL WHERE %[2]s = ?", schema.Quote(table), schema.Quote(column)), value,
+	); err != nil {
+		return fmt.Errorf("failed to clear %s.%s: %w", table, column, err)
+	}
+	r.count(Change{Table: table, Column: column, Action: Cleared}, n)
+	return nil
+}
+
+// observeRows passes the rows of table whose column is value to the observer
+// as they are before and after they are deleted, or after cleared is set to
+// NULL if it is not empty.
+func (r *remover) observeRows(ctx context.Context, table, cleared, column string, value any) error {
+	if r.observe == nil {
+		return nil
+	}
+	columns, _, err := schema.Columns(ctx, r.tx, table)
+	if err != nil {
+		return err
+	}
+	after := "''"
+	var args []any
+	if cleared != "" {
+		after = fmt.Sprintf("json_set(%s, ?, NULL)", schema.Object(columns))
+		args = append(args, `$."`+strings.ReplaceAll(cleared, `"`, `\"`)+`"`)
+	}
+	rows, err := r.tx.QueryContext(ctx, fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s = ?",
+		schema.Object(columns), after, schema.Quote(table), schema.Quote(column)), append(args, value)...)
+	if err != nil {
+		return fmt.Errorf("failed to select removed rows of %s: %w", table, err)
+	}
+	type change struct{ before, after string }
+	var changes []change
+	for rows.Next() {
+		var c change
+		if err := rows.Scan(&c.before, &c.after); err != nil {
+			return errors.Join(fmt.Errorf("failed to scan removed row of %s: %w", table, err), rows.Close())
+		}
+		changes = append(changes, c)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return fmt.Errorf("failed to select removed rows of %s: %w", table, err)
+	}
+	for _, c := range changes {
+		if err := r.observe(ctx, r.tx, table, c.before, c.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *remover) count(c Change, n int64) {
+	if n > 0 {
+		r.counts[c] += n
+	}
+}
+
+// changes returns the rows removed other than the deleted rows of table
+// themselves.
+func (r *remover) changes(table string, deleted int64) []Change {
+	var changes []Change
+	for c, n := range r.counts {
+		if c.Table == table && c.Action == Deleted {
+			n -= deleted
+		}
+		if n > 0 {
+			c.Count = n
+			changes = append(changes, c)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Table != changes[j].Table {
+			return changes[i].Table < changes[j].Table
+		}
+		return changes[i].Column < changes[j].Column
+	})
+	return changes
+}
+
+// distinct returns the distinct values of selected for the rows of table
+// whose column is value.
+func (r *remover) distinct(ctx context.Context, table, selected, column string, value any) ([]any, error) {
+	rows, err := r.tx.QueryContext(ctx,
+		fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s = ?", schema.Quote(selected), schema.Quote(table), schema.Quote(column)),
+		value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select %s.%s: %w", table, selected, err)
+	}
+	var values []any
+	for rows.Next() {
+		var v any
+		if err := rows.Scan(&v); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to scan %s.%s: %w", table, selected, err), rows.Close())
+		}
+		if v != nil {
+			values = append(values, v)
+		}
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("failed to select %s.%s: %w", table, selected, err)
+	}
+	return values, nil
+}
+
+// record copies the rows of table whose column is value into the batch and
+// returns how many there were.
+func (r *remover) record(ctx context.Context, action Action, table, cleared, column string, value any) (int64, error) {
+	columns, _, err := schema.Columns(ctx, r.tx, table)
+	if err != nil {
+		return 0, err
+	}
+	var clearedArg any
+	if cleared != "" {
+		clearedArg = cleared
+	}
+	result, err := r.tx.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO trash_row (batch, source, action, cleared, data)
+		SELECT ?, ?, ?, ?, %s FROM %s WHERE %s = ?`,
+		schema.Object(columns), schema.Quote(table), schema.Quote(column)),
+		r.batch, table, string(action), clearedArg, value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy %s to trash: %w", table, err)
+	}
+	return result.RowsAffected()
+}
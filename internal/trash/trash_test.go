@@ -0,0 +1,276 @@
+package trash
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/RyRose/uplog/internal/sqlc/sqlctest"
+)
+
+// setup returns a migrated database with a lift, its routine and progress,
+// all referencing a side weight.
+func setup(t *testing.T) *sql.DB {
+	t.Helper()
+	ctx := context.Background()
+	db := sqlctest.Open(t)
+	for _, stmt := range []string{
+		"INSERT INTO side_weight VALUES ('test sw', 2.5, 0, '')",
+		"INSERT INTO lift (id, link, default_side_weight) VALUES ('test lift', 'link', 'test sw')",
+		"INSERT INTO workout VALUES ('test workout', '')",
+		"INSERT INTO routine VALUES ('test routine', '5x5', 'test lift')",
+		"INSERT INTO routine_workout_mapping VALUES ('test routine', 'test workout')",
+		"INSERT INTO lift_workout_mapping VALUES ('test lift', 'test workout')",
+		"INSERT INTO progress (lift, date, weight, sets, reps, side_weight) VALUES ('test lift', '2025-01-01', 100.5, 3, 5, 'test sw')",
+		"INSERT INTO progress (lift, date, weight, sets, reps) VALUES ('test lift', '2025-01-02', 100, 3, 5)",
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	return db
+}
+
+// dump returns every row touched by the fixture as JSON.
+func dump(t *testing.T, db *sql.DB) string {
+	t.Helper()
+	var out string
+	if err := db.QueryRow(`SELECT json_array(
+		(SELECT json_group_array(json_array(id, multiplier, addend)) FROM side_weight WHERE id = 'test sw'),
+		(SELECT json_group_array(json_array(id, link, default_side_weight)) FROM lift WHERE id = 'test lift'),
+		(SELECT json_group_array(json_array(id, steps, lift)) FROM routine WHERE id = 'test routine'),
+		(SELECT json_group_array(json_array(routine, workout)) FROM routine_workout_mapping WHERE routine = 'test routine'),
+		(SELECT json_group_array(json_array(lift, workout)) FROM lift_workout_mapping WHERE lift = 'test lift'),
+		(SELECT json_group_array(json_array(id, lift, date, weight, sets, reps, side_weight)) FROM progress)
+	)`).Scan(&out); err != nil {
+		t.Fatalf("failed to dump rows: %v", err)
+	}
+	return out
+}
+
+func TestDelete_Blocked(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	before := dump(t, db)
+
+	_, err := New(db, 0).Delete(ctx, "lift", "id", "test lift", false, nil)
+	var blocked *BlockedError
+	if !errors.As(err, &blocked) {
+		t.Fatalf("Delete() error = %v, want BlockedError", err)
+	}
+	want := []Change{
+		{Table: "lift_workout_mapping", Action: Deleted, Count: 1},
+		{Table: "progress", Action: Deleted, Count: 2},
+		{Table: "routine", Action: Deleted, Count: 1},
+		{Table: "routine_workout_mapping", Action: Deleted, Count: 1},
+	}
+	if !reflect.DeepEqual(blocked.Changes, want) {
+		t.Errorf("Changes = %+v, want %+v", blocked.Changes, want)
+	}
+	if after := dump(t, db); after != before {
+		t.Errorf("blocked delete changed rows:\n%s\nwant\n%s", after, before)
+	}
+	var batches int
+	if err := db.QueryRow("SELECT COUNT(*) FROM trash_batch").Scan(&batches); err != nil {
+		t.Fatalf("failed to count batches: %v", err)
+	}
+	if batches != 0 {
+		t.Errorf("blocked delete left %d batches", batches)
+	}
+}
+
+func TestDelete_CascadeAndRestore(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	bin := New(db, 24*time.Hour)
+	before := dump(t, db)
+
+	batch, err := bin.Delete(ctx, "lift", "id", "test lift", true, nil)
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if got := dump(t, db); got != `[[["test sw",2.5,0.0]],[],[],[],[],[]]` {
+		t.Errorf("rows after delete = %s, want only the side weight", got)
+	}
+
+	batches, err := bin.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(batches) != 1 {
+		t.Fatalf("List() = %+v, want 1 batch", batches)
+	}
+	got := batches[0]
+	if got.ID != batch || got.Table != "lift" || got.Key != "test lift" || got.Rows != 6 {
+		t.Errorf("batch = %+v, want lift \"test lift\" with 6 rows", got)
+	}
+	if got.ExpiresAt.Sub(got.DeletedAt) != 24*time.Hour {
+		t.Errorf("batch expires at %v, want a day after %v", got.ExpiresAt, got.DeletedAt)
+	}
+
+	n, err := bin.Restore(ctx, batch, nil)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if n != 6 {
+		t.Errorf("Restore() = %d, want 6", n)
+	}
+	if after := dump(t, db); after != before {
+		t.Errorf("rows after restore:\n%s\nwant\n%s", after, before)
+	}
+	if _, err := bin.Restore(ctx, batch, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Restore() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDelete_ClearsNullableReferences(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	bin := New(db, 0)
+	before := dump(t, db)
+
+	_, err := bin.Delete(ctx, "side_weight", "id", "test sw", false, nil)
+	var blocked *BlockedError
+	if !errors.As(err, &blocked) {
+		t.Fatalf("Delete() error = %v, want BlockedError", err)
+	}
+	want := []Change{
+		{Table: "lift", Column: "default_side_weight", Action: Cleared, Count: 1},
+		{Table: "progress", Column: "side_weight", Action: Cleared, Count: 1},
+	}
+	if !reflect.DeepEqual(blocked.Changes, want) {
+		t.Errorf("Changes = %+v, want %+v", blocked.Changes, want)
+	}
+
+	batch, err := bin.Delete(ctx, "side_weight", "id", "test sw", true, nil)
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	var lifts, progress int
+	if err := db.QueryRow(`SELECT
+		(SELECT COUNT(*) FROM lift WHERE id = 'test lift' AND default_side_weight IS NULL),
+		(SELECT COUNT(*) FROM progress WHERE side_weight IS NULL)`).Scan(&lifts, &progress); err != nil {
+		t.Fatalf("failed to count cleared rows: %v", err)
+	}
+	if lifts != 1 || progress != 2 {
+		t.Errorf("cleared lifts = %d, progress = %d; want 1, 2", lifts, progress)
+	}
+
+	if _, err := bin.Restore(ctx, batch, nil); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if after := dump(t, db); after != before {
+		t.Errorf("rows after restore:\n%s\nwant\n%s", after, before)
+	}
+}
+
+func TestDelete_WithoutDependents(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+
+	if _, err := New(db, 0).Delete(ctx, "routine_workout_mapping", "routine", "test routine", false, nil); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := New(db, 0).Delete(ctx, "routine", "id", "test routine", false, nil); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+}
+
+func TestDelete_Errors(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	bin := New(db, 0)
+
+	if _, err := bin.Delete(ctx, "lift", "id", "missing", true, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() of missing row error = %v, want ErrNotFound", err)
+	}
+	if _, err := bin.Delete(ctx, "nope", "id", "test lift", true, nil); err == nil {
+		t.Error("Delete() from missing table succeeded, want error")
+	}
+	if _, err := bin.Delete(ctx, "lift", "nope", "nope", true, nil); err == nil {
+		t.Error("Delete() by missing column succeeded, want error")
+	}
+}
+
+func TestPurge(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	bin := New(db, time.Hour)
+
+	if _, err := bin.Delete(ctx, "workout", "id", "test workout", true, nil); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	now := time.Now()
+	bin.now = func() time.Time { return now.Add(2 * time.Hour) }
+	batches, err := bin.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(batches) != 0 {
+		t.Errorf("List() = %+v after retention, want none", batches)
+	}
+	var rows int
+	if err := db.QueryRow("SELECT COUNT(*) FROM trash_row").Scan(&rows); err != nil {
+		t.Fatalf("failed to count trash rows: %v", err)
+	}
+	if rows != 0 {
+		t.Errorf("purge left %d trash rows", rows)
+	}
+}
+
+func TestDelete_Observer(t *testing.T) {
+	ctx := context.Background()
+	db := setup(t)
+	bin := New(db, 0)
+	before := dump(t, db)
+
+	observeErr := errors.New("observer failed")
+	_, err := bin.Delete(ctx, "lift", "id", "test lift", true, func(ctx context.Context, tx *sql.Tx, table, before, after string) error {
+		return observeErr
+	})
+	if !errors.Is(err, observeErr) {
+		t.Errorf("Delete() error = %v, want %v", err, observeErr)
+	}
+	if after := dump(t, db); after != before {
+		t.Errorf("failed observer changed rows:\n%s\nwant\n%s", after, before)
+	}
+
+	// Every removed row is observed, including the dependents of the row.
+	var removed []string
+	batch, err := bin.Delete(ctx, "side_weight", "id", "test sw", true, func(ctx context.Context, tx *sql.Tx, table, before, after string) error {
+		if before == "" {
+			t.Errorf("removed row of %s has no value before", table)
+		}
+		removed = append(removed, table+" "+after)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	want := []string{
+		`lift {"id":"test lift","link":"link","default_side_weight":null,"notes":null,"lift_group":null}`,
+		`progress {"id":1,"lift":"test lift","date":"2025-01-01","weight":100.5,"sets":3,"reps":5,"side_weight":null}`,
+		"side_weight ",
+	}
+	if !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed rows = %q, want %q", removed, want)
+	}
+
+	var restored []string
+	if _, err := bin.Restore(ctx, batch, func(ctx context.Context, tx *sql.Tx, table, before, after string) error {
+		if after == "" {
+			t.Errorf("restored row of %s has no value after", table)
+		}
+		restored = append(restored, table+" "+before)
+		return nil
+	}); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	want = []string{"side_weight ", want[1], want[0]}
+	if !reflect.DeepEqual(restored, want) {
+		t.Errorf("restored rows = %q, want %q", restored, want)
+	}
+}
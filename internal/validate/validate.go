@@ -0,0 +1,192 @@
+// Package validate checks the values of form fields before they are written,
+// so that invalid values are reported for the fields they were entered in
+// rather than as errors of the database.
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RyRose/uplog/internal/schema"
+)
+
+// FieldError is a problem with the value of a field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors are the problems with the values of the fields of a form, in the
+// order they were found.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, f := range e {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	return "invalid " + strings.Join(parts, "; ")
+}
+
+// Add adds err as the problem with field unless it is nil.
+func (e *Errors) Add(field string, err error) {
+	if err != nil {
+		*e = append(*e, FieldError{Field: field, Message: err.Error()})
+	}
+}
+
+// Message returns the problem with field, or "" if there is none.
+func (e Errors) Message(field string) string {
+	for _, f := range e {
+		if f.Field == field {
+			return f.Message
+		}
+	}
+	return ""
+}
+
+// Err returns e, or nil if there are no problems.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Date checks that s is a date formatted as YYYY-MM-DD.
+func Date(s string) error {
+	if _, err := time.Parse(time.DateOnly, s); err != nil {
+		return errors.New("must be a date formatted as YYYY-MM-DD")
+	}
+	return nil
+}
+
+// NonNegative checks that s is a number that is not negative.
+func NonNegative(s string) error {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return errors.New("must be a number")
+	}
+	if f < 0 {
+		return errors.New("must not be negative")
+	}
+	return nil
+}
+
+// URL checks that s is empty or an absolute http or https URL.
+func URL(s string) error {
+	if s == "" {
+		return nil
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be an http or https URL")
+	}
+	return nil
+}
+
+// Step is a step of a routine: Sets sets of Reps reps, up to MaxReps, at
+// Percent percent of the training max. AMRAP steps are done for as many reps
+// as possible.
+type Step struct {
+	Sets, Reps, MaxReps int64
+	AMRAP               bool
+	Percent             float64
+}
+
+// step matches a step such as 5@65%, 5+@85%, 3~5@80% or 5x5@65%.
+var step = regexp.MustCompile(`^(?:(\d+)x)?(\d+)(?:~(\d+))?(\+)?@(\d+(?:\.\d+)?)%$`)
+
+// ParseSteps parses the comma separated steps of a routine.
+func ParseSteps(s string) ([]Step, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, errors.New("must list at least one step")
+	}
+	var steps []Step
+	for i, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		m := step.FindStringSubmatch(part)
+		if m == nil {
+			return nil, fmt.Errorf("step %d %q must look like 5@65%%, 5+@85%%, 3~5@80%% or 5x5@65%%", i+1, part)
+		}
+		st := Step{Sets: 1, AMRAP: m[4] != ""}
+		if m[1] != "" {
+			st.Sets, _ = strconv.ParseInt(m[1], 10, 64)
+		}
+		st.Reps, _ = strconv.ParseInt(m[2], 10, 64)
+		st.MaxReps = st.Reps
+		if m[3] != "" {
+			st.MaxReps, _ = strconv.ParseInt(m[3], 10, 64)
+		}
+		st.Percent, _ = strconv.ParseFloat(m[5], 64)
+		if st.Sets == 0 || st.MaxReps < st.Reps {
+			return nil, fmt.Errorf("step %d %q has no sets or fewer reps than it starts with", i+1, part)
+		}
+		steps = append(steps, st)
+	}
+	return steps, nil
+}
+
+// Steps checks that s lists the steps of a routine, as parsed by ParseSteps.
+func Steps(s string) error {
+	_, err := ParseSteps(s)
+	return err
+}
+
+// placeholder matches the placeholders of side weight formats.
+var placeholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// SideWeightFormat checks that s formats a side weight: it shows the
+// {WEIGHT} and may show the {SIDE_WEIGHT}, but no other placeholders.
+func SideWeightFormat(s string) error {
+	if !strings.Contains(s, "{WEIGHT}") {
+		return errors.New("must show the {WEIGHT}")
+	}
+	rest := placeholder.ReplaceAllStringFunc(s, func(p string) string {
+		if p == "{WEIGHT}" || p == "{SIDE_WEIGHT}" {
+			return ""
+		}
+		return p
+	})
+	if strings.ContainsAny(rest, "{}") {
+		return errors.New("must only show the {WEIGHT} and {SIDE_WEIGHT}")
+	}
+	return nil
+}
+
+// Ref is the value of a field referencing the primary key of a table.
+type Ref struct {
+	Field, Table, Value string
+}
+
+// References returns the problems with the fields of refs whose values are
+// not keys of the tables they reference.
+func References(ctx context.Context, db schema.Querier, refs []Ref) (Errors, error) {
+	var errs Errors
+	for _, ref := range refs {
+		_, primaryKey, err := schema.Columns(ctx, db, ref.Table)
+		if err != nil {
+			return nil, err
+		}
+		if len(primaryKey) != 1 {
+			return nil, fmt.Errorf("%s has no single column primary key to reference", ref.Table)
+		}
+		var exists bool
+		if err := db.QueryRowContext(ctx,
+			fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE %s = ?)", schema.Quote(ref.Table), schema.Quote(primaryKey[0])),
+			ref.Value,
+		).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to look up %s: %w", ref.Table, err)
+		}
+		if !exists {
+			errs.Add(ref.Field, fmt.Errorf("no %s is named %q", strings.ReplaceAll(ref.Table, "_", " "), ref.Value))
+		}
+	}
+	return errs, nil
+}
@@ -0,0 +1,95 @@
+package validate
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestParseSteps(t *testing.T) {
+	got, err := ParseSteps("5@40%, 3~5@80%,5+@85%,5x5@65%,1@92.5%")
+	if err != nil {
+		t.Fatalf("ParseSteps() error = %v", err)
+	}
+	want := []Step{
+		{Sets: 1, Reps: 5, MaxReps: 5, Percent: 40},
+		{Sets: 1, Reps: 3, MaxReps: 5, Percent: 80},
+		{Sets: 1, Reps: 5, MaxReps: 5, AMRAP: true, Percent: 85},
+		{Sets: 5, Reps: 5, MaxReps: 5, Percent: 65},
+		{Sets: 1, Reps: 1, MaxReps: 1, Percent: 92.5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSteps() = %+v, want %+v", got, want)
+	}
+
+	for _, s := range []string{"", "5", "5@", "5@40", "5 at 40%", "5@40%,", "0x5@40%", "5~3@40%"} {
+		if _, err := ParseSteps(s); err == nil {
+			t.Errorf("ParseSteps(%q) error = nil, want an error", s)
+		}
+	}
+}
+
+func TestChecks(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(string) error
+		value string
+		ok    bool
+	}{
+		{"Date", Date, "2025-03-10", true},
+		{"Date", Date, "2025-3-10", false},
+		{"Date", Date, "2025-02-30", false},
+		{"NonNegative", NonNegative, "0", true},
+		{"NonNegative", NonNegative, " 102.5", true},
+		{"NonNegative", NonNegative, "-1", false},
+		{"NonNegative", NonNegative, "heavy", false},
+		{"URL", URL, "", true},
+		{"URL", URL, "https://example.com/squat", true},
+		{"URL", URL, "example.com", false},
+		{"URL", URL, "ftp://example.com", false},
+		{"SideWeightFormat", SideWeightFormat, "{WEIGHT}", true},
+		{"SideWeightFormat", SideWeightFormat, "{SIDE_WEIGHT}x2+45={WEIGHT}", true},
+		{"SideWeightFormat", SideWeightFormat, "{SIDE_WEIGHT}x2", false},
+		{"SideWeightFormat", SideWeightFormat, "{WEIGHT} {REPS}", false},
+		{"SideWeightFormat", SideWeightFormat, "{WEIGHT}}", false},
+	}
+	for _, tt := range tests {
+		if err := tt.check(tt.value); (err == nil) != tt.ok {
+			t.Errorf("%s(%q) error = %v, want ok %v", tt.name, tt.value, err, tt.ok)
+		}
+	}
+}
+
+func TestReferences(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if _, err := db.Exec(`
+CREATE TABLE lift (id TEXT PRIMARY KEY NOT NULL);
+CREATE TABLE side_weight (id TEXT PRIMARY KEY NOT NULL);
+INSERT INTO lift VALUES ('squat');
+`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	got, err := References(context.Background(), db, []Ref{
+		{Field: "lift", Table: "lift", Value: "squat"},
+		{Field: "side", Table: "side_weight", Value: "x2"},
+	})
+	if err != nil {
+		t.Fatalf("References() error = %v", err)
+	}
+	want := Errors{{Field: "side", Message: `no side weight is named "x2"`}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("References() = %+v, want %+v", got, want)
+	}
+	if got.Message("lift") != "" {
+		t.Errorf("Message(lift) = %q, want none", got.Message("lift"))
+	}
+}
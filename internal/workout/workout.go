@@ -0,0 +1,120 @@
+// Package workout expands the templates of workouts and finds the hierarchy of
+// the workouts containing each other as subworkouts.
+package workout
+
+import (
+	"slices"
+	"strings"
+)
+
+// Expand returns template with each {name} placeholder replaced by the value
+// of the variable name, whose own placeholders are expanded in turn.
+// Placeholders of unknown variables, such as {ROUTINE}, and of variables
+// within their own values are left as is.
+func Expand(template string, variables map[string]string) string {
+	return expand(template, variables, nil)
+}
+
+// expand expands template, which is the value of the variables expanding.
+func expand(template string, variables map[string]string, expanding []string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(template[:start])
+		name := template[start+1 : end]
+		if value, ok := variables[name]; ok && !slices.Contains(expanding, name) {
+			b.WriteString(expand(value, variables, append(expanding, name)))
+		} else {
+			b.WriteString(template[start : end+1])
+		}
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
+
+// Relation is a workout containing another as a subworkout.
+type Relation struct {
+	Subworkout, Superworkout string
+}
+
+// Node is a workout of a graph.
+type Node struct {
+	Workout string
+	// Level is how many relations away the workout is from the workout of the
+	// graph: negative for the superworkouts containing it and positive for
+	// the subworkouts it contains.
+	Level int
+	// Row is the position of the workout among those of its level.
+	Row int
+}
+
+// Graph is the hierarchy of the workouts related to a workout.
+type Graph struct {
+	// Nodes are sorted by level then row. The workout of the graph is the
+	// only node of level 0.
+	Nodes []Node
+	// Edges are the relations between nodes, as the indexes of the
+	// superworkout and the subworkout.
+	Edges [][2]int
+}
+
+// Hierarchy returns the graph of id, the superworkouts containing it and the
+// subworkouts it contains at any depth of relations. Each workout is placed at
+// the level it is first reached at, searching breadth first from id.
+func Hierarchy(id string, relations []Relation) Graph {
+	levels := map[string]int{id: 0}
+	var g Graph
+	g.Nodes = append(g.Nodes, Node{Workout: id})
+	for _, dir := range []int{-1, 1} {
+		frontier := []string{id}
+		for level := dir; len(frontier) > 0; level += dir {
+			var next []string
+			for _, r := range relations {
+				from, to := r.Subworkout, r.Superworkout
+				if dir > 0 {
+					from, to = to, from
+				}
+				if _, seen := levels[to]; seen || !slices.Contains(frontier, from) {
+					continue
+				}
+				levels[to] = level
+				next = append(next, to)
+			}
+			slices.Sort(next)
+			for i, workout := range next {
+				g.Nodes = append(g.Nodes, Node{Workout: workout, Level: level, Row: i})
+			}
+			frontier = next
+		}
+	}
+	slices.SortFunc(g.Nodes, func(a, b Node) int {
+		if a.Level != b.Level {
+			return a.Level - b.Level
+		}
+		return a.Row - b.Row
+	})
+
+	index := make(map[string]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		index[n.Workout] = i
+	}
+	for _, r := range relations {
+		super, ok := index[r.Superworkout]
+		if !ok {
+			continue
+		}
+		if sub, ok := index[r.Subworkout]; ok {
+			g.Edges = append(g.Edges, [2]int{super, sub})
+		}
+	}
+	return g
+}
@@ -0,0 +1,67 @@
+package workout
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	variables := map[string]string{
+		"531":     "Main lifts\n{ROUTINE}\n{assist}",
+		"assist":  "Assistance: {PUSH}",
+		"PUSH":    "Dips",
+		"loop":    "again {loop}",
+		"unknown": "{missing}",
+	}
+	tests := []struct {
+		template, want string
+	}{
+		{"plain", "plain"},
+		{"{531}", "Main lifts\n{ROUTINE}\nAssistance: Dips"},
+		{"{loop}", "again {loop}"},
+		{"{unknown} and {PUSH}", "{missing} and Dips"},
+		{"unclosed {PUSH", "unclosed {PUSH"},
+		{"{}", "{}"},
+	}
+	for _, tt := range tests {
+		if got := Expand(tt.template, variables); got != tt.want {
+			t.Errorf("Expand(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestHierarchy(t *testing.T) {
+	relations := []Relation{
+		{Subworkout: "assistance", Superworkout: "day 1"},
+		{Subworkout: "assistance", Superworkout: "day 2"},
+		{Subworkout: "push", Superworkout: "assistance"},
+		{Subworkout: "pull", Superworkout: "assistance"},
+		{Subworkout: "warmup", Superworkout: "day 1"},
+		// Unrelated to the assistance work.
+		{Subworkout: "cardio", Superworkout: "rest day"},
+	}
+	got := Hierarchy("assistance", relations)
+	want := Graph{
+		Nodes: []Node{
+			{Workout: "day 1", Level: -1, Row: 0},
+			{Workout: "day 2", Level: -1, Row: 1},
+			{Workout: "assistance"},
+			{Workout: "pull", Level: 1, Row: 0},
+			{Workout: "push", Level: 1, Row: 1},
+		},
+		Edges: [][2]int{{0, 2}, {1, 2}, {2, 4}, {2, 3}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hierarchy() = %+v, want %+v", got, want)
+	}
+
+	// Workouts containing themselves are placed once.
+	got = Hierarchy("a", []Relation{{Subworkout: "a", Superworkout: "b"}, {Subworkout: "b", Superworkout: "a"}})
+	want = Graph{
+		Nodes: []Node{{Workout: "b", Level: -1}, {Workout: "a"}},
+		Edges: [][2]int{{0, 1}, {1, 0}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hierarchy() of a cycle = %+v, want %+v", got, want)
+	}
+}
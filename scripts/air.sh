@@ -2,6 +2,6 @@
 
 set -eu
 
-export DEBUG=true
+export UPLOG_DEBUG=true
 
 go run github.com/air-verse/air@latest $@
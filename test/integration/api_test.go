@@ -0,0 +1,150 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/RyRose/uplog/test/testutil"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// TestIntegration_DataAPI tests the JSON API of the data tables.
+func TestIntegration_DataAPI(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	srv := testutil.Setup(t)
+	defer srv.Cancel()
+
+	baseURL := "http://localhost:" + srv.GetPort(t) + "/api/data"
+	do := func(t *testing.T, method, path, body string, header map[string]string) (*http.Response, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, baseURL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		b, _ := io.ReadAll(resp.Body)
+		return resp, string(b)
+	}
+	decode := func(t *testing.T, body string, v any) {
+		t.Helper()
+		if err := json.Unmarshal([]byte(body), v); err != nil {
+			t.Fatalf("failed to decode %s: %v", body, err)
+		}
+	}
+
+	resp, body := do(t, "POST", "/lift", `{"id": "API Lift", "link": "", "notes": null}`, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST: got status %d, want %d, body: %s", resp.StatusCode, http.StatusCreated, body)
+	}
+	var lift map[string]any
+	decode(t, body, &lift)
+	if lift["id"] != "API Lift" || lift["notes"] != nil {
+		t.Errorf("POST: got row %v", lift)
+	}
+	etag := resp.Header.Get("ETag")
+
+	t.Run("invalid rows are rejected", func(t *testing.T) {
+		for body, field := range map[string]string{
+			`{"id": "API Squat", "link": "", "default_side_weight": "no such side weight"}`:       "default_side_weight",
+			`{"id": "API Squat", "link": "not a link"}`:                                           "link",
+			`{"lift": "API Lift", "date": "2025-01-01", "weight": "heavy", "sets": 3, "reps": 5}`: "weight",
+			`{"lift": "API Lift", "date": "2025-01-01", "weight": true, "sets": 3, "reps": 5}`:    "weight",
+			`{"lift": "API Lift", "date": "2025-01-01", "weight": -1, "sets": 3, "reps": 5}`:      "weight",
+			`{"lift": "API Lift", "date": "tomorrow", "weight": 1, "sets": 3, "reps": 5}`:         "date",
+		} {
+			table := "/lift"
+			if strings.Contains(body, "date") {
+				table = "/progress"
+			}
+			resp, got := do(t, "POST", table, body, nil)
+			if resp.StatusCode != http.StatusUnprocessableEntity {
+				t.Errorf("POST %s: got status %d, want %d, body: %s", body, resp.StatusCode, http.StatusUnprocessableEntity, got)
+				continue
+			}
+			var invalid struct {
+				Errors []struct{ Field, Message string }
+			}
+			decode(t, got, &invalid)
+			if len(invalid.Errors) != 1 || invalid.Errors[0].Field != field || invalid.Errors[0].Message == "" {
+				t.Errorf("POST %s: got errors %+v, want one for %s", body, invalid.Errors, field)
+			}
+		}
+	})
+
+	resp, body = do(t, "POST", "/progress", `{"lift": "API Lift", "date": "2025-01-01", "weight": 102.5, "sets": 3, "reps": 5}`, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST progress: got status %d, body: %s", resp.StatusCode, body)
+	}
+	var progress struct {
+		ID     int64
+		Weight float64
+	}
+	decode(t, body, &progress)
+	if progress.ID == 0 || progress.Weight != 102.5 {
+		t.Errorf("POST progress: got row %s", body)
+	}
+
+	t.Run("list", func(t *testing.T) {
+		resp, body := do(t, "GET", "/lift?"+url.Values{"q": {"API"}}.Encode(), "", nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET: got status %d, body: %s", resp.StatusCode, body)
+		}
+		var lifts []map[string]any
+		decode(t, body, &lifts)
+		if len(lifts) != 1 || lifts[0]["id"] != "API Lift" {
+			t.Errorf("GET: got rows %v", lifts)
+		}
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		path := "/lift/" + url.PathEscape("API Lift")
+		resp, body := do(t, "PATCH", path, `{"notes": "from the API"}`, map[string]string{"If-Match": etag})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("PATCH: got status %d, body: %s", resp.StatusCode, body)
+		}
+		var lift map[string]any
+		decode(t, body, &lift)
+		if lift["notes"] != "from the API" {
+			t.Errorf("PATCH: got row %v", lift)
+		}
+
+		// The patch is based on a stale version.
+		resp, body = do(t, "PATCH", path, `{"notes": "stale"}`, map[string]string{"If-Match": etag})
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("PATCH stale: got status %d, want %d, body: %s", resp.StatusCode, http.StatusConflict, body)
+		}
+		resp, body = do(t, "GET", path, "", nil)
+		if resp.StatusCode != http.StatusOK || !strings.Contains(body, "from the API") {
+			t.Errorf("GET: got status %d, body: %s", resp.StatusCode, body)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		path := "/lift/" + url.PathEscape("API Lift")
+		resp, body := do(t, "DELETE", path, "", nil)
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("DELETE: got status %d, want %d, body: %s", resp.StatusCode, http.StatusConflict, body)
+		}
+		if resp, body = do(t, "DELETE", path+"?cascade=true", "", nil); resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("DELETE cascade: got status %d, body: %s", resp.StatusCode, body)
+		}
+		if resp, body = do(t, "GET", path, "", nil); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("GET deleted: got status %d, body: %s", resp.StatusCode, body)
+		}
+	})
+}
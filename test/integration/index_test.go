@@ -3,6 +3,8 @@ package integration
 import (
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
 	"testing"
 
@@ -104,3 +106,168 @@ func TestIntegration_MainViewEndpoints(t *testing.T) {
 		})
 	}
 }
+
+// TestIntegration_LiftPage tests that the page of a lift shows the workouts
+// using it through subworkouts and its progress, and edits it inline.
+func TestIntegration_LiftPage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	srv := testutil.Setup(t)
+	defer srv.Cancel()
+
+	_, err := srv.GetWriteDB(t).Exec(`INSERT INTO progress (lift, date, weight, sets, reps) VALUES
+		('Chinups', '2025-01-01', 10, 3, 8),
+		('Chinups', '2025-01-02', 20, 1, 8)`)
+	if err != nil {
+		t.Fatalf("failed to insert progress: %v", err)
+	}
+
+	get := func(t *testing.T, path string) *goquery.Document {
+		t.Helper()
+		resp := srv.Get(t, path)
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("GET %s: unexpected status code: got %d, want %d, body: %s",
+				path, resp.StatusCode, http.StatusOK, string(body))
+		}
+		doc, err := goquery.NewDocumentFromReader(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to parse HTML: %v", err)
+		}
+		return doc
+	}
+
+	page := get(t, "/lift/Chinups")
+	if got := page.Find("article").AttrOr("hx-get", ""); got != "/view/lift/Chinups" {
+		t.Errorf("page loads %q, want %q", got, "/view/lift/Chinups")
+	}
+
+	doc := get(t, "/view/lift/Chinups")
+	workouts := doc.Find("#lift li").Text()
+	for _, want := range []string{
+		"531 (pull assistance work)",
+		"via 531 (pull assistance work)",
+		"via 531 (assistance work)",
+	} {
+		if !strings.Contains(workouts, want) {
+			t.Errorf("workouts %q do not contain %q", workouts, want)
+		}
+	}
+	if n := doc.Find("#lifthistory tbody tr").Length(); n != 2 {
+		t.Errorf("got %d history rows, want 2", n)
+	}
+	if got := doc.Find("#liftrecords tbody td").Eq(1).Text(); got != "20" {
+		t.Errorf("got record weight %q, want 20", got)
+	}
+
+	notes := doc.Find(`input[name="notes"]`)
+	form := strings.NewReader(url.Values{"notes": {"from the lift page"}}.Encode())
+	req, err := http.NewRequest("PATCH", "http://localhost:"+srv.GetPort(t)+notes.AttrOr("hx-patch", ""), form)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("PATCH: unexpected status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	doc = get(t, "/view/lift/Chinups")
+	if got := doc.Find(`input[name="notes"]`).AttrOr("value", ""); got != "from the lift page" {
+		t.Errorf("got notes %q, want %q", got, "from the lift page")
+	}
+
+	resp = srv.Get(t, "/view/lift/Missing")
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET missing lift: unexpected status code: got %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+// TestIntegration_WorkoutPage tests that the page of a workout expands its
+// template and graphs its subworkouts, and that logging it fills in a progress
+// form for each of its lifts.
+func TestIntegration_WorkoutPage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	srv := testutil.Setup(t)
+	defer srv.Cancel()
+
+	const id = "531 FSL (week 1, deadlift)"
+	_, err := srv.GetWriteDB(t).Exec(`INSERT INTO progress (lift, date, weight, sets, reps, side_weight)
+		VALUES ('Deadlift (hex 90% TM)', '2025-01-01', 95, 1, 5, 'x2+45')`)
+	if err != nil {
+		t.Fatalf("failed to insert progress: %v", err)
+	}
+
+	get := func(t *testing.T, req *http.Request) *goquery.Document {
+		t.Helper()
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("GET %s: unexpected status code: got %d, want %d, body: %s",
+				req.URL, resp.StatusCode, http.StatusOK, string(body))
+		}
+		doc, err := goquery.NewDocumentFromReader(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to parse HTML: %v", err)
+		}
+		return doc
+	}
+	newRequest := func(t *testing.T, path string) *http.Request {
+		t.Helper()
+		req, err := http.NewRequest("GET", "http://localhost:"+srv.GetPort(t)+path, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		return req
+	}
+
+	doc := get(t, newRequest(t, "/view/workout/"+url.PathEscape(id)))
+	template := doc.Find("#workouttemplate").Text()
+	if strings.Contains(template, "{531") || !strings.Contains(template, "{ROUTINE}") {
+		t.Errorf("template %q is not expanded", template)
+	}
+	if got := doc.Find("#workoutroutines").Text(); !strings.Contains(got, "Deadlift (hex 90% TM)") {
+		t.Errorf("routines %q do not contain the deadlift routine", got)
+	}
+	graph := doc.Find("#workoutgraph title").Map(func(_ int, s *goquery.Selection) string { return s.Text() })
+	for _, want := range []string{id, "531 (assistance work)", "531 (pull assistance work)"} {
+		if !slices.Contains(graph, want) {
+			t.Errorf("graph %q does not contain %q", graph, want)
+		}
+	}
+	logURL := doc.Find(`a[href^="/?workout="]`).AttrOr("href", "")
+
+	req := newRequest(t, "/view/tabs/main")
+	req.Header.Set("HX-Current-URL", "http://localhost"+logURL)
+	doc = get(t, req)
+	forms := doc.Find(`form:has(input[name="keep"])`)
+	if forms.Length() != 1 {
+		t.Fatalf("got %d workout forms, want 1", forms.Length())
+	}
+	if got := forms.Find(`input[name="weight"]`).AttrOr("value", ""); got != "95" {
+		t.Errorf("got weight %q, want 95", got)
+	}
+	if got := forms.Find("select").Eq(1).AttrOr("hx-get", ""); !strings.Contains(got, "side=x2%2B45") {
+		t.Errorf("side weight select %q does not select x2+45", got)
+	}
+
+	resp := srv.Get(t, "/view/workout/Missing")
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET missing workout: unexpected status code: got %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
@@ -195,11 +195,15 @@ func TestIntegration_ProgressTableMutations(t *testing.T) {
 
 	baseURL := "http://localhost:" + srv.GetPort(t)
 
-	// First, we need a lift to create progress for
-	// Use one from the default data
+	// Foreign keys are enforced, so the lift progress is created for must exist.
+	if _, err := srv.GetWriteDB(t).Exec(
+		"INSERT OR IGNORE INTO lift (id, link) VALUES ('bench-press', '')"); err != nil {
+		t.Fatalf("failed to insert lift: %v", err)
+	}
+
 	t.Run("POST progresstablerow creates entry", func(t *testing.T) {
 		formData := url.Values{}
-		formData.Set("lift", "bench-press") // From default data
+		formData.Set("lift", "bench-press")
 		formData.Set("date", "2024-12-25")
 		formData.Set("weight", "225")
 		formData.Set("sets", "3")
@@ -399,3 +403,307 @@ func TestIntegration_DataProgressMutations(t *testing.T) {
 		}
 	})
 }
+
+// TestIntegration_MultiFieldPatch tests that PATCH applies all fields of a
+// request in one transaction and renders the updated row.
+func TestIntegration_MultiFieldPatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	srv := testutil.Setup(t)
+	defer srv.Cancel()
+
+	baseURL := "http://localhost:" + srv.GetPort(t) + "/view/data/lift_muscle_mapping/"
+
+	patch := func(t *testing.T, path string, formData url.Values) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest("PATCH", baseURL+path, strings.NewReader(formData.Encode()))
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("HX-Request", "true")
+
+		client := &http.Client{}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	// Errors are rendered as alerts with their status.
+	wantError := func(t *testing.T, status int, body string, code int) {
+		t.Helper()
+		if status != code || !strings.Contains(body, `role="alert"`) {
+			t.Fatalf("unexpected response: got status %d, want %d alert, body: %s", status, code, body)
+		}
+	}
+
+	// From default data
+	oldPath := url.PathEscape("Bench (DB)") + "/Biceps/" + url.PathEscape("D Stabilizer")
+	newPath := url.PathEscape("Bench (Smith)") + "/Triceps/" + url.PathEscape("D Stabilizer")
+
+	t.Run("renames both keys", func(t *testing.T) {
+		status, body := patch(t, oldPath, url.Values{
+			"lift":   {"Bench (Smith)"},
+			"muscle": {"Triceps"},
+		})
+		if status != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d, body: %s", status, http.StatusOK, body)
+		}
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader("<table>" + body + "</table>"))
+		if err != nil {
+			t.Fatalf("failed to parse HTML: %v", err)
+		}
+		row := doc.Find("tr")
+		if row.Length() != 1 {
+			t.Fatalf("expected one rendered row, got %d: %s", row.Length(), body)
+		}
+		for _, want := range []string{"Bench (Smith)", "Triceps", "D Stabilizer"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("expected updated row to contain %q, body: %s", want, body)
+			}
+		}
+	})
+
+	t.Run("rolls back on unknown field", func(t *testing.T) {
+		status, body := patch(t, newPath, url.Values{
+			"movement": {"Target"},
+			"unknown":  {"value"},
+		})
+		wantError(t, status, body, http.StatusBadRequest)
+		// The movement is unchanged, so the row is still found by it.
+		status, body = patch(t, newPath, url.Values{"movement": {"D Stabilizer"}})
+		if status != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d, body: %s", status, http.StatusOK, body)
+		}
+	})
+
+	t.Run("missing row", func(t *testing.T) {
+		status, body := patch(t, oldPath, url.Values{"muscle": {"Chest"}})
+		wantError(t, status, body, http.StatusNotFound)
+	})
+}
+
+// TestIntegration_PatchConflict tests that PATCH only applies to the version of
+// the row it is based on and otherwise renders the stored row.
+func TestIntegration_PatchConflict(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	srv := testutil.Setup(t)
+	defer srv.Cancel()
+
+	baseURL := "http://localhost:" + srv.GetPort(t)
+	// From default data
+	rowURL := baseURL + "/view/data/lift/" + url.PathEscape("Bench (BB)")
+
+	do := func(t *testing.T, req *http.Request) (*http.Response, string) {
+		t.Helper()
+		client := &http.Client{}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+	patch := func(t *testing.T, notes, etag string) (*http.Response, string) {
+		t.Helper()
+		req, err := http.NewRequest("PATCH", rowURL, strings.NewReader(url.Values{"notes": {notes}}.Encode()))
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if etag != "" {
+			req.Header.Set("If-Match", etag)
+		}
+		return do(t, req)
+	}
+
+	resp, body := patch(t, "first", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d, body: %s", resp.StatusCode, http.StatusOK, body)
+	}
+	first := resp.Header.Get("ETag")
+	if first == "" {
+		t.Fatal("expected an ETag for the patched row")
+	}
+
+	t.Run("GET renders the version", func(t *testing.T) {
+		req, err := http.NewRequest("GET", baseURL+"/view/data/lift", nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		req.Header.Set("HX-Current-URL", baseURL+"/data/lift?"+url.Values{"f.id": {"Bench (BB)"}}.Encode())
+		resp, body := do(t, req)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d, body: %s", resp.StatusCode, http.StatusOK, body)
+		}
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to parse HTML: %v", err)
+		}
+		headers, ok := doc.Find("tbody tr[hx-headers]").First().Attr("hx-headers")
+		if !ok || !strings.Contains(headers, strings.ReplaceAll(first, `"`, `\"`)) {
+			t.Errorf("expected row headers to contain version %s, got %q", first, headers)
+		}
+	})
+
+	resp, body = patch(t, "second", first)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d, body: %s", resp.StatusCode, http.StatusOK, body)
+	}
+	second := resp.Header.Get("ETag")
+	if second == first {
+		t.Fatalf("expected the version to change from %s", first)
+	}
+
+	t.Run("stale version conflicts", func(t *testing.T) {
+		resp, body := patch(t, "third", first)
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("unexpected status code: got %d, want %d, body: %s", resp.StatusCode, http.StatusConflict, body)
+		}
+		if got := resp.Header.Get("ETag"); got != second {
+			t.Errorf("expected the stored version %s, got %s", second, got)
+		}
+		for _, want := range []string{`value="second"`, "third", "Use yours"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("expected conflict to contain %q, body: %s", want, body)
+			}
+		}
+	})
+
+	t.Run("current version applies", func(t *testing.T) {
+		resp, body := patch(t, "third", second)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d, body: %s", resp.StatusCode, http.StatusOK, body)
+		}
+		if !strings.Contains(body, `value="third"`) {
+			t.Errorf("expected updated row to contain the new notes, body: %s", body)
+		}
+	})
+}
+
+// TestIntegration_InvalidFields tests that invalid fields are rendered with
+// their problems rather than saved.
+func TestIntegration_InvalidFields(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	srv := testutil.Setup(t)
+	defer srv.Cancel()
+
+	baseURL := "http://localhost:" + srv.GetPort(t)
+	send := func(t *testing.T, method, path string, form url.Values) (*http.Response, *goquery.Document) {
+		t.Helper()
+		req, err := http.NewRequest(method, baseURL+path, strings.NewReader(form.Encode()))
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		client := &http.Client{}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		doc, err := goquery.NewDocumentFromReader(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to parse HTML: %v", err)
+		}
+		return resp, doc
+	}
+	invalid := func(t *testing.T, resp *http.Response, doc *goquery.Document, name, value string) {
+		t.Helper()
+		if resp.StatusCode != http.StatusUnprocessableEntity {
+			t.Fatalf("unexpected status code: got %d, want %d, body: %s",
+				resp.StatusCode, http.StatusUnprocessableEntity, doc.Text())
+		}
+		input := doc.Find(fmt.Sprintf(`[name=%q]`, name))
+		if class, _ := input.Attr("class"); !strings.Contains(class, "-error") {
+			t.Errorf("expected %s to be highlighted, got class %q", name, class)
+		}
+		if got, _ := input.Attr("value"); value != "" && got != value {
+			t.Errorf("expected %s to keep the value %q, got %q", name, value, got)
+		}
+		if input.Parent().Find(".text-error").Length() == 0 {
+			t.Errorf("expected a message for %s, body: %s", name, doc.Text())
+		}
+	}
+
+	resp, doc := send(t, "POST", "/view/data/progress", url.Values{
+		"lift":   {"Bench (BB)"},
+		"date":   {"2024-12-25"},
+		"weight": {"225"},
+		"sets":   {"3"},
+		"reps":   {"5"},
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d, body: %s", resp.StatusCode, http.StatusOK, doc.Text())
+	}
+	id := doc.Find(`input[name="date"]`).AttrOr("hx-patch", "")
+	if id == "" {
+		t.Fatalf("expected the new row to be patchable, body: %s", doc.Text())
+	}
+
+	t.Run("PATCH negative weight", func(t *testing.T) {
+		resp, doc := send(t, "PATCH", id, url.Values{"weight": {"-1"}})
+		invalid(t, resp, doc, "weight", "-1")
+	})
+
+	t.Run("PATCH bad date", func(t *testing.T) {
+		resp, doc := send(t, "PATCH", id, url.Values{"date": {"12/25/2024"}, "sets": {"4"}})
+		invalid(t, resp, doc, "date", "12/25/2024")
+		// Valid fields of an invalid patch are not applied either.
+		if got := doc.Find(`input[name="sets"]`).AttrOr("value", ""); got != "3" {
+			t.Errorf("expected sets to stay 3, got %q", got)
+		}
+	})
+
+	t.Run("POST missing lift", func(t *testing.T) {
+		resp, doc := send(t, "POST", "/view/data/progress", url.Values{
+			"lift":   {"No Such Lift"},
+			"date":   {"2024-12-25"},
+			"weight": {"225"},
+			"sets":   {"3"},
+			"reps":   {"5"},
+		})
+		if got := resp.Header.Get("HX-Reswap"); got != "outerHTML" {
+			t.Errorf("expected the new row to be replaced, got HX-Reswap %q", got)
+		}
+		invalid(t, resp, doc, "lift", "")
+		if got := doc.Find(`input[name="weight"]`).AttrOr("value", ""); got != "225" {
+			t.Errorf("expected weight to keep its value, got %q", got)
+		}
+	})
+
+	t.Run("POST bad routine steps", func(t *testing.T) {
+		resp, doc := send(t, "POST", "/view/data/routine", url.Values{
+			"id":    {"Bad Routine"},
+			"steps": {"5 at 65%"},
+			"lift":  {"Bench (BB)"},
+		})
+		invalid(t, resp, doc, "steps", "5 at 65%")
+	})
+
+	t.Run("POST progresstablerow negative reps", func(t *testing.T) {
+		resp, doc := send(t, "POST", "/view/progresstablerow", url.Values{
+			"lift":   {"Bench (BB)"},
+			"weight": {"225"},
+			"sets":   {"3"},
+			"reps":   {"-5"},
+		})
+		if got := resp.Header.Get("HX-Retarget"); got != "closest form" {
+			t.Errorf("expected the form to be retargeted, got HX-Retarget %q", got)
+		}
+		invalid(t, resp, doc, "reps", "-5")
+	})
+}
@@ -3,6 +3,7 @@ package integration
 import (
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -173,3 +174,176 @@ func TestIntegration_EmptyTables(t *testing.T) {
 		})
 	}
 }
+
+// TestIntegration_DataTableInputs tests the inputs of the data tables that
+// depend on their columns.
+func TestIntegration_DataTableInputs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	srv := testutil.Setup(t)
+	defer srv.Cancel()
+
+	get := func(t *testing.T, path string) (*http.Response, *goquery.Document) {
+		t.Helper()
+		resp := srv.Get(t, path)
+		defer func() { _ = resp.Body.Close() }()
+		doc, err := goquery.NewDocumentFromReader(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to parse HTML: %v", err)
+		}
+		return resp, doc
+	}
+	post := func(t *testing.T, path string, form url.Values) (*http.Response, *goquery.Document) {
+		t.Helper()
+		resp, err := http.PostForm("http://localhost:"+srv.GetPort(t)+path, form)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		doc, err := goquery.NewDocumentFromReader(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to parse HTML: %v", err)
+		}
+		return resp, doc
+	}
+
+	t.Run("typed inputs", func(t *testing.T) {
+		_, doc := get(t, "/view/data/progress")
+		if doc.Find(`input[name="date"][type="date"]`).Length() == 0 {
+			t.Error("expected progress dates to have date inputs")
+		}
+		lift := doc.Find(`input[name="lift"][list]`).First()
+		if lift.Length() == 0 {
+			t.Fatal("expected progress lifts to have typeahead inputs")
+		}
+		if doc.Find(`datalist[id="`+lift.AttrOr("list", "")+`"]`).AttrOr("hx-get", "") != "/view/options/progress/lift" {
+			t.Errorf("expected the lift datalist to get its options from /view/options/progress/lift")
+		}
+		if doc.Find("select").Length() > 1 {
+			t.Errorf("expected no selects of lifts, got %d selects", doc.Find("select").Length())
+		}
+
+		_, doc = get(t, "/view/data/lift")
+		if doc.Find(`input[name="link"][type="url"]`).Length() == 0 {
+			t.Error("expected lift links to have URL inputs")
+		}
+		if doc.Find(`a[target="_blank"]`).Length() == 0 {
+			t.Error("expected lift links to link to their URL")
+		}
+
+		_, doc = get(t, "/view/data/lift_muscle_mapping")
+		if doc.Find(`select[name="muscle"][multiple] option`).Length() == 0 {
+			t.Error("expected new mappings to have a multiselect of muscles")
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		resp, doc := get(t, "/view/options/progress/lift?lift=bench")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		var options []string
+		doc.Find("option").Each(func(_ int, s *goquery.Selection) {
+			options = append(options, s.AttrOr("value", ""))
+		})
+		if len(options) == 0 || len(options) > 20 {
+			t.Fatalf("expected 1 to 20 options, got %q", options)
+		}
+		if !strings.HasPrefix(options[0], "Bench") {
+			t.Errorf("expected options starting with the text first, got %q", options)
+		}
+		for _, option := range options {
+			if !strings.Contains(strings.ToLower(option), "bench") {
+				t.Errorf("expected options to contain the text, got %q", option)
+			}
+		}
+
+		if resp, _ := get(t, "/view/options/progress/date"); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("unexpected status code for a column without a source: got %d, want %d",
+				resp.StatusCode, http.StatusNotFound)
+		}
+	})
+
+	t.Run("multiselect", func(t *testing.T) {
+		resp, doc := post(t, "/view/data/lift_muscle_mapping", url.Values{
+			"lift":     {"Bench (Smith)"},
+			"muscle":   {"Abs", "Adductors"},
+			"movement": {"Target"},
+		})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d, body: %s", resp.StatusCode, http.StatusOK, doc.Text())
+		}
+		if got := doc.Find("button[hx-delete]").Length(); got != 2 {
+			t.Errorf("expected a row for each muscle, got %d rows", got)
+		}
+
+		resp, doc = post(t, "/view/data/lift_muscle_mapping", url.Values{
+			"lift":     {"Bench (Smith)"},
+			"muscle":   {"Back", "No Such Muscle"},
+			"movement": {"Target"},
+		})
+		if resp.StatusCode != http.StatusUnprocessableEntity {
+			t.Fatalf("unexpected status code: got %d, want %d, body: %s",
+				resp.StatusCode, http.StatusUnprocessableEntity, doc.Text())
+		}
+		if got := doc.Find(`select[name="muscle"] option[selected]`).Text(); got != "Back" {
+			t.Errorf("expected the chosen muscle to stay selected, got %q", got)
+		}
+		// The valid muscle is not added without the invalid one.
+		resp = srv.Get(t, "/api/data/lift_muscle_mapping/"+url.PathEscape("Bench (Smith)")+"/Back/Target")
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("unexpected status code of the valid muscle: got %d, want %d", resp.StatusCode, http.StatusNotFound)
+		}
+	})
+}
+
+// TestIntegration_MappingMatrix tests saving a mapping matrix.
+func TestIntegration_MappingMatrix(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	srv := testutil.Setup(t)
+	defer srv.Cancel()
+
+	resp := srv.Get(t, "/view/matrix/lift_workout_mapping?group=push")
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to parse HTML: %v", err)
+	}
+	form := url.Values{}
+	doc.Find(`#matrixform input[type="hidden"], #matrixform input[checked]`).Each(func(_ int, s *goquery.Selection) {
+		form.Add(s.AttrOr("name", ""), s.AttrOr("value", ""))
+	})
+	unchecked := doc.Find(`#matrixform input[type="checkbox"]:not([checked])`).First()
+	if unchecked.Length() == 0 {
+		t.Fatal("expected an unchecked cell")
+	}
+	form.Set(unchecked.AttrOr("name", ""), "1")
+
+	post := func(t *testing.T) (*http.Response, string) {
+		t.Helper()
+		resp, err := http.PostForm("http://localhost:"+srv.GetPort(t)+"/view/matrix/lift_workout_mapping", form)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+	resp, body := post(t)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, "Saved 1 changed mappings.") {
+		t.Fatalf("unexpected response: status %d, body: %s", resp.StatusCode, body)
+	}
+	// The matrix the form was based on has changed since.
+	if resp, body := post(t); resp.StatusCode != http.StatusConflict {
+		t.Errorf("unexpected status code: got %d, want %d, body: %s", resp.StatusCode, http.StatusConflict, body)
+	}
+}
@@ -38,6 +38,23 @@ func TestIntegration_Endpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Readiness", func(t *testing.T) {
+		resp := srv.Get(t, "/readyz")
+		defer func() { _ = resp.Body.Close() }()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read response body: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d, body: %s",
+				resp.StatusCode, http.StatusOK, string(body))
+		}
+		if !strings.Contains(string(body), `"status":"ok"`) {
+			t.Errorf("expected ready status, got %s", string(body))
+		}
+	})
+
 	t.Run("Metrics", func(t *testing.T) {
 		resp := srv.Get(t, "/metrics")
 		defer func() { _ = resp.Body.Close() }()
@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/RyRose/uplog/internal/sqlc/workoutdb"
+	"github.com/RyRose/uplog/test/testutil"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// TestIntegration_TrainingProgress tests that the training metrics only load
+// the most recent session of each lift and the progress since the start of the
+// volume window, lifting progress without a side weight as is.
+func TestIntegration_TrainingProgress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	srv := testutil.Setup(t)
+	defer srv.Cancel()
+
+	_, err := srv.GetWriteDB(t).Exec(`
+		INSERT INTO lift (id, link) VALUES ('train-a', ''), ('train-b', '');
+		INSERT INTO progress (lift, date, weight, sets, reps, side_weight) VALUES
+			('train-a', '2025-01-01', 100, 3, 5, NULL),
+			('train-a', '2025-03-05', 110, 3, 5, NULL),
+			('train-a', '2025-03-09', 120, 3, 5, 'x2+45'),
+			('train-b', '2025-01-01', 50, 3, 5, NULL),
+			('train-b', '2025-01-02', 60, 3, 5, NULL)`)
+	if err != nil {
+		t.Fatalf("failed to insert progress: %v", err)
+	}
+
+	rows, err := workoutdb.New(srv.GetReadDB(t)).ListTrainingProgress(context.Background(), "2025-03-04")
+	if err != nil {
+		t.Fatalf("failed to list training progress: %v", err)
+	}
+	var got []string
+	for _, row := range rows {
+		if strings.HasPrefix(row.Lift, "train-") {
+			got = append(got, fmt.Sprintf("%s %s %v", row.Lift, row.Date, row.Weight*row.Multiplier+row.Addend))
+		}
+	}
+	slices.Sort(got)
+	want := []string{
+		"train-a 2025-03-05 110",
+		"train-a 2025-03-09 285",
+		"train-b 2025-01-02 60",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ListTrainingProgress() = %q, want %q", got, want)
+	}
+}
+
+// TestIntegration_TrainingProgressForLift tests that the history of a lift
+// keeps progress without a side weight, lifted as is, even without an x1 side
+// weight.
+func TestIntegration_TrainingProgressForLift(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	srv := testutil.Setup(t)
+	defer srv.Cancel()
+
+	_, err := srv.GetWriteDB(t).Exec(`
+		DELETE FROM side_weight WHERE id = 'x1';
+		INSERT INTO lift (id, link) VALUES ('history-a', '');
+		INSERT INTO progress (lift, date, weight, sets, reps, side_weight) VALUES
+			('history-a', '2025-01-01', 100, 3, 5, NULL),
+			('history-a', '2025-01-02', 120, 3, 5, 'x2+45')`)
+	if err != nil {
+		t.Fatalf("failed to insert progress: %v", err)
+	}
+
+	rows, err := workoutdb.New(srv.GetReadDB(t)).ListTrainingProgressForLift(context.Background(), "history-a")
+	if err != nil {
+		t.Fatalf("failed to list training progress: %v", err)
+	}
+	var got []string
+	for _, row := range rows {
+		got = append(got, fmt.Sprintf("%s %v %v", row.Date, row.SideWeight, row.Weight*row.Multiplier+row.Addend))
+	}
+	want := []string{
+		"2025-01-02 x2+45 285",
+		"2025-01-01 <nil> 100",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ListTrainingProgressForLift() = %q, want %q", got, want)
+	}
+}
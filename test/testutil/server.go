@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/RyRose/uplog/internal/config"
 	"github.com/RyRose/uplog/internal/service"
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -37,14 +38,17 @@ func Setup(t *testing.T) *Server {
 		t.Fatalf("failed to get free port: %v", err)
 	}
 
-	_ = os.Setenv("PORT", fmt.Sprintf("%d", port))
-	_ = os.Setenv("DATABASE_PATH", dbPath)
-	_ = os.Setenv("DEBUG", "false")
-	t.Cleanup(func() {
-		_ = os.Unsetenv("PORT")
-		_ = os.Unsetenv("DATABASE_PATH")
-		_ = os.Unsetenv("DEBUG")
-	})
+	// Override values through flags rather than the process environment so
+	// tests don't leak settings into each other.
+	src := config.Source{
+		Path:    "./config/main.lua",
+		Profile: "test",
+		Overrides: map[string]string{
+			"port":          fmt.Sprintf("%d", port),
+			"database_path": dbPath,
+		},
+		Environ: []string{},
+	}
 
 	// Change to repo root so Lua's require() can find config modules
 	origDir, err := os.Getwd()
@@ -67,7 +71,7 @@ func Setup(t *testing.T) *Server {
 
 	errChan := make(chan error, 1)
 	go func() {
-		if err := service.Run(ctx, "./config/main.lua"); err != nil {
+		if err := service.Run(ctx, src); err != nil {
 			errChan <- err
 		}
 	}()